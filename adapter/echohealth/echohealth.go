@@ -0,0 +1,44 @@
+// Package echohealth exposes the endpoints of a healthcheck.Handler
+// as echo handlers.
+package echohealth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/pattern"
+)
+
+// Router is the part of *echo.Echo and *echo.Group the endpoints are registered on.
+type Router interface {
+	Any(path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) []*echo.Route
+}
+
+// LiveEndpoint returns an echo.HandlerFunc serving the liveness probe.
+func LiveEndpoint(h healthcheck.Prober) echo.HandlerFunc {
+	return echo.WrapHandler(http.HandlerFunc(h.LiveEndpoint))
+}
+
+// ReadyEndpoint returns an echo.HandlerFunc serving the readiness probe.
+func ReadyEndpoint(h healthcheck.Prober) echo.HandlerFunc {
+	return echo.WrapHandler(http.HandlerFunc(h.ReadyEndpoint))
+}
+
+// Handler returns an echo.HandlerFunc serving every endpoint of the handler.
+func Handler(h healthcheck.Prober) echo.HandlerFunc {
+	return echo.WrapHandler(h)
+}
+
+// RegisterEcho registers every health endpoint of the handler on the echo
+// instance (or group), with echo's ":name" syntax for the path parameters.
+//
+//	e := echo.New()
+//	echohealth.RegisterEcho(e, handler)
+func RegisterEcho(router Router, h healthcheck.Handler) {
+	handler := Handler(h)
+	h.RegisterRoutes(healthcheck.RouterFunc(func(path string, _ http.Handler) {
+		router.Any(pattern.Colon(path), handler)
+	}))
+}
@@ -0,0 +1,56 @@
+package echohealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestRegisterEcho(t *testing.T) {
+	h := healthcheck.NewHandler(healthcheck.WithAdminEndpoints(), healthcheck.WithAdminAuth(healthcheck.AdminAllowAll))
+	h.AddReadinessCheck("database", func() error { return errors.New("failed") })
+
+	e := echo.New()
+	RegisterEcho(e, h)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		expect int
+	}{
+		{
+			name:   "liveness",
+			method: http.MethodGet,
+			target: "/live",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "readiness",
+			method: http.MethodGet,
+			target: "/ready",
+			expect: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "admin route with a path parameter",
+			method: http.MethodPost,
+			target: "/health/admin/checks/database/disable",
+			expect: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			e.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, rr.Code)
+			}
+		})
+	}
+}
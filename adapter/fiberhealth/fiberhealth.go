@@ -0,0 +1,40 @@
+// Package fiberhealth exposes the endpoints of a healthcheck.Handler
+// as fiber handlers.
+package fiberhealth
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/pattern"
+)
+
+// LiveEndpoint returns a fiber.Handler serving the liveness probe.
+func LiveEndpoint(h healthcheck.Prober) fiber.Handler {
+	return adaptor.HTTPHandlerFunc(h.LiveEndpoint)
+}
+
+// ReadyEndpoint returns a fiber.Handler serving the readiness probe.
+func ReadyEndpoint(h healthcheck.Prober) fiber.Handler {
+	return adaptor.HTTPHandlerFunc(h.ReadyEndpoint)
+}
+
+// Handler returns a fiber.Handler serving every endpoint of the handler.
+func Handler(h healthcheck.Prober) fiber.Handler {
+	return adaptor.HTTPHandler(h)
+}
+
+// RegisterFiber registers every health endpoint of the handler on the fiber app
+// (or group), with fiber's ":name" syntax for the path parameters.
+//
+//	app := fiber.New()
+//	fiberhealth.RegisterFiber(app, handler)
+func RegisterFiber(router fiber.Router, h healthcheck.Handler) {
+	handler := Handler(h)
+	h.RegisterRoutes(healthcheck.RouterFunc(func(path string, _ http.Handler) {
+		router.All(pattern.Colon(path), handler)
+	}))
+}
@@ -0,0 +1,95 @@
+package fiberhealth
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestRegisterFiber(t *testing.T) {
+	h := healthcheck.NewHandler(
+		healthcheck.WithAdminEndpoints(),
+		healthcheck.WithAdminAuth(healthcheck.AdminAllowAll),
+		healthcheck.WithSuccessString("pass"),
+		healthcheck.WithLoadBalancerProbes(),
+	)
+	h.AddLivenessCheck("live", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("failed") })
+
+	app := fiber.New()
+	RegisterFiber(app, h)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		expect     int
+		expectBody string
+	}{
+		{
+			name:       "liveness",
+			method:     http.MethodGet,
+			target:     "/live",
+			expect:     http.StatusOK,
+			expectBody: "{}\n",
+		},
+		{
+			name:       "readiness full output",
+			method:     http.MethodGet,
+			target:     "/ready?full=1",
+			expect:     http.StatusServiceUnavailable,
+			expectBody: "{\n    \"database\": \"failed\",\n    \"live\": \"pass\"\n}\n",
+		},
+		{
+			name:       "readiness compact output",
+			method:     http.MethodGet,
+			target:     "/ready?full=1&compact=1",
+			expect:     http.StatusServiceUnavailable,
+			expectBody: "{\"database\":\"failed\",\"live\":\"pass\"}\n",
+		},
+		{
+			name:       "readiness text output",
+			method:     http.MethodGet,
+			target:     "/ready?format=text",
+			expect:     http.StatusServiceUnavailable,
+			expectBody: "readiness: fail\ndatabase\tfailed\tfailed\nlive\tok\tpass\n",
+		},
+		{
+			name:   "liveness head",
+			method: http.MethodHead,
+			target: "/live",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "admin route with a path parameter",
+			method: http.MethodPost,
+			target: "/health/admin/checks/live/disable",
+			expect: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.target, nil))
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, resp.StatusCode)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if tt.expectBody != "" && string(body) != tt.expectBody {
+				t.Errorf("Wrong body\n"+"expected: %q\n"+"actual  : %q", tt.expectBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/catalystgo/healthcheck/adapter/fiberhealth
+
+go 1.22
+
+require (
+	github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+	github.com/gofiber/fiber/v2 v2.52.5
+)
+
+require (
+	github.com/andybalholm/brotli v1.0.5 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasthttp v1.51.0 // indirect
+	github.com/valyala/tcplisten v1.0.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+)
+
+replace github.com/catalystgo/healthcheck => ../..
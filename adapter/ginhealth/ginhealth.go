@@ -0,0 +1,39 @@
+// Package ginhealth exposes the endpoints of a healthcheck.Handler
+// as gin handlers.
+package ginhealth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/pattern"
+)
+
+// LiveEndpoint returns a gin.HandlerFunc serving the liveness probe.
+func LiveEndpoint(h healthcheck.Prober) gin.HandlerFunc {
+	return gin.WrapF(h.LiveEndpoint)
+}
+
+// ReadyEndpoint returns a gin.HandlerFunc serving the readiness probe.
+func ReadyEndpoint(h healthcheck.Prober) gin.HandlerFunc {
+	return gin.WrapF(h.ReadyEndpoint)
+}
+
+// Handler returns a gin.HandlerFunc serving every endpoint of the handler.
+func Handler(h healthcheck.Prober) gin.HandlerFunc {
+	return gin.WrapH(h)
+}
+
+// RegisterGin registers every health endpoint of the handler on the gin engine
+// (or group), with gin's ":name" syntax for the path parameters.
+//
+//	router := gin.New()
+//	ginhealth.RegisterGin(router, handler)
+func RegisterGin(router gin.IRoutes, h healthcheck.Handler) {
+	handler := Handler(h)
+	h.RegisterRoutes(healthcheck.RouterFunc(func(path string, _ http.Handler) {
+		router.Any(pattern.Colon(path), handler)
+	}))
+}
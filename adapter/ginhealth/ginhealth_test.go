@@ -0,0 +1,75 @@
+package ginhealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestRegisterGin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := healthcheck.NewHandler(healthcheck.WithAdminEndpoints(), healthcheck.WithAdminAuth(healthcheck.AdminAllowAll))
+	h.AddReadinessCheck("database", func() error { return errors.New("failed") })
+
+	router := gin.New()
+	RegisterGin(router, h)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		expect int
+	}{
+		{
+			name:   "liveness",
+			method: http.MethodGet,
+			target: "/live",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "readiness",
+			method: http.MethodGet,
+			target: "/ready",
+			expect: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "admin route with a path parameter",
+			method: http.MethodPost,
+			target: "/health/admin/checks/database/disable",
+			expect: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, rr.Code)
+			}
+		})
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := healthcheck.NewHandler()
+	router := gin.New()
+	router.GET("/healthz", LiveEndpoint(h))
+	router.GET("/readyz", ReadyEndpoint(h))
+
+	for _, target := range []string{"/healthz", "/readyz"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+		}
+	}
+}
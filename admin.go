@@ -0,0 +1,346 @@
+package healthcheck
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AdminHandlerPath is the prefix of the admin endpoints.
+const AdminHandlerPath = "/health/admin"
+
+var (
+	// ErrCheckNotFound is returned when there's no check with the given name.
+	ErrCheckNotFound = errors.New("check not found")
+	// ErrCheckDisabled is the result of a disabled check.
+	ErrCheckDisabled = errors.New("disabled")
+)
+
+// AdminAuthFunc reports whether the request is allowed to use the admin endpoints.
+type AdminAuthFunc func(r *http.Request) bool
+
+// WithAdminEndpoints registers the admin endpoints giving operational control
+// over the handler without redeploys:
+//
+//	GET    /health/admin/config                 current configuration of the checks
+//	POST   /health/admin/checks/{name}/enable   enable a disabled check
+//	POST   /health/admin/checks/{name}/disable  disable a check
+//	POST   /health/admin/readiness/ready        force readiness to pass
+//	POST   /health/admin/readiness/unready      force readiness to fail
+//	DELETE /health/admin/readiness              remove the readiness override
+//	POST   /health/admin/evaluate               re-evaluate all checks immediately
+//
+// The endpoints require WithAdminAuth as well: without an auth hook, every
+// request is rejected with 403 Forbidden. Use AdminAllowAll to expose them
+// without authentication, e.g. on an internal port only.
+func WithAdminEndpoints() Option {
+	return func(h *basicHandler) {
+		h.adminEndpoints = true
+	}
+}
+
+// WithAdminAuth protects the admin endpoints with the auth hook:
+// requests it rejects get 403 Forbidden.
+func WithAdminAuth(auth AdminAuthFunc) Option {
+	return func(h *basicHandler) {
+		h.adminAuth = auth
+	}
+}
+
+// AdminAllowAll is an auth hook accepting all the requests, to opt out of the
+// authentication of the admin endpoints explicitly.
+func AdminAllowAll(*http.Request) bool {
+	return true
+}
+
+// AdminBasicAuth returns an auth hook accepting requests
+// with the given HTTP basic auth credentials.
+func AdminBasicAuth(username, password string) AdminAuthFunc {
+	return func(r *http.Request) bool {
+		user, pass, ok := r.BasicAuth()
+		return ok &&
+			subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+	}
+}
+
+// AdminBearerToken returns an auth hook accepting requests
+// with the "Authorization: Bearer <token>" header.
+func AdminBearerToken(token string) AdminAuthFunc {
+	return func(r *http.Request) bool {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+}
+
+func (s *basicHandler) EnableCheck(name string) error {
+	if err := s.setCheckDisabled(name, false); err != nil {
+		return err
+	}
+	s.resetPanics(name)
+	return nil
+}
+
+func (s *basicHandler) DisableCheck(name string) error {
+	return s.setCheckDisabled(name, true)
+}
+
+func (s *basicHandler) setCheckDisabled(name string, disabled bool) error {
+	s.checksMutex.Lock()
+	defer s.checksMutex.Unlock()
+
+	_, live := s.livenessChecks[name]
+	_, ready := s.readinessChecks[name]
+	if !live && !ready {
+		return ErrCheckNotFound
+	}
+
+	if disabled {
+		s.disabledChecks[name] = true
+	} else {
+		delete(s.disabledChecks, name)
+	}
+	return nil
+}
+
+func (s *basicHandler) checkDisabled(name string) bool {
+	s.checksMutex.RLock()
+	defer s.checksMutex.RUnlock()
+	return s.disabledChecks[name]
+}
+
+// checkFailed reports whether the result of a check is a failure.
+// Disabled checks and checks in maintenance never fail.
+func checkFailed(err error) bool {
+	return err != nil && !errors.Is(err, ErrCheckDisabled) && !errors.Is(err, ErrSuppressed)
+}
+
+// readinessOverride forces the readiness probe status regardless of the checks.
+type readinessOverride struct {
+	mu    sync.RWMutex
+	set   bool
+	ready bool
+}
+
+func (o *readinessOverride) get() (ready, set bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.ready, o.set
+}
+
+func (o *readinessOverride) update(ready, set bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ready, o.set = ready, set
+}
+
+// apply returns the status of the readiness probe with the override applied.
+func (o *readinessOverride) apply(status int) int {
+	ready, set := o.get()
+	switch {
+	case !set:
+		return status
+	case ready:
+		return http.StatusOK
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+// applyState returns the outcome of the readiness probe with the override applied.
+func (o *readinessOverride) applyState(state probeState) probeState {
+	ready, set := o.get()
+	switch {
+	case !set:
+		return state
+	case ready:
+		return probePass
+	default:
+		return probeFail
+	}
+}
+
+func (s *basicHandler) OverrideReadiness(ready bool) {
+	s.readinessOverride.update(ready, true)
+}
+
+func (s *basicHandler) ResetReadinessOverride() {
+	s.readinessOverride.update(false, false)
+}
+
+// refreshBackground executes the background checks immediately
+// and updates their last results.
+func (s *basicHandler) refreshBackground(checks []*checkEntry) {
+	var wg sync.WaitGroup
+	for _, entry := range checks {
+		if entry.background == nil || s.checkDisabled(entry.name) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(entry *checkEntry) {
+			defer wg.Done()
+			entry.background.setResult(s.runCheck(entry), s.clock.Now())
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// adminConfig is the configuration exposed by the admin config endpoint.
+type adminConfig struct {
+	Checks            []adminCheckConfig `json:"checks"`
+	ReadinessOverride string             `json:"readiness_override,omitempty"`
+	ScoreThreshold    float64            `json:"score_threshold"`
+	PanicLimit        int                `json:"panic_limit"`
+}
+
+type adminCheckConfig struct {
+	Name         string   `json:"name"`
+	Probes       []string `json:"probes"`
+	Tags         []string `json:"tags,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	Weight       float64  `json:"weight"`
+	Critical     bool     `json:"critical"`
+	Criticality  string   `json:"criticality"`
+	ReportOnly   bool     `json:"report_only"`
+	Background   bool     `json:"background"`
+	Disabled     bool     `json:"disabled"`
+	Panics       int      `json:"panics"`
+}
+
+func (s *basicHandler) adminConfig() adminConfig {
+	s.checksMutex.RLock()
+	defer s.checksMutex.RUnlock()
+
+	var (
+		cfg    = adminConfig{Checks: []adminCheckConfig{}}
+		checks = make(map[string]int)
+	)
+	for _, probe := range []struct {
+		name   string
+		checks map[string]*checkEntry
+	}{
+		{name: "liveness", checks: s.livenessChecks},
+		{name: "readiness", checks: s.readinessChecks},
+	} {
+		for name, entry := range probe.checks {
+			if i, ok := checks[name]; ok {
+				cfg.Checks[i].Probes = append(cfg.Checks[i].Probes, probe.name)
+				continue
+			}
+
+			checks[name] = len(cfg.Checks)
+			cfg.Checks = append(cfg.Checks, adminCheckConfig{
+				Name:         name,
+				Probes:       []string{probe.name},
+				Tags:         entry.config.tags,
+				Dependencies: entry.config.dependencies,
+				Weight:       entry.config.weightOrDefault(),
+				Critical:     entry.config.critical(),
+				Criticality:  entry.config.criticality.String(),
+				ReportOnly:   entry.config.reportOnly,
+				Background:   entry.background != nil,
+				Disabled:     s.disabledChecks[name],
+				Panics:       s.panicCount(name),
+			})
+		}
+	}
+	sort.Slice(cfg.Checks, func(i, j int) bool { return cfg.Checks[i].Name < cfg.Checks[j].Name })
+
+	if ready, set := s.readinessOverride.get(); set {
+		cfg.ReadinessOverride = "unready"
+		if ready {
+			cfg.ReadinessOverride = "ready"
+		}
+	}
+	cfg.ScoreThreshold = s.scoreThreshold
+	cfg.PanicLimit = s.panicLimit
+
+	return cfg
+}
+
+// registerAdminEndpoints registers the admin endpoints on the mux if they're enabled.
+func (s *basicHandler) registerAdminEndpoints() {
+	if !s.adminEndpoints {
+		return
+	}
+
+	s.route("GET "+AdminHandlerPath+"/config",
+		adminOperation(jsonOperation("Configuration of the checks", "Config")),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, s.adminConfig())
+		}))
+	s.route("POST "+AdminHandlerPath+"/checks/{name}/enable",
+		adminOperation(actionOperation("Enable a disabled check", http.StatusNoContent, http.StatusNotFound)),
+		s.adminOnly(func(w http.ResponseWriter, r *http.Request) {
+			writeAdminResult(w, s.EnableCheck(r.PathValue("name")))
+		}))
+	s.route("POST "+AdminHandlerPath+"/checks/{name}/disable",
+		adminOperation(actionOperation("Disable a check", http.StatusNoContent, http.StatusNotFound)),
+		s.adminOnly(func(w http.ResponseWriter, r *http.Request) {
+			writeAdminResult(w, s.DisableCheck(r.PathValue("name")))
+		}))
+	s.route("POST "+AdminHandlerPath+"/readiness/ready",
+		adminOperation(actionOperation("Force the readiness to pass", http.StatusNoContent)),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.OverrideReadiness(true)
+			writeAdminResult(w, nil)
+		}))
+	s.route("POST "+AdminHandlerPath+"/readiness/unready",
+		adminOperation(actionOperation("Force the readiness to fail", http.StatusNoContent)),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.OverrideReadiness(false)
+			writeAdminResult(w, nil)
+		}))
+	s.route("DELETE "+AdminHandlerPath+"/readiness",
+		adminOperation(actionOperation("Remove the readiness override", http.StatusNoContent)),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.ResetReadinessOverride()
+			writeAdminResult(w, nil)
+		}))
+	s.route("POST "+AdminHandlerPath+"/evaluate",
+		adminOperation(jsonOperation("Re-evaluate all the checks", "CheckResults", http.StatusServiceUnavailable)),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.refreshBackground(s.entries(s.readinessProbe...))
+			status, results := s.evaluate(true, s.readinessProbe...)
+			writeJSON(w, s.readinessOverride.apply(status), s.resultOutputs(results))
+		}))
+}
+
+// adminOnly rejects the requests the admin auth hook doesn't allow,
+// all of them if there's no hook.
+func (s *basicHandler) adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.adminAuth == nil || !s.adminAuth(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeAdminResult(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrCheckNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeJSON writes the value as an indented JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,209 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminCheckToggle(t *testing.T) {
+	var calls int
+
+	h := NewHandler(WithAdminEndpoints(), WithAdminAuth(AdminAllowAll))
+	h.AddReadinessCheck("flaky", func() error {
+		calls++
+		return errors.New("failed flaky check")
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		expect     int
+		expectBody string
+	}{
+		{
+			name:   "failing check fails readiness",
+			method: http.MethodGet,
+			path:   ReadinessHandlerPath,
+			expect: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "disable unknown check",
+			method: http.MethodPost,
+			path:   AdminHandlerPath + "/checks/unknown/disable",
+			expect: http.StatusNotFound,
+		},
+		{
+			name:   "disable check",
+			method: http.MethodPost,
+			path:   AdminHandlerPath + "/checks/flaky/disable",
+			expect: http.StatusNoContent,
+		},
+		{
+			name:       "disabled check doesn't affect readiness",
+			method:     http.MethodGet,
+			path:       ReadinessHandlerPath + "?full=1",
+			expect:     http.StatusOK,
+			expectBody: "{\n    \"flaky\": \"disabled\"\n}\n",
+		},
+		{
+			name:   "enable check",
+			method: http.MethodPost,
+			path:   AdminHandlerPath + "/checks/flaky/enable",
+			expect: http.StatusNoContent,
+		},
+		{
+			name:   "enabled check fails readiness again",
+			method: http.MethodGet,
+			path:   ReadinessHandlerPath,
+			expect: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, step := range steps {
+		rr := serve(step.method, step.path)
+		if rr.Code != step.expect {
+			t.Errorf("%s: wrong code\n"+
+				"expected: %v\n"+
+				"actual  : %v", step.name, step.expect, rr.Code)
+		}
+		if step.expectBody != "" && rr.Body.String() != step.expectBody {
+			t.Errorf("%s: wrong body\n"+
+				"expected: %v"+
+				"actual  : %v", step.name, step.expectBody, rr.Body.String())
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Disabled check must not be executed, got %d calls", calls)
+	}
+}
+
+func TestAdminAuthAndOverride(t *testing.T) {
+	h := NewHandler(WithAdminEndpoints(), WithAdminAuth(AdminBearerToken("secret")))
+	h.AddReadinessCheck("ready", func() error { return nil })
+
+	serve := func(method, path, token string) int {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		expect int
+	}{
+		{
+			name:   "config without token",
+			method: http.MethodGet,
+			path:   AdminHandlerPath + "/config",
+			expect: http.StatusForbidden,
+		},
+		{
+			name:   "config with a wrong token",
+			method: http.MethodGet,
+			path:   AdminHandlerPath + "/config",
+			token:  "wrong",
+			expect: http.StatusForbidden,
+		},
+		{
+			name:   "config",
+			method: http.MethodGet,
+			path:   AdminHandlerPath + "/config",
+			token:  "secret",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "force unready",
+			method: http.MethodPost,
+			path:   AdminHandlerPath + "/readiness/unready",
+			token:  "secret",
+			expect: http.StatusNoContent,
+		},
+		{
+			name:   "forced unready",
+			method: http.MethodGet,
+			path:   ReadinessHandlerPath,
+			expect: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "liveness isn't affected",
+			method: http.MethodGet,
+			path:   LivenessHandlerPath,
+			expect: http.StatusOK,
+		},
+		{
+			name:   "evaluate",
+			method: http.MethodPost,
+			path:   AdminHandlerPath + "/evaluate",
+			token:  "secret",
+			expect: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "reset override",
+			method: http.MethodDelete,
+			path:   AdminHandlerPath + "/readiness",
+			token:  "secret",
+			expect: http.StatusNoContent,
+		},
+		{
+			name:   "ready again",
+			method: http.MethodGet,
+			path:   ReadinessHandlerPath,
+			expect: http.StatusOK,
+		},
+	}
+
+	for _, step := range steps {
+		if code := serve(step.method, step.path, step.token); code != step.expect {
+			t.Errorf("%s: wrong code\n"+
+				"expected: %v\n"+
+				"actual  : %v", step.name, step.expect, code)
+		}
+	}
+}
+
+func TestAdminWithoutAuth(t *testing.T) {
+	h := NewHandler(WithAdminEndpoints(), WithEnvoyEndpoints())
+	h.AddReadinessCheck("ready", func() error { return nil })
+
+	for _, target := range []string{
+		AdminHandlerPath + "/readiness/unready",
+		AdminHandlerPath + "/checks/ready/disable",
+		EnvoyFailHandlerPath,
+	} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, target, nil))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Wrong code of %s\n"+"expected: %v\n"+"actual  : %v", target, http.StatusForbidden, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Wrong readiness code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+	}
+}
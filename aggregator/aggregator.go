@@ -0,0 +1,181 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+const (
+	// maxBodySize limits the size of an upstream response body read by a check.
+	maxBodySize = 1 << 20
+	// defaultSuccessString is the result of a passed check in the full output
+	// of a handler without healthcheck.WithSuccessString.
+	defaultSuccessString = "OK"
+)
+
+// Upstream is a remote service whose health endpoint is polled.
+type Upstream struct {
+	// Name is the name of the check exposing the upstream status.
+	Name string
+	// URL is the health endpoint of the upstream, e.g. "http://users:8080/ready?full=1".
+	// Both this package's JSON output and application/health+json are understood.
+	URL string
+	// Optional marks the upstream as non-critical (see healthcheck.NonCritical).
+	Optional bool
+	// Client is the HTTP client polling the upstream, http.DefaultClient if nil.
+	Client *http.Client
+	// SuccessString is the result of a passed check in the full output of the
+	// upstream, "OK" if empty. Set it if the upstream uses healthcheck.WithSuccessString.
+	SuccessString string
+}
+
+// Register adds a readiness check for each upstream to the handler. Upstreams are
+// polled in background every interval, so probes of this handler don't fan out
+// to the upstreams; every poll times out after timeout.
+func Register(r healthcheck.CheckRegistrar, upstreams []Upstream, interval, timeout time.Duration, opts ...healthcheck.CheckOption) {
+	for _, upstream := range upstreams {
+		checkOpts := append([]healthcheck.CheckOption{
+			healthcheck.WithSchedule(healthcheck.Every(interval)),
+			healthcheck.WithTags("upstream"),
+		}, opts...)
+		if upstream.Optional {
+			checkOpts = append(checkOpts, healthcheck.NonCritical())
+		}
+
+		r.AddReadinessCheck(upstream.Name, CheckUpstream(upstream, timeout), checkOpts...)
+	}
+}
+
+// Check returns a Check requesting the upstream health endpoint. The check
+// fails if the request fails, times out or returns any code but 200 OK,
+// or if the returned application/health+json status is "fail".
+// The failed upstream checks are listed in the error when the body has them.
+func Check(url string, timeout time.Duration) healthcheck.Check {
+	return CheckUpstream(Upstream{URL: url}, timeout)
+}
+
+// CheckWithClient is Check with the HTTP client sending the requests,
+// e.g. with an explicit proxy (see misc.HTTPOptions) or mTLS.
+func CheckWithClient(client *http.Client, url string, timeout time.Duration) healthcheck.Check {
+	return CheckUpstream(Upstream{URL: url, Client: client}, timeout)
+}
+
+// CheckUpstream is Check with the client and the success string of the upstream.
+func CheckUpstream(upstream Upstream, timeout time.Duration) healthcheck.Check {
+	client := upstream.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	successString := upstream.SuccessString
+	if successString == "" {
+		successString = defaultSuccessString
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/health+json, application/json;q=0.9")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return err
+		}
+
+		status, failed := parseBody(body, successString)
+		if resp.StatusCode == http.StatusOK && status != healthcheck.StatusFail {
+			return nil
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("returned status %d, failed checks: %s", resp.StatusCode, strings.Join(failed, ", "))
+		}
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+}
+
+// parseBody extracts the overall status and the names of the failed checks
+// from an application/health+json body or this package's full JSON output,
+// where the result of a check is either a string (successString if it passed)
+// or an object with its kind.
+func parseBody(body []byte, successString string) (status healthcheck.Status, failed []string) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", nil
+	}
+
+	if health, ok := parseHealthJSON(body, fields); ok {
+		for name, components := range health.Checks {
+			for _, component := range components {
+				if component.Status == healthcheck.StatusFail {
+					failed = append(failed, name)
+					break
+				}
+			}
+		}
+		sort.Strings(failed)
+		return health.Status, failed
+	}
+
+	for name, raw := range fields {
+		var result string
+		if err := json.Unmarshal(raw, &result); err == nil {
+			if result != successString {
+				failed = append(failed, name)
+			}
+			continue
+		}
+
+		var detailed struct {
+			Kind healthcheck.ResultKind `json:"kind"`
+		}
+		if err := json.Unmarshal(raw, &detailed); err == nil && resultFailed(detailed.Kind) {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return "", failed
+}
+
+// parseHealthJSON decodes an application/health+json body: an object with
+// a string status and the checks object, which the full output can't be.
+func parseHealthJSON(body []byte, fields map[string]json.RawMessage) (healthcheck.HealthJSON, bool) {
+	var health healthcheck.HealthJSON
+
+	checks, ok := fields["checks"]
+	if !ok || len(checks) == 0 || checks[0] != '{' {
+		return health, false
+	}
+	if err := json.Unmarshal(body, &health); err != nil || health.Status == "" {
+		return health, false
+	}
+	return health, true
+}
+
+// resultFailed reports whether the kind of a check result in the full output is a failure,
+// the skipped and suppressed checks don't fail the probes.
+func resultFailed(kind healthcheck.ResultKind) bool {
+	switch kind {
+	case "", healthcheck.KindOK, healthcheck.KindSkipped, healthcheck.KindSuppressed:
+		return false
+	}
+	return true
+}
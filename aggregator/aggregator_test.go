@@ -0,0 +1,214 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		upstream  Upstream
+		status    int
+		body      string
+		expectErr string
+	}{
+		{
+			name:   "ready upstream",
+			status: http.StatusOK,
+			body:   "{}\n",
+		},
+		{
+			name:      "unready upstream with an empty body",
+			status:    http.StatusServiceUnavailable,
+			body:      "{}\n",
+			expectErr: "returned status 503",
+		},
+		{
+			name:      "unready upstream with the full output",
+			status:    http.StatusServiceUnavailable,
+			body:      `{"db": "connection refused", "cache": "OK", "kafka": "timeout"}`,
+			expectErr: "returned status 503, failed checks: db, kafka",
+		},
+		{
+			name:      "unready upstream with detailed results",
+			status:    http.StatusServiceUnavailable,
+			body:      `{"db": {"status": "connection refused", "kind": "failed"}, "cache": {"status": "OK", "kind": "ok", "code": "hit"}, "kafka": {"status": "disabled", "kind": "skipped"}}`,
+			expectErr: "returned status 503, failed checks: db",
+		},
+		{
+			name:      "unready upstream with a custom success string",
+			upstream:  Upstream{SuccessString: "pass"},
+			status:    http.StatusServiceUnavailable,
+			body:      `{"db": "connection refused", "cache": "pass"}`,
+			expectErr: "returned status 503, failed checks: db",
+		},
+		{
+			name:      "unready upstream with checks named as health+json fields",
+			status:    http.StatusServiceUnavailable,
+			body:      `{"status": "OK", "checks": {"status": "timeout", "kind": "timeout"}}`,
+			expectErr: "returned status 503, failed checks: checks",
+		},
+		{
+			name:   "health+json warning",
+			status: http.StatusOK,
+			body:   `{"status": "warn", "checks": {"disk": [{"status": "warn"}]}}`,
+		},
+		{
+			name:      "health+json failure",
+			status:    http.StatusOK,
+			body:      `{"status": "fail", "checks": {"db": [{"status": "fail"}], "disk": [{"status": "pass"}]}}`,
+			expectErr: "returned status 200, failed checks: db",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			upstream := tt.upstream
+			upstream.URL = srv.URL
+			err := CheckUpstream(upstream, time.Second)()
+			switch {
+			case tt.expectErr == "" && err != nil:
+				t.Errorf("Received unexpected error:\n%+v", err)
+			case tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr):
+				t.Errorf("Wrong error\n"+
+					"expected: %v\n"+
+					"actual  : %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestFleet(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("{}\n"))
+	}))
+	defer healthy.Close()
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	addr := func(srv *httptest.Server) string { return srv.Listener.Addr().String() }
+
+	tests := []struct {
+		name    string
+		addrs   []string
+		healthy int
+		expect  int
+	}{
+		{
+			name:    "quorum",
+			addrs:   []string{addr(healthy), addr(healthy), addr(unhealthy)},
+			healthy: 2,
+			expect:  http.StatusOK,
+		},
+		{
+			name:    "no quorum",
+			addrs:   []string{addr(healthy), addr(unhealthy)},
+			healthy: 1,
+			expect:  http.StatusServiceUnavailable,
+		},
+		{
+			name:   "no instances",
+			expect: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			fleet := NewFleet(Static(tt.addrs...), WithHealthPath("/"))
+			fleet.Poll(context.Background())
+
+			status := fleet.Status()
+			if status.Healthy != tt.healthy || status.Total != len(tt.addrs) {
+				t.Errorf("Wrong fleet status: %d of %d healthy", status.Healthy, status.Total)
+			}
+
+			rr := httptest.NewRecorder()
+			fleet.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+
+					"expected: %v\n"+
+					"actual  : %v", tt.expect, rr.Code)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var registrar fakeRegistrar
+	Register(&registrar, []Upstream{{Name: "users", URL: srv.URL}}, time.Minute, time.Second)
+
+	check, ok := registrar.readiness["users"]
+	if !ok {
+		t.Fatalf("Missing readiness check %q", "users")
+	}
+	if err := check(); err == nil {
+		t.Errorf("Expected an error of the unready upstream")
+	}
+}
+
+// fakeRegistrar records the registered readiness checks.
+type fakeRegistrar struct {
+	healthcheck.CheckRegistrar
+
+	readiness map[string]healthcheck.Check
+}
+
+func (r *fakeRegistrar) AddReadinessCheck(name string, check healthcheck.Check, _ ...healthcheck.CheckOption) {
+	if r.readiness == nil {
+		r.readiness = make(map[string]healthcheck.Check)
+	}
+	r.readiness[name] = check
+}
+
+// roundTripperFunc is an http.RoundTripper calling itself.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFleetHTTPClient(t *testing.T) {
+	var polled []string
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		polled = append(polled, req.URL.String())
+		return nil, errors.New("connection refused")
+	})}
+
+	fleet := NewFleet(Static("10.0.0.1:8080"), WithHTTPClient(client), WithScheme("https"))
+	fleet.Poll(context.Background())
+
+	expect := "https://10.0.0.1:8080" + healthcheck.ReadinessHandlerPath
+	if len(polled) != 1 || polled[0] != expect {
+		t.Errorf("Wrong polled URLs\n"+"expected: %v\n"+"actual  : %v", []string{expect}, polled)
+	}
+	if status := fleet.Status(); status.Healthy != 0 || status.Total != 1 {
+		t.Errorf("Wrong fleet status: %d of %d healthy", status.Healthy, status.Total)
+	}
+}
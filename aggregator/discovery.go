@@ -0,0 +1,165 @@
+package aggregator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// serviceAccountDir holds the credentials of the pod service account.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Discoverer returns the addresses ("host:port") of the instances of a service.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]string, error)
+}
+
+// DiscovererFunc is an adapter allowing the use of an ordinary function as a Discoverer.
+type DiscovererFunc func(ctx context.Context) ([]string, error)
+
+// Discover calls f(ctx).
+func (f DiscovererFunc) Discover(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// Static returns a Discoverer always returning the given addresses.
+func Static(addrs ...string) Discoverer {
+	return DiscovererFunc(func(context.Context) ([]string, error) {
+		return addrs, nil
+	})
+}
+
+// DNSSRV returns a Discoverer resolving the instances from DNS SRV records
+// of _service._proto.name (e.g. "http", "tcp", "users.default.svc.cluster.local").
+func DNSSRV(service, proto, name string) Discoverer {
+	return DiscovererFunc(func(ctx context.Context) ([]string, error) {
+		_, records, err := net.DefaultResolver.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs := make([]string, 0, len(records))
+		for _, record := range records {
+			host := strings.TrimSuffix(record.Target, ".")
+			addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+		}
+		return addrs, nil
+	})
+}
+
+// KubernetesEndpoints returns a Discoverer listing the instances from the Endpoints
+// object of the service using the in-cluster service account (which needs the
+// permission to get endpoints). port is the name of the endpoint port, the first
+// port is used if it's empty. Both ready and not ready addresses are returned.
+//
+// The client of the API server is created along with the Discoverer and reused by
+// every Discover, the token is read on every Discover since it's rotated.
+func KubernetesEndpoints(namespace, service, port string) Discoverer {
+	client, clientErr := kubernetesClient()
+
+	return DiscovererFunc(func(ctx context.Context) ([]string, error) {
+		host, apiPort := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || apiPort == "" {
+			return nil, errors.New("not running in a kubernetes cluster")
+		}
+		if clientErr != nil {
+			return nil, clientErr
+		}
+
+		token, err := os.ReadFile(filepath.Join(serviceAccountDir, "token"))
+		if err != nil {
+			return nil, err
+		}
+
+		url := fmt.Sprintf("https://%s/api/v1/namespaces/%s/endpoints/%s", net.JoinHostPort(host, apiPort), namespace, service)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("kubernetes api returned status %d", resp.StatusCode)
+		}
+
+		var endpoints kubernetesEndpoints
+		if err = json.NewDecoder(resp.Body).Decode(&endpoints); err != nil {
+			return nil, err
+		}
+		return endpoints.addresses(port), nil
+	})
+}
+
+// kubernetesClient returns an HTTP client trusting the cluster CA.
+func kubernetesClient() (*http.Client, error) {
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid kubernetes ca certificate")
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}
+	return client, nil
+}
+
+// kubernetesEndpoints is the subset of the core/v1 Endpoints object used by the discovery.
+type kubernetesEndpoints struct {
+	Subsets []struct {
+		Addresses         []kubernetesAddress `json:"addresses"`
+		NotReadyAddresses []kubernetesAddress `json:"notReadyAddresses"`
+		Ports             []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+type kubernetesAddress struct {
+	IP string `json:"ip"`
+}
+
+func (e kubernetesEndpoints) addresses(portName string) []string {
+	var addrs []string
+	for _, subset := range e.Subsets {
+		port := 0
+		for _, p := range subset.Ports {
+			if portName == "" || p.Name == portName {
+				port = p.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+
+		for _, list := range [][]kubernetesAddress{subset.Addresses, subset.NotReadyAddresses} {
+			for _, addr := range list {
+				addrs = append(addrs, net.JoinHostPort(addr.IP, strconv.Itoa(port)))
+			}
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,256 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+const (
+	// DefaultFleetInterval is the default interval between polls of the fleet.
+	DefaultFleetInterval = 10 * time.Second
+	// DefaultFleetTimeout is the default timeout of polling a single instance.
+	DefaultFleetTimeout = 2 * time.Second
+	// DefaultQuorum is the default share of instances that must be healthy.
+	DefaultQuorum = 0.5
+)
+
+// Fleet periodically discovers the instances of a service, polls their health
+// endpoints and serves the merged view: the status of every instance and whether
+// a quorum of them is healthy. It's an http.Handler responding with 200 OK when
+// the quorum is reached and 503 Service Unavailable otherwise.
+type Fleet struct {
+	discoverer Discoverer
+	scheme     string
+	path       string
+	interval   time.Duration
+	timeout    time.Duration
+	quorum     float64
+	client     *http.Client
+	success    string
+
+	mu     sync.RWMutex
+	status FleetStatus
+}
+
+// FleetOption configures a Fleet.
+type FleetOption func(f *Fleet)
+
+// WithHealthPath sets the health endpoint path polled on every instance, "/ready" by default.
+func WithHealthPath(path string) FleetOption {
+	return func(f *Fleet) {
+		f.path = path
+	}
+}
+
+// WithScheme sets the scheme used to poll the instances, "http" by default.
+func WithScheme(scheme string) FleetOption {
+	return func(f *Fleet) {
+		f.scheme = scheme
+	}
+}
+
+// WithPollInterval sets the interval between polls, DefaultFleetInterval by default.
+func WithPollInterval(interval time.Duration) FleetOption {
+	return func(f *Fleet) {
+		f.interval = interval
+	}
+}
+
+// WithPollTimeout sets the timeout of polling a single instance, DefaultFleetTimeout by default.
+func WithPollTimeout(timeout time.Duration) FleetOption {
+	return func(f *Fleet) {
+		f.timeout = timeout
+	}
+}
+
+// WithQuorum sets the share of instances (0-1) that must be healthy for the quorum,
+// DefaultQuorum by default. The quorum is reached if more than that share is healthy.
+func WithQuorum(quorum float64) FleetOption {
+	return func(f *Fleet) {
+		f.quorum = quorum
+	}
+}
+
+// WithHTTPClient sets the HTTP client polling the instances, http.DefaultClient by default,
+// e.g. with mTLS or an explicit proxy.
+func WithHTTPClient(client *http.Client) FleetOption {
+	return func(f *Fleet) {
+		f.client = client
+	}
+}
+
+// WithSuccessString sets the result of a passed check in the full output
+// of the instances (see Upstream.SuccessString), "OK" by default.
+func WithSuccessString(success string) FleetOption {
+	return func(f *Fleet) {
+		f.success = success
+	}
+}
+
+// FleetStatus is the merged view of the fleet health.
+type FleetStatus struct {
+	Quorum    bool             `json:"quorum"`
+	Healthy   int              `json:"healthy"`
+	Total     int              `json:"total"`
+	Instances []InstanceStatus `json:"instances"`
+	Error     string           `json:"error,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// InstanceStatus is the health of a single instance of the fleet.
+type InstanceStatus struct {
+	Address string `json:"address"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewFleet creates a Fleet discovering the instances with the discoverer.
+// Run must be called to start polling.
+func NewFleet(discoverer Discoverer, opts ...FleetOption) *Fleet {
+	f := &Fleet{
+		discoverer: discoverer,
+		scheme:     "http",
+		path:       healthcheck.ReadinessHandlerPath,
+		interval:   DefaultFleetInterval,
+		timeout:    DefaultFleetTimeout,
+		quorum:     DefaultQuorum,
+		status: FleetStatus{
+			Instances: []InstanceStatus{},
+			Error:     "fleet has not been polled yet",
+		},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Run polls the fleet every interval until the context is done.
+func (f *Fleet) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll discovers and polls the fleet once, updating its status.
+func (f *Fleet) Poll(ctx context.Context) {
+	status := FleetStatus{
+		Instances: []InstanceStatus{},
+		UpdatedAt: time.Now(),
+	}
+
+	addrs, err := f.discoverer.Discover(ctx)
+	if err != nil {
+		status.Error = fmt.Sprintf("discovery failed: %v", err)
+		f.setStatus(status)
+		return
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, addr := range addrs {
+		wg.Add(1)
+
+		go func(addr string) {
+			defer wg.Done()
+
+			instance := InstanceStatus{Address: addr, Healthy: true}
+			check := CheckUpstream(Upstream{
+				URL:           f.scheme + "://" + addr + f.path,
+				Client:        f.client,
+				SuccessString: f.success,
+			}, f.timeout)
+			if err := check(); err != nil {
+				instance.Healthy = false
+				instance.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			status.Instances = append(status.Instances, instance)
+		}(addr)
+	}
+	wg.Wait()
+
+	sort.Slice(status.Instances, func(i, j int) bool {
+		return status.Instances[i].Address < status.Instances[j].Address
+	})
+	for _, instance := range status.Instances {
+		if instance.Healthy {
+			status.Healthy++
+		}
+	}
+	status.Total = len(status.Instances)
+	status.Quorum = status.Total > 0 && float64(status.Healthy) > float64(status.Total)*f.quorum
+
+	f.setStatus(status)
+}
+
+// Status returns the last merged view of the fleet.
+func (f *Fleet) Status() FleetStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.status
+}
+
+func (f *Fleet) setStatus(status FleetStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+// Check returns a Check failing if the fleet has no quorum,
+// so the fleet status can be exposed on a healthcheck.Handler.
+func (f *Fleet) Check() healthcheck.Check {
+	return func() error {
+		status := f.Status()
+		if status.Quorum {
+			return nil
+		}
+		if status.Error != "" {
+			return errors.New(status.Error)
+		}
+		return fmt.Errorf("no quorum: %d of %d instances are healthy", status.Healthy, status.Total)
+	}
+}
+
+// ServeHTTP serves the merged view of the fleet as JSON.
+func (f *Fleet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := f.Status()
+	code := http.StatusOK
+	if !status.Quorum {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(code)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	_ = encoder.Encode(status)
+}
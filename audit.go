@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ProbeAudit describes a probe request served by the handler.
+type ProbeAudit struct {
+	// Time is the time the request was received.
+	Time time.Time
+	// Probe is "liveness" or "readiness".
+	Probe string
+	// Path is the requested path.
+	Path string
+	// SourceIP is the IP address the request comes from.
+	SourceIP string
+	// UserAgent is the User-Agent of the request.
+	UserAgent string
+	// Caller is the kind of client detected from the User-Agent.
+	Caller Caller
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Duration is the time it took to evaluate the probe.
+	Duration time.Duration
+}
+
+// ProbeAuditSink receives the audit of every probe request.
+type ProbeAuditSink func(audit ProbeAudit)
+
+// WithProbeAudit passes the audit of every probe request to the sink, to find
+// out which clients (mesh sidecars, external monitors...) probe the instance
+// and what they got when diagnosing unexpected restarts.
+func WithProbeAudit(sink ProbeAuditSink) Option {
+	return func(h *basicHandler) {
+		h.probeAuditSink = sink
+	}
+}
+
+// ProbeAuditToLogger returns a sink logging every probe request, at the info
+// level if the probe passed and at the warning level if it failed.
+func ProbeAuditToLogger(logger *slog.Logger) ProbeAuditSink {
+	return func(audit ProbeAudit) {
+		level := slog.LevelInfo
+		if audit.StatusCode >= http.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(context.Background(), level, "health probe served",
+			slog.String("probe", audit.Probe),
+			slog.String("path", audit.Path),
+			slog.String("source_ip", audit.SourceIP),
+			slog.String("user_agent", audit.UserAgent),
+			slog.String("caller", string(audit.Caller)),
+			slog.Int("status", audit.StatusCode),
+			slog.Duration("duration", audit.Duration),
+		)
+	}
+}
+
+// auditProbe passes the audit of the probe request to the sink set by WithProbeAudit.
+func (s *basicHandler) auditProbe(r *http.Request, probe string, start time.Time, elapsed time.Duration, status int) {
+	if s.probeAuditSink == nil {
+		return
+	}
+
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+	audit := ProbeAudit{
+		Time:       start,
+		Probe:      probe,
+		Path:       r.URL.Path,
+		SourceIP:   sourceIP,
+		UserAgent:  r.UserAgent(),
+		Caller:     ClassifyCaller(r),
+		StatusCode: status,
+		Duration:   elapsed,
+	}
+	safeCall(func() { s.probeAuditSink(audit) })
+}
@@ -0,0 +1,57 @@
+package healthcheck
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithProbeAudit(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var audits []ProbeAudit
+	h := NewHandler(WithClock(clock), WithProbeAudit(func(audit ProbeAudit) {
+		audits = append(audits, audit)
+	}))
+	h.AddReadinessCheck("database", func() error {
+		clock.Advance(time.Second)
+		return errors.New("connection refused")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil)
+	req.RemoteAddr = "10.0.0.7:51234"
+	req.Header.Set("User-Agent", "ELB-HealthChecker/2.0")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	expect := ProbeAudit{
+		Time:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Probe:      "readiness",
+		Path:       ReadinessHandlerPath,
+		SourceIP:   "10.0.0.7",
+		UserAgent:  "ELB-HealthChecker/2.0",
+		Caller:     CallerAWSELB,
+		StatusCode: http.StatusServiceUnavailable,
+		Duration:   time.Second,
+	}
+	if len(audits) != 1 || audits[0] != expect {
+		t.Errorf("Wrong audits\n"+"expected: %+v\n"+"actual  : %+v", []ProbeAudit{expect}, audits)
+	}
+}
+
+func TestProbeAuditToLogger(t *testing.T) {
+	var buf bytes.Buffer
+	sink := ProbeAuditToLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	sink(ProbeAudit{Probe: "liveness", SourceIP: "10.0.0.7", StatusCode: http.StatusServiceUnavailable})
+
+	for _, attr := range []string{"level=WARN", "probe=liveness", "source_ip=10.0.0.7", "status=503"} {
+		if !strings.Contains(buf.String(), attr) {
+			t.Errorf("Missing attribute %s in log:\n%s", attr, buf.String())
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package healthcheck
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Schedule determines when a background check is executed.
+type Schedule interface {
+	// Next returns the next execution time after t,
+	// or the zero time if there are no more executions.
+	Next(t time.Time) time.Time
+}
+
+// ScheduleFunc is an adapter allowing the use of an ordinary function as a Schedule.
+type ScheduleFunc func(t time.Time) time.Time
+
+// Next calls f(t).
+func (f ScheduleFunc) Next(t time.Time) time.Time {
+	return f(t)
+}
+
+// Every returns a Schedule executing a check at fixed intervals.
+func Every(interval time.Duration) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		return t.Add(interval)
+	})
+}
+
+// WithSchedule executes the check in background according to the schedule
+// instead of on every probe; probes are served with the last result.
+// The check is executed for the first time as soon as it's added.
+// Use Every for simple intervals and ParseCron for expensive checks
+// that should only run at particular times.
+func WithSchedule(schedule Schedule) CheckOption {
+	return func(c *checkConfig) {
+		c.schedule = schedule
+	}
+}
+
+// WithJitter delays every background execution of the check by a random
+// duration up to maxJitter, so many replicas deployed at the same time don't all hit
+// the same dependency with their checks at the same instant.
+// It only has an effect along with WithSchedule.
+func WithJitter(maxJitter time.Duration) CheckOption {
+	return func(c *checkConfig) {
+		c.jitter = maxJitter
+	}
+}
+
+// jittered adds a random jitter up to maxJitter to the delay.
+func jittered(delay, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return delay
+	}
+	return delay + rand.N(maxJitter)
+}
+
+// background holds the state of a check executed in background.
+type background struct {
+	cancel context.CancelFunc
+
+	mu   sync.RWMutex
+	last Result
+	// updated is the time of the last execution, or of the start if none completed.
+	updated time.Time
+}
+
+func (b *background) result() (Result, time.Time) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.last, b.updated
+}
+
+func (b *background) setResult(result Result, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last, b.updated = result, now
+}
+
+// startBackground starts executing the check according to its schedule
+// until the handler context is done or the check is replaced.
+func (s *basicHandler) startBackground(entry *checkEntry) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	bg := &background{
+		cancel:  cancel,
+		last:    Result{Err: ErrPending},
+		updated: s.clock.Now(),
+	}
+	if restored, ok := s.restoredResult(entry.name); ok {
+		bg.last = restored
+	}
+	entry.background = bg
+
+	go func() {
+		for {
+			if !s.checkDisabled(entry.name) {
+				bg.setResult(s.runCheck(entry), s.clock.Now())
+			}
+
+			next := entry.config.schedule.Next(s.clock.Now())
+			if next.IsZero() {
+				return
+			}
+
+			timer := s.clock.NewTimer(jittered(next.Sub(s.clock.Now()), entry.config.jitter))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C():
+			}
+		}
+	}()
+}
@@ -0,0 +1,78 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackgroundCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+
+	h := NewHandler(WithContext(ctx))
+	h.AddReadinessCheck("background", func() error {
+		if calls.Add(1) == 1 {
+			<-release
+			return errors.New("failed background check")
+		}
+		return nil
+	}, WithSchedule(Every(10*time.Millisecond)))
+
+	probe := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1", nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// the first execution is still in progress
+	rr := probe()
+	expectBody := "{\n    \"background\": \"pending\"\n}\n"
+	if rr.Code != http.StatusServiceUnavailable || rr.Body.String() != expectBody {
+		t.Errorf("Wrong response before the first execution: %d %s", rr.Code, rr.Body.String())
+	}
+
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for probe().Code != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatalf("Background check didn't recover")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// probes are served from the last result
+	before := calls.Load()
+	for i := 0; i < 10; i++ {
+		probe()
+	}
+	if after := calls.Load(); after-before > 2 {
+		t.Errorf("Probes must not execute background checks, got %d executions", after-before)
+	}
+}
+
+func TestJittered(t *testing.T) {
+	if d := jittered(time.Second, 0); d != time.Second {
+		t.Errorf("Delay must not change without jitter, got %v", d)
+	}
+
+	for i := 0; i < 100; i++ {
+		if d := jittered(time.Second, time.Second); d < time.Second || d >= 2*time.Second {
+			t.Fatalf("Jittered delay out of range: %v", d)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen wraps the cached error of a check wrapped by
+// CircuitBreaker which isn't executed during its cool-down.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitBreaker wraps the check to stop executing it for the cool-down once
+// it has failed threshold times in a row, reporting its last error wrapped in
+// ErrCircuitOpen instead, so the probes don't hammer a dependency which is down:
+//
+//	handler.AddReadinessCheck("payments-api", healthcheck.CircuitBreaker(check, 3, 30*time.Second))
+//
+// After the cool-down, the next call executes the check once: the circuit
+// closes if it passes and opens for another cool-down if it fails.
+func CircuitBreaker(check Check, threshold int, coolDown time.Duration, opts ...CircuitBreakerOption) Check {
+	if threshold < 1 {
+		threshold = 1
+	}
+	b := &circuitBreaker{check: check, threshold: threshold, coolDown: coolDown, clock: SystemClock}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b.execute
+}
+
+// CircuitBreakerOption configures a CircuitBreaker.
+type CircuitBreakerOption func(b *circuitBreaker)
+
+// WithCircuitBreakerClock sets the Clock of the cool-down, SystemClock by default.
+func WithCircuitBreakerClock(clock Clock) CircuitBreakerOption {
+	return func(b *circuitBreaker) {
+		b.clock = clock
+	}
+}
+
+// circuitBreaker is the state of a check wrapped by CircuitBreaker.
+type circuitBreaker struct {
+	check     Check
+	threshold int
+	coolDown  time.Duration
+	clock     Clock
+
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *circuitBreaker) execute() error {
+	b.mu.Lock()
+	if b.failures >= b.threshold {
+		if b.probing || b.clock.Now().Sub(b.openedAt) < b.coolDown {
+			err := b.lastErr
+			b.mu.Unlock()
+			return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+		}
+		// half-open: only this call executes the check
+		b.probing = true
+	}
+	b.mu.Unlock()
+
+	completed := false
+	defer func() {
+		// a panicking check must not keep the circuit half-open forever
+		if !completed {
+			b.mu.Lock()
+			b.probing = false
+			b.mu.Unlock()
+		}
+	}()
+	err := b.check()
+	completed = true
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures, b.lastErr = 0, nil
+		return nil
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+	}
+	return err
+}
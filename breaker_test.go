@@ -0,0 +1,54 @@
+package healthcheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var (
+		executions int
+		fails      = true
+		failure    = errors.New("connection refused")
+	)
+	check := CircuitBreaker(func() error {
+		executions++
+		if fails {
+			return failure
+		}
+		return nil
+	}, 2, 20*time.Second, WithCircuitBreakerClock(clock))
+
+	steps := []struct {
+		name       string
+		fails      bool
+		wait       time.Duration
+		expectOpen bool
+		executions int
+	}{
+		{name: "first failure", fails: true, executions: 1},
+		{name: "threshold reached", fails: true, executions: 2},
+		{name: "open", fails: true, expectOpen: true, executions: 2},
+		{name: "half-open failure", fails: true, wait: 30 * time.Second, executions: 3},
+		{name: "reopened", fails: false, expectOpen: true, executions: 3},
+		{name: "half-open success", fails: false, wait: 30 * time.Second, executions: 4},
+		{name: "closed", fails: true, executions: 5},
+	}
+	for _, step := range steps {
+		fails = step.fails
+		clock.Advance(step.wait)
+
+		err := check()
+		if errors.Is(err, ErrCircuitOpen) != step.expectOpen {
+			t.Errorf("Wrong error for %s\n"+"expected open: %v\n"+"actual       : %v", step.name, step.expectOpen, err)
+		}
+		if err != nil && !errors.Is(err, failure) {
+			t.Errorf("Wrong error for %s\n"+"expected: %v\n"+"actual  : %v", step.name, failure, err)
+		}
+		if executions != step.executions {
+			t.Errorf("Wrong executions for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.executions, executions)
+		}
+	}
+}
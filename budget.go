@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+// WithProbeBudget bounds the load the probes put on the dependencies, so an
+// aggressive external prober can't multiply the calls to every dependency:
+//   - at most maxConcurrent checks are executed concurrently by the probes;
+//   - at most maxCalls checks are executed by the probes per window.
+//
+// A probe which would exceed the budget is served with the results of the
+// last evaluation of the same probe instead of executing the checks. Background
+// checks don't count, as the probes don't execute them. Zero disables a limit.
+// The first evaluation of each probe is always executed.
+func WithProbeBudget(maxConcurrent, maxCalls int, window time.Duration) Option {
+	return func(h *basicHandler) {
+		h.budget = &probeBudget{maxConcurrent: maxConcurrent, maxCalls: maxCalls, window: window}
+	}
+}
+
+// probeSnapshot is the last evaluation of a probe.
+type probeSnapshot struct {
+	state   probeState
+	results map[string]Result
+}
+
+// probeBudget tracks the check executions of the probes against the budget.
+type probeBudget struct {
+	maxConcurrent int
+	maxCalls      int
+	window        time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	windowStart time.Time
+	calls       int
+	// snapshots are the last evaluations of the liveness and readiness probes
+	snapshots [2]*probeSnapshot
+}
+
+// acquire reserves the execution of calls checks, it returns the snapshot
+// to serve instead if the budget is exceeded.
+func (b *probeBudget) acquire(readiness bool, calls int, now time.Time) *probeSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.windowStart) >= b.window {
+		b.windowStart, b.calls = now, 0
+	}
+
+	snapshot := b.snapshots[probeIndex(readiness)]
+	exceeded := (b.maxConcurrent > 0 && b.inFlight+calls > b.maxConcurrent) ||
+		(b.maxCalls > 0 && b.calls+calls > b.maxCalls)
+	if exceeded && snapshot != nil {
+		return snapshot
+	}
+
+	b.inFlight += calls
+	b.calls += calls
+	return nil
+}
+
+// release completes the execution of calls checks and stores the evaluation.
+func (b *probeBudget) release(readiness bool, calls int, state probeState, results map[string]Result) {
+	snapshot := &probeSnapshot{state: state, results: make(map[string]Result, len(results))}
+	for name, res := range results {
+		snapshot.results[name] = res
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight -= calls
+	b.snapshots[probeIndex(readiness)] = snapshot
+}
+
+func probeIndex(readiness bool) int {
+	if readiness {
+		return 1
+	}
+	return 0
+}
+
+// probeCalls returns the number of checks executed by an evaluation of the probe.
+func probeCalls(entries []*checkEntry) int {
+	calls := 0
+	for _, entry := range entries {
+		if entry.background == nil {
+			calls++
+		}
+	}
+	return calls
+}
@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithProbeBudget(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock), WithProbeBudget(0, 2, time.Minute))
+
+	var (
+		executions atomic.Int32
+		failing    atomic.Bool
+	)
+	h.AddReadinessCheck("database", func() error {
+		executions.Add(1)
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	steps := []struct {
+		name       string
+		advance    time.Duration
+		failing    bool
+		code       int
+		executions int32
+	}{
+		{name: "first evaluation", code: http.StatusOK, executions: 1},
+		{name: "within budget", failing: true, code: http.StatusServiceUnavailable, executions: 2},
+		{name: "budget exceeded serves the snapshot", code: http.StatusServiceUnavailable, executions: 2},
+		{name: "next window", advance: time.Minute, code: http.StatusOK, executions: 3},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		failing.Store(step.failing)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+		if rr.Code != step.code {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.code, rr.Code)
+		}
+		if n := executions.Load(); n != step.executions {
+			t.Errorf("Wrong executions for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.executions, n)
+		}
+	}
+}
+
+func TestProbeBudgetConcurrency(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := &probeBudget{maxConcurrent: 2}
+
+	if snapshot := budget.acquire(true, 2, now); snapshot != nil {
+		t.Fatalf("First evaluation served from a snapshot")
+	}
+	budget.release(true, 2, probePass, map[string]Result{"database": {}})
+
+	first := budget.acquire(true, 2, now)
+	if first != nil {
+		t.Fatalf("Evaluation within the budget served from a snapshot")
+	}
+	if second := budget.acquire(true, 2, now); second == nil || second.state != probePass {
+		t.Errorf("Concurrent evaluation exceeding the budget not served from the snapshot")
+	}
+}
@@ -0,0 +1,94 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBulkheadFull is the error of a check wrapped by a Bulkhead which
+// couldn't start because its dependency had too many checks in flight.
+var ErrBulkheadFull = errors.New("bulkhead full")
+
+// Bulkhead limits the number of in-flight checks per dependency, so a storm
+// of probes (or many checks sharing a target) can't pile up queries on a
+// fragile dependency:
+//
+//	bulkhead := healthcheck.NewBulkhead(1, 2*time.Second)
+//	handler.AddReadinessCheck("database", bulkhead.Wrap("postgres", dbCheck))
+//	handler.AddLivenessCheck("database-replica", bulkhead.Wrap("postgres", replicaCheck))
+//
+// The checks wrapped with the same key share the limit.
+type Bulkhead struct {
+	limit   int
+	maxWait time.Duration
+	clock   Clock
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewBulkhead creates a Bulkhead allowing limit checks in flight per key (at
+// least 1). A check waits up to maxWait for a slot, then fails with ErrBulkheadFull.
+func NewBulkhead(limit int, maxWait time.Duration, opts ...BulkheadOption) *Bulkhead {
+	if limit < 1 {
+		limit = 1
+	}
+	b := &Bulkhead{
+		limit:   limit,
+		maxWait: maxWait,
+		clock:   SystemClock,
+		slots:   make(map[string]chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// BulkheadOption configures a Bulkhead.
+type BulkheadOption func(b *Bulkhead)
+
+// WithBulkheadClock sets the Clock of the wait for a slot, SystemClock by default.
+func WithBulkheadClock(clock Clock) BulkheadOption {
+	return func(b *Bulkhead) {
+		b.clock = clock
+	}
+}
+
+// Wrap returns the check limited by the bulkhead of key.
+func (b *Bulkhead) Wrap(key string, check Check) Check {
+	return func() error {
+		slots := b.keySlots(key)
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			timer := b.clock.NewTimer(b.maxWait)
+			defer timer.Stop()
+
+			select {
+			case slots <- struct{}{}:
+			case <-timer.C():
+				return fmt.Errorf("%w: %d checks of %s in flight", ErrBulkheadFull, b.limit, key)
+			}
+		}
+		defer func() { <-slots }()
+
+		return check()
+	}
+}
+
+// keySlots returns the semaphore of key.
+func (b *Bulkhead) keySlots(key string) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	slots, ok := b.slots[key]
+	if !ok {
+		slots = make(chan struct{}, b.limit)
+		b.slots[key] = slots
+	}
+	return slots
+}
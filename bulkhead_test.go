@@ -0,0 +1,54 @@
+package healthcheck
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkhead(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	bulkhead := NewBulkhead(1, 10*time.Second, WithBulkheadClock(clock))
+
+	var (
+		release = make(chan struct{})
+		started = make(chan struct{})
+	)
+	slow := bulkhead.Wrap("postgres", func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	fast := bulkhead.Wrap("postgres", func() error { return nil })
+	other := bulkhead.Wrap("redis", func() error { return nil })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := slow(); err != nil {
+			t.Errorf("Received unexpected error:\n%+v", err)
+		}
+	}()
+	<-started
+
+	// the wait for a slot gives up once maxWait elapses
+	go func() {
+		clock.BlockUntil(1)
+		clock.Advance(10 * time.Second)
+	}()
+	if err := fast(); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrBulkheadFull, err)
+	}
+	if err := other(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if err := fast(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+}
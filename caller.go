@@ -0,0 +1,163 @@
+package healthcheck
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CallersHandlerPath path to the number of probe requests by caller.
+const CallersHandlerPath = "/health/stats/callers"
+
+// Caller is the kind of client issuing a probe.
+type Caller string
+
+const (
+	// CallerUnknown is a caller that isn't recognized.
+	CallerUnknown Caller = "unknown"
+	// CallerAWSELB is an AWS Application, Network or Classic Load Balancer.
+	CallerAWSELB Caller = "aws-elb"
+	// CallerGCPLB is a Google Cloud load balancer.
+	CallerGCPLB Caller = "gcp-lb"
+	// CallerAzureLB is an Azure Load Balancer or Front Door.
+	CallerAzureLB Caller = "azure-lb"
+	// CallerKubelet is the kubelet running the Kubernetes probes.
+	CallerKubelet Caller = "kubelet"
+	// CallerEnvoy is the active health checking of Envoy.
+	CallerEnvoy Caller = "envoy"
+	// CallerHuman is an operator using curl, Wget or HTTPie.
+	CallerHuman Caller = "human"
+)
+
+// callerAgents maps User-Agent prefixes to the callers.
+var callerAgents = []struct {
+	prefix string
+	caller Caller
+}{
+	{prefix: "ELB-HealthChecker/", caller: CallerAWSELB},
+	{prefix: "GoogleHC/", caller: CallerGCPLB},
+	{prefix: "Load Balancer Agent", caller: CallerAzureLB},
+	{prefix: "Edge Health Probe", caller: CallerAzureLB},
+	{prefix: "kube-probe/", caller: CallerKubelet},
+	{prefix: "Envoy/HC", caller: CallerEnvoy},
+	{prefix: "curl/", caller: CallerHuman},
+	{prefix: "Wget/", caller: CallerHuman},
+	{prefix: "HTTPie/", caller: CallerHuman},
+}
+
+// ClassifyCaller detects the caller of the request from its User-Agent.
+func ClassifyCaller(r *http.Request) Caller {
+	agent := r.UserAgent()
+	for _, a := range callerAgents {
+		if strings.HasPrefix(agent, a.prefix) {
+			return a.caller
+		}
+	}
+	return CallerUnknown
+}
+
+// isLoadBalancer reports whether the caller is a cloud load balancer.
+func (c Caller) isLoadBalancer() bool {
+	switch c {
+	case CallerAWSELB, CallerGCPLB, CallerAzureLB:
+		return true
+	}
+	return false
+}
+
+// WithLoadBalancerProbes makes the handler serve cloud load balancer health checks
+// (AWS ELB, Google Cloud and Azure load balancers) along with the orchestrator probes:
+//   - the probe endpoints accept HEAD requests, which some load balancers send;
+//   - requests from recognized load balancers to any of the given paths (e.g. "/",
+//     the default health check path of most of them) are answered as readiness probes,
+//     other requests to these paths get 404 Not Found.
+func WithLoadBalancerProbes(paths ...string) Option {
+	return func(h *basicHandler) {
+		h.loadBalancerProbes = true
+		h.loadBalancerPaths = append(h.loadBalancerPaths, paths...)
+	}
+}
+
+// registerLoadBalancerPaths registers the load balancer health check paths on the mux.
+func (s *basicHandler) registerLoadBalancerPaths() {
+	for _, path := range s.loadBalancerPaths {
+		pattern := path
+		if pattern == "/" {
+			// "/" matches every path otherwise
+			pattern = "/{$}"
+		}
+
+		s.route(pattern, probeOperation("Readiness probe for the load balancers"), func(w http.ResponseWriter, r *http.Request) {
+			if !ClassifyCaller(r).isLoadBalancer() {
+				http.NotFound(w, r)
+				return
+			}
+			s.ReadyEndpoint(w, r)
+		})
+	}
+}
+
+// WithFullOutputFor serves the full output of the probes to the given callers
+// (e.g. CallerHuman) without ?full=1, other callers keep getting an empty body.
+func WithFullOutputFor(callers ...Caller) Option {
+	return func(h *basicHandler) {
+		if h.fullOutputCallers == nil {
+			h.fullOutputCallers = make(map[Caller]bool)
+		}
+		for _, caller := range callers {
+			h.fullOutputCallers[caller] = true
+		}
+	}
+}
+
+// fullOutput reports whether the full output of the probe is served to the request.
+func (s *basicHandler) fullOutput(r *http.Request) bool {
+	return r.URL.Query().Get("full") == "1" || s.fullOutputCallers[ClassifyCaller(r)]
+}
+
+// callerKey identifies the requests of a caller to a probe.
+type callerKey struct {
+	probe  string
+	caller Caller
+}
+
+// callerCounter counts the probe requests by caller.
+type callerCounter struct {
+	mu     sync.Mutex
+	counts map[callerKey]uint64
+}
+
+// add counts a request of the caller to the probe.
+func (c *callerCounter) add(probe string, caller Caller) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[callerKey]uint64)
+	}
+	c.counts[callerKey{probe: probe, caller: caller}]++
+}
+
+// snapshot returns the number of requests by probe and caller.
+func (c *callerCounter) snapshot() map[string]map[Caller]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]map[Caller]uint64)
+	for key, n := range c.counts {
+		if counts[key.probe] == nil {
+			counts[key.probe] = make(map[Caller]uint64)
+		}
+		counts[key.probe][key.caller] = n
+	}
+	return counts
+}
+
+// CallersEndpoint is an HTTP handler exposing the number
+// of requests to each probe by caller as JSON.
+func (s *basicHandler) CallersEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.callers.snapshot())
+}
@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadBalancerProbes(t *testing.T) {
+	h := NewHandler(WithLoadBalancerProbes("/"))
+	h.AddReadinessCheck("ready", func() error { return nil })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		agent  string
+		proto  string
+		expect int
+	}{
+		{
+			name:   "ALB health check",
+			method: http.MethodGet,
+			path:   "/",
+			agent:  "ELB-HealthChecker/2.0",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "Google health check with HTTP/1.0",
+			method: http.MethodGet,
+			path:   "/",
+			agent:  "GoogleHC/1.0",
+			proto:  "HTTP/1.0",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "Azure HEAD health check",
+			method: http.MethodHead,
+			path:   "/",
+			agent:  "Load Balancer Agent",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "unknown caller on the load balancer path",
+			method: http.MethodGet,
+			path:   "/",
+			agent:  "curl/8.0",
+			expect: http.StatusNotFound,
+		},
+		{
+			name:   "HEAD readiness probe",
+			method: http.MethodHead,
+			path:   ReadinessHandlerPath,
+			expect: http.StatusOK,
+		},
+		{
+			name:   "other paths aren't served",
+			method: http.MethodGet,
+			path:   "/foo",
+			agent:  "ELB-HealthChecker/2.0",
+			expect: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("User-Agent", tt.agent)
+			if tt.proto != "" {
+				req.Proto, req.ProtoMajor, req.ProtoMinor = tt.proto, 1, 0
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+
+					"expected: %v\n"+
+					"actual  : %v", tt.expect, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCallers(t *testing.T) {
+	h := NewHandler(WithOpenMetrics(""), WithFullOutputFor(CallerHuman))
+	h.AddReadinessCheck("ready", func() error { return nil })
+
+	probes := []struct {
+		path   string
+		agent  string
+		expect string
+	}{
+		{path: LivenessHandlerPath, agent: "kube-probe/1.29", expect: "{}\n"},
+		{path: ReadinessHandlerPath, agent: "kube-probe/1.29", expect: "{}\n"},
+		{path: ReadinessHandlerPath, agent: "kube-probe/1.29", expect: "{}\n"},
+		{path: ReadinessHandlerPath, agent: "Envoy/HC", expect: "{}\n"},
+		{path: ReadinessHandlerPath, agent: "curl/8.5.0", expect: "{\n    \"ready\": \"OK\"\n}\n"},
+	}
+	for _, probe := range probes {
+		req := httptest.NewRequest(http.MethodGet, probe.path, nil)
+		req.Header.Set("User-Agent", probe.agent)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Body.String() != probe.expect {
+			t.Errorf("Wrong body for %s\n"+"expected: %q\n"+"actual  : %q", probe.agent, probe.expect, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, CallersHandlerPath, nil))
+	expect := "{\n    \"liveness\": {\n        \"kubelet\": 1\n    },\n" +
+		"    \"readiness\": {\n        \"envoy\": 1,\n        \"human\": 1,\n        \"kubelet\": 2\n    }\n}\n"
+	if rr.Body.String() != expect {
+		t.Errorf("Wrong callers\n"+"expected: %v\n"+"actual  : %v", expect, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, OpenMetricsHandlerPath, nil))
+	series := `healthcheck_probe_requests_total{probe="readiness",caller="kubelet"} 2`
+	if !strings.Contains(rr.Body.String(), series) {
+		t.Errorf("Missing series %s in metrics:\n%s", series, rr.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package healthcheck
+
+import (
+	"errors"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ChaosEnv is the environment variable enabling the chaos mode set by WithChaosFromEnv.
+const ChaosEnv = "HEALTHCHECK_CHAOS"
+
+// ChaosAllChecks is the ChaosRule key applying to all the checks without their own rule.
+const ChaosAllChecks = "*"
+
+// ErrChaos is the error of the failures injected by the chaos mode.
+var ErrChaos = errors.New("chaos: injected failure")
+
+// ChaosRule configures the faults injected into a check by the chaos mode.
+type ChaosRule struct {
+	// FailureRate is the probability (0 to 1) the check fails with ErrChaos
+	// without being executed.
+	FailureRate float64
+	// Delay is the delay added before the execution of the check.
+	Delay time.Duration
+	// DelayRate is the probability (0 to 1) the Delay is added.
+	DelayRate float64
+}
+
+// WithChaos enables the chaos mode: the checks are randomly failed or delayed
+// according to their rule (ChaosAllChecks for any check), so the reaction of
+// the alerting, draining and auto-healing to health changes can be verified.
+// It must never be enabled unintentionally, see WithChaosFromEnv.
+func WithChaos(rules map[string]ChaosRule) Option {
+	return func(h *basicHandler) {
+		h.chaosRules = rules
+	}
+}
+
+// WithChaosFromEnv is WithChaos enabled only if the ChaosEnv environment
+// variable is set to a true value ("1", "true"...).
+func WithChaosFromEnv(rules map[string]ChaosRule) Option {
+	return func(h *basicHandler) {
+		if enabled, _ := strconv.ParseBool(os.Getenv(ChaosEnv)); enabled {
+			WithChaos(rules)(h)
+		}
+	}
+}
+
+// injectChaos applies the chaos rule of the check, if any: it waits for
+// the injected delay and returns ErrChaos if a failure is injected.
+func (s *basicHandler) injectChaos(name string) error {
+	rule, ok := s.chaosRules[name]
+	if !ok {
+		rule, ok = s.chaosRules[ChaosAllChecks]
+	}
+	if !ok {
+		return nil
+	}
+
+	if rule.Delay > 0 && rand.Float64() < rule.DelayRate {
+		timer := s.clock.NewTimer(rule.Delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return s.ctx.Err()
+		case <-timer.C():
+		}
+	}
+	if rand.Float64() < rule.FailureRate {
+		return ErrChaos
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChaos(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    Option
+		env    string
+		expect error
+	}{
+		{
+			name:   "failure injected",
+			opt:    WithChaos(map[string]ChaosRule{"database": {FailureRate: 1}}),
+			expect: ErrChaos,
+		},
+		{
+			name:   "failure injected into all checks",
+			opt:    WithChaos(map[string]ChaosRule{ChaosAllChecks: {FailureRate: 1}}),
+			expect: ErrChaos,
+		},
+		{
+			name: "other check",
+			opt:  WithChaos(map[string]ChaosRule{"cache": {FailureRate: 1}}),
+		},
+		{
+			name: "env var unset",
+			opt:  WithChaosFromEnv(map[string]ChaosRule{"database": {FailureRate: 1}}),
+		},
+		{
+			name:   "env var set",
+			opt:    WithChaosFromEnv(map[string]ChaosRule{"database": {FailureRate: 1}}),
+			env:    "true",
+			expect: ErrChaos,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ChaosEnv, tt.env)
+
+			h := NewHandler(tt.opt)
+			h.AddReadinessCheck("database", func() error { return nil })
+
+			result, err := h.RunCheck(context.Background(), "database")
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if !errors.Is(result.Err, tt.expect) {
+				t.Errorf("Wrong result\n"+"expected: %v\n"+"actual  : %v", tt.expect, result.Err)
+			}
+		})
+	}
+}
+
+func TestChaosDelay(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	h := NewHandler(WithClock(clock), WithChaos(map[string]ChaosRule{
+		"database": {Delay: time.Second, DelayRate: 1},
+	}))
+	h.AddReadinessCheck("database", func() error { return nil })
+
+	done := make(chan Result)
+	go func() {
+		result, _ := h.RunCheck(context.Background(), "database")
+		done <- result
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	if result := <-done; result.Duration != time.Second {
+		t.Errorf("Wrong duration\n"+"expected: %v\n"+"actual  : %v", time.Second, result.Duration)
+	}
+}
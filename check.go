@@ -0,0 +1,130 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+)
+
+// CheckOption configures a single check added to a Handler.
+type CheckOption func(c *checkConfig)
+
+// ReportOnly registers the check in report-only mode: it's executed and appears in
+// the full output and metrics, but never affects the HTTP status of the probes or
+// the health score. It allows soak-testing new checks in production before
+// they can take instances out of rotation.
+func ReportOnly() CheckOption {
+	return func(c *checkConfig) {
+		c.reportOnly = true
+	}
+}
+
+// WithLabels attaches static labels to the check (e.g. component, tier, owner),
+// they're added to the metrics exported for the check so alerts can be routed
+// on them. The "check", "probe" and "detail" labels are reserved.
+func WithLabels(labels map[string]string) CheckOption {
+	return func(c *checkConfig) {
+		if c.labels == nil {
+			c.labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			c.labels[k] = v
+		}
+	}
+}
+
+// checkConfig is the configuration of a single check.
+type checkConfig struct {
+	schedule     Schedule
+	jitter       time.Duration
+	staleAfter   time.Duration
+	dependencies []string
+	tags         []string
+	labels       map[string]string
+	errorCode    string
+	slo          *sloConfig
+	weight       *float64
+	criticality  Criticality
+	nonCritical  bool
+	reportOnly   bool
+	maintenance  []maintenanceWindow
+	roleGate     *RoleGate
+	role         Role
+	historySize  int
+}
+
+// checkEntry is a check registered on the handler along with its configuration.
+type checkEntry struct {
+	name       string
+	check      Checker
+	config     checkConfig
+	background *background
+	slo        *sloTracker
+	history    *observationHistory
+}
+
+// stop releases the resources held by the check.
+func (e *checkEntry) stop() {
+	if e.background != nil {
+		e.background.cancel()
+	}
+}
+
+// Checker is a check able to report more than a pass or a fail, e.g. observed values
+// (latency, version, free space) rendered in the full output and metrics.
+type Checker interface {
+	// Name returns the name of the check.
+	Name() string
+	// Check executes the check, ctx is the handler context (see WithContext).
+	Check(ctx context.Context) Result
+}
+
+// checkFunc adapts a Check to the Checker interface.
+type checkFunc struct {
+	name  string
+	check Check
+}
+
+func (c checkFunc) Name() string {
+	return c.name
+}
+
+func (c checkFunc) Check(context.Context) Result {
+	return Result{Err: c.check()}
+}
+
+// Metrics returns the numeric details of the result as float64, durations
+// in seconds, booleans as 0 or 1 and ObservedValue details as their value.
+func (r Result) Metrics() map[string]float64 {
+	metrics := make(map[string]float64)
+	for key, value := range r.Details {
+		var v float64
+		switch value := value.(type) {
+		case time.Duration:
+			v = value.Seconds()
+		case float64:
+			v = value
+		case float32:
+			v = float64(value)
+		case int:
+			v = float64(value)
+		case int32:
+			v = float64(value)
+		case int64:
+			v = float64(value)
+		case uint:
+			v = float64(value)
+		case uint32:
+			v = float64(value)
+		case uint64:
+			v = float64(value)
+		case bool:
+			v = float64(boolGauge(value))
+		case observation:
+			v = value.Float64()
+		default:
+			continue
+		}
+		metrics[key] = v
+	}
+	return metrics
+}
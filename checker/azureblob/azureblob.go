@@ -0,0 +1,49 @@
+// Package azureblob provides a check of an Azure Blob Storage container,
+// for services storing artifacts in Azure.
+package azureblob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ContainerCheck returns a Check that gets the properties of the container,
+// failing if it doesn't exist or the credentials of the client can't access it.
+func ContainerCheck(client *container.Client, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if client == nil {
+			return errors.New("container client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		_, err := client.GetProperties(ctx, nil)
+		return err
+	}
+}
+
+// NewContainerCheck creates a ContainerCheck of the container of the storage
+// account (e.g. "https://account.blob.core.windows.net") with a client
+// authenticated by the Azure SDK default credential chain (environment,
+// workload identity, managed identity, Azure CLI...).
+func NewContainerCheck(accountURL, containerName string, timeout time.Duration) (healthcheck.Check, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("azure credential: %w", err)
+	}
+
+	client, err := container.NewClient(strings.TrimSuffix(accountURL, "/")+"/"+containerName, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ContainerCheck(client, timeout), nil
+}
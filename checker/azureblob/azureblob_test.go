@@ -0,0 +1,41 @@
+package azureblob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+func TestContainerCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/artifacts" || r.URL.Query().Get("restype") != "container" {
+			w.Header().Set("x-ms-error-code", "ContainerNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		container string
+		fails     bool
+	}{
+		{name: "existing container", container: "artifacts"},
+		{name: "missing container", container: "missing", fails: true},
+	}
+
+	for _, tt := range tests {
+		client, err := container.NewClientWithNoCredential(server.URL+"/account/"+tt.container, nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		if err := ContainerCheck(client, time.Second)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
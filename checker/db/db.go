@@ -41,3 +41,22 @@ func DatabasePingCheck(database *sql.DB, timeout time.Duration) healthcheck.Chec
 		return database.PingContext(ctx)
 	}
 }
+
+// QueryCheck returns a Check that pings the database and executes
+// the probe query, e.g. orm.ProbeQuery of its dialect.
+func QueryCheck(database *sql.DB, query string, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if database == nil {
+			return fmt.Errorf("database is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := database.PingContext(ctx); err != nil {
+			return err
+		}
+		var result int
+		return database.QueryRowContext(ctx, query).Scan(&result)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/catalystgo/healthcheck/checker/db/orm
+
+go 1.22
+
+require (
+	github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+	github.com/jmoiron/sqlx v1.4.0
+	gorm.io/gorm v1.25.12
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.17.0 // indirect
+)
+
+replace github.com/catalystgo/healthcheck => ../../..
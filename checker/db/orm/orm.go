@@ -0,0 +1,53 @@
+// Package orm provides the checks of the databases behind the GORM and sqlx
+// connections, executing the probe query of their dialect.
+package orm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"gorm.io/gorm"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/checker/db"
+)
+
+// ProbeQuery returns the cheapest query checking the database is able
+// to execute queries for the dialect or driver name ("postgres", "mysql",
+// "oracle", "godror"...): "SELECT 1" for most databases, "SELECT 1 FROM DUAL"
+// for Oracle.
+func ProbeQuery(dialect string) string {
+	switch dialect = strings.ToLower(dialect); {
+	case strings.Contains(dialect, "oracle"), strings.Contains(dialect, "godror"), dialect == "oci8":
+		return "SELECT 1 FROM DUAL"
+	default:
+		return "SELECT 1"
+	}
+}
+
+// GormCheck returns a Check that pings the database behind the GORM
+// connection and executes the probe query of its dialect.
+func GormCheck(database *gorm.DB, timeout time.Duration) healthcheck.Check {
+	if database == nil {
+		return db.QueryCheck(nil, "", timeout)
+	}
+
+	sqlDB, err := database.DB()
+	if err != nil {
+		return func() error {
+			return fmt.Errorf("gorm connection: %w", err)
+		}
+	}
+	return db.QueryCheck(sqlDB, ProbeQuery(database.Dialector.Name()), timeout)
+}
+
+// SQLXCheck returns a Check that pings the database behind the sqlx
+// connection and executes the probe query of its driver.
+func SQLXCheck(database *sqlx.DB, timeout time.Duration) healthcheck.Check {
+	if database == nil {
+		return db.QueryCheck(nil, "", timeout)
+	}
+	return db.QueryCheck(database.DB, ProbeQuery(database.DriverName()), timeout)
+}
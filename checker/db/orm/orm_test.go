@@ -0,0 +1,228 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"gorm.io/gorm"
+	gormtests "gorm.io/gorm/utils/tests"
+)
+
+// fakeDriver is a database/sql driver answering 1 to every query,
+// unless its ping fails.
+type fakeDriver struct {
+	pingErr error
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) lastQuery() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.queries) == 0 {
+		return ""
+	}
+	return d.queries[len(d.queries)-1]
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Ping(context.Context) error {
+	return c.driver.pingErr
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+
+	c.driver.queries = append(c.driver.queries, query)
+	return &fakeRows{}, nil
+}
+
+// fakeRows is a single row with the value 1.
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"1"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+// openFake opens a database on a new fake driver.
+func openFake(t *testing.T, pingErr error) (*sql.DB, *fakeDriver) {
+	t.Helper()
+
+	d := &fakeDriver{pingErr: pingErr}
+	database := sql.OpenDB(connector{d})
+	t.Cleanup(func() { _ = database.Close() })
+	return database, d
+}
+
+// connector opens the connections of a fakeDriver.
+type connector struct {
+	driver *fakeDriver
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dialector is a GORM dialector of the dialect using an open database.
+type dialector struct {
+	gormtests.DummyDialector
+
+	name     string
+	database *sql.DB
+}
+
+func (d dialector) Name() string {
+	return d.name
+}
+
+func (d dialector) Initialize(db *gorm.DB) error {
+	db.ConnPool = d.database
+	return d.DummyDialector.Initialize(db)
+}
+
+func TestProbeQuery(t *testing.T) {
+	tests := map[string]string{
+		"postgres": "SELECT 1",
+		"mysql":    "SELECT 1",
+		"Oracle":   "SELECT 1 FROM DUAL",
+		"godror":   "SELECT 1 FROM DUAL",
+		"oci8":     "SELECT 1 FROM DUAL",
+	}
+
+	for dialect, expect := range tests {
+		if query := ProbeQuery(dialect); query != expect {
+			t.Errorf("Wrong query of %s\n"+"expected: %v\n"+"actual  : %v", dialect, expect, query)
+		}
+	}
+}
+
+func TestGormCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     string
+		pingErr     error
+		expectQuery string
+		fails       bool
+	}{
+		{name: "postgres", dialect: "postgres", expectQuery: "SELECT 1"},
+		{name: "oracle", dialect: "oracle", expectQuery: "SELECT 1 FROM DUAL"},
+		{name: "unreachable", dialect: "postgres", pingErr: errors.New("connection refused"), fails: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			database, d := openFake(t, tt.pingErr)
+			gormDB, err := gorm.Open(dialector{name: tt.dialect, database: database}, &gorm.Config{DisableAutomaticPing: true})
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			if err := GormCheck(gormDB, time.Second)(); (err != nil) != tt.fails {
+				t.Errorf("Wrong result\n"+"expected failure: %v\n"+"actual  : %v", tt.fails, err)
+			}
+			if query := d.lastQuery(); query != tt.expectQuery {
+				t.Errorf("Wrong query\n"+"expected: %v\n"+"actual  : %v", tt.expectQuery, query)
+			}
+		})
+	}
+}
+
+func TestGormCheckWithoutConnection(t *testing.T) {
+	gormDB, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	if err := GormCheck(gormDB, time.Second)(); !errors.Is(err, gorm.ErrInvalidDB) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", gorm.ErrInvalidDB, err)
+	}
+	if err := GormCheck(nil, time.Second)(); err == nil {
+		t.Errorf("Expected an error of the nil connection")
+	}
+}
+
+func TestSQLXCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		driverName  string
+		pingErr     error
+		expectQuery string
+		fails       bool
+	}{
+		{name: "mysql", driverName: "mysql", expectQuery: "SELECT 1"},
+		{name: "godror", driverName: "godror", expectQuery: "SELECT 1 FROM DUAL"},
+		{name: "unreachable", driverName: "mysql", pingErr: errors.New("connection refused"), fails: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			database, d := openFake(t, tt.pingErr)
+			if err := SQLXCheck(sqlx.NewDb(database, tt.driverName), time.Second)(); (err != nil) != tt.fails {
+				t.Errorf("Wrong result\n"+"expected failure: %v\n"+"actual  : %v", tt.fails, err)
+			}
+			if query := d.lastQuery(); query != tt.expectQuery {
+				t.Errorf("Wrong query\n"+"expected: %v\n"+"actual  : %v", tt.expectQuery, query)
+			}
+		})
+	}
+
+	if err := SQLXCheck(nil, time.Second)(); err == nil {
+		t.Errorf("Expected an error of the nil connection")
+	}
+}
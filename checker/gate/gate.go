@@ -0,0 +1,101 @@
+// Package gate provides readiness checks driven by an external switch
+// (callback, drain file, environment variable or feature flag), so operators
+// can pull a deployment group out of rotation without touching the infrastructure:
+//
+//	handler.AddReadinessCheck("drain", gate.DrainFile("/etc/app/drain"))
+//
+// A gate check fails while the gate is closed. Liveness is never affected,
+// so the instances keep running and can be put back in rotation any time.
+package gate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrClosed is the error of a check whose gate is closed.
+var ErrClosed = errors.New("gate closed")
+
+// Func returns a check failing while open returns false.
+func Func(open func() bool) healthcheck.Check {
+	return func() error {
+		if !open() {
+			return ErrClosed
+		}
+		return nil
+	}
+}
+
+// DrainFile returns a check failing while the file exists, e.g. created
+// with `touch` by an operator or a config management tool.
+func DrainFile(path string) healthcheck.Check {
+	return func() error {
+		_, err := os.Stat(path)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: drain file %s exists", ErrClosed, path)
+		case errors.Is(err, os.ErrNotExist):
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// DrainEnv returns a check failing while the environment variable
+// is set to a true value ("1", "true"...).
+func DrainEnv(name string) healthcheck.Check {
+	return func() error {
+		if drain, _ := strconv.ParseBool(os.Getenv(name)); drain {
+			return fmt.Errorf("%w: %s is set", ErrClosed, name)
+		}
+		return nil
+	}
+}
+
+// FlagProvider evaluates boolean feature flags (LaunchDarkly, Unleash, OpenFeature...).
+type FlagProvider interface {
+	// Bool returns the value of the flag.
+	Bool(ctx context.Context, key string) (bool, error)
+}
+
+// FlagProviderFunc is a function implementing FlagProvider.
+type FlagProviderFunc func(ctx context.Context, key string) (bool, error)
+
+// Bool implements FlagProvider.
+func (f FlagProviderFunc) Bool(ctx context.Context, key string) (bool, error) {
+	return f(ctx, key)
+}
+
+// Flag returns a check failing while the flag is false, i.e. the flag tells
+// whether the instance should serve traffic. If the provider fails, the last
+// known value is used (true initially), so an outage of the flag service
+// doesn't take the fleet out of rotation.
+func Flag(provider FlagProvider, key string, timeout time.Duration) healthcheck.Check {
+	var (
+		mu   sync.Mutex
+		last = true
+	)
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if open, err := provider.Bool(ctx, key); err == nil {
+			last = open
+		}
+		if !last {
+			return fmt.Errorf("%w: flag %s is off", ErrClosed, key)
+		}
+		return nil
+	}
+}
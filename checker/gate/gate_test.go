@@ -0,0 +1,67 @@
+package gate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDrainFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drain")
+	check := DrainFile(path)
+
+	if err := check(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if err := check(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrClosed, err)
+	}
+}
+
+func TestDrainEnv(t *testing.T) {
+	check := DrainEnv("HEALTHCHECK_TEST_DRAIN")
+
+	t.Setenv("HEALTHCHECK_TEST_DRAIN", "")
+	if err := check(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	t.Setenv("HEALTHCHECK_TEST_DRAIN", "true")
+	if err := check(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrClosed, err)
+	}
+}
+
+func TestFlag(t *testing.T) {
+	var (
+		value bool
+		err   error
+	)
+	check := Flag(FlagProviderFunc(func(context.Context, string) (bool, error) {
+		return value, err
+	}), "serve-traffic", time.Second)
+
+	tests := []struct {
+		name   string
+		value  bool
+		err    error
+		closed bool
+	}{
+		{name: "provider down initially", err: errors.New("unavailable")},
+		{name: "flag off", value: false, closed: true},
+		{name: "provider down keeps the last value", err: errors.New("unavailable"), closed: true},
+		{name: "flag on", value: true},
+	}
+
+	for _, tt := range tests {
+		value, err = tt.value, tt.err
+		if closed := errors.Is(check(), ErrClosed); closed != tt.closed {
+			t.Errorf("Wrong state for %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.closed, closed)
+		}
+	}
+}
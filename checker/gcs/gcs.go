@@ -0,0 +1,86 @@
+// Package gcs provides a check of a Google Cloud Storage bucket,
+// for services storing data in GCP.
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultEndpoint is the endpoint of the Cloud Storage JSON API.
+const DefaultEndpoint = "https://storage.googleapis.com/storage/v1"
+
+// readOnlyScope is the OAuth2 scope of the read-only access to Cloud Storage.
+const readOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+// Option configures the check.
+type Option func(c *config)
+
+type config struct {
+	endpoint string
+}
+
+// WithEndpoint sets the endpoint of the JSON API, e.g. of an emulator
+// such as fake-gcs-server, DefaultEndpoint by default.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// BucketCheck returns a Check that retrieves the attributes of the bucket
+// with the authenticated client, failing if the bucket doesn't exist or
+// the credentials can't access it.
+func BucketCheck(client *http.Client, bucket string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := config{endpoint: DefaultEndpoint}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	target := cfg.endpoint + "/b/" + url.PathEscape(bucket) + "?fields=name"
+
+	return func() error {
+		if client == nil {
+			return errors.New("gcs client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+			return fmt.Errorf("bucket %s: %s: %s", bucket, resp.Status, strings.TrimSpace(string(msg)))
+		}
+		return nil
+	}
+}
+
+// NewBucketCheck creates a BucketCheck with a client authenticated by the
+// Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud
+// credentials, workload identity or the metadata server).
+func NewBucketCheck(ctx context.Context, bucket string, timeout time.Duration, opts ...Option) (healthcheck.Check, error) {
+	client, err := google.DefaultClient(ctx, readOnlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("application default credentials: %w", err)
+	}
+	return BucketCheck(client, bucket, timeout, opts...), nil
+}
@@ -0,0 +1,35 @@
+package gcs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBucketCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/artifacts" {
+			http.Error(w, `{"error": {"code": 404, "message": "Not Found"}}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "artifacts"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		bucket string
+		fails  bool
+	}{
+		{name: "existing bucket", bucket: "artifacts"},
+		{name: "missing bucket", bucket: "missing", fails: true},
+	}
+
+	for _, tt := range tests {
+		check := BucketCheck(server.Client(), tt.bucket, time.Second, WithEndpoint(server.URL+"/storage/v1/"))
+		if err := check(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
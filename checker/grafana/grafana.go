@@ -0,0 +1,119 @@
+// Package grafana provides checks of the Grafana Loki and Tempo endpoints,
+// for log and trace forwarding sidecars which should drop out of readiness
+// when the backend is unreachable:
+//
+//	handler.AddReadinessCheck("loki", grafana.LokiPushCheck("http://loki:3100", 5*time.Second,
+//		grafana.WithTenant("team-a")))
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// Option configures the checks.
+type Option func(c *config)
+
+type config struct {
+	client  *http.Client
+	headers http.Header
+}
+
+// WithClient sets the HTTP client sending the requests, e.g. for mTLS.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithHeader adds a header to the requests.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithTenant sets the tenant of the requests (X-Scope-OrgID) of multi-tenant deployments.
+func WithTenant(tenant string) Option {
+	return WithHeader("X-Scope-OrgID", tenant)
+}
+
+// WithBasicAuth authenticates the requests with HTTP basic auth,
+// e.g. for Grafana Cloud.
+func WithBasicAuth(username, password string) Option {
+	return func(c *config) {
+		req := http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(username, password)
+		c.headers.Set("Authorization", req.Header.Get("Authorization"))
+	}
+}
+
+// LokiReadyCheck returns a Check of the /ready endpoint of Loki,
+// failing until the instance is ready to accept traffic.
+func LokiReadyCheck(baseURL string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	return request(http.MethodGet, endpoint(baseURL, "/ready"), nil, timeout, opts)
+}
+
+// LokiPushCheck returns a Check sending an empty push request to Loki
+// (POST /loki/api/v1/push), which validates the authentication and the
+// tenant along with the availability of the distributors.
+func LokiPushCheck(baseURL string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	body := []byte(`{"streams":[]}`)
+	return request(http.MethodPost, endpoint(baseURL, "/loki/api/v1/push"), body, timeout, opts)
+}
+
+// TempoReadyCheck returns a Check of the /ready endpoint of Tempo,
+// failing until the instance is ready to accept traffic.
+func TempoReadyCheck(baseURL string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	return request(http.MethodGet, endpoint(baseURL, "/ready"), nil, timeout, opts)
+}
+
+func endpoint(baseURL, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// request returns a Check sending the request and failing unless the response is 2xx.
+func request(method, url string, body []byte, timeout time.Duration, opts []Option) healthcheck.Check {
+	cfg := config{
+		client:  http.DefaultClient,
+		headers: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for key, values := range cfg.headers {
+			req.Header[key] = values
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+			return fmt.Errorf("%s %s responded with %s: %s", method, req.URL.Path, resp.Status, bytes.TrimSpace(msg))
+		}
+		return nil
+	}
+}
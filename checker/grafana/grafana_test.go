@@ -0,0 +1,48 @@
+package grafana
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecks(t *testing.T) {
+	ready := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/ready" && ready:
+			_, _ = io.WriteString(w, "ready")
+		case r.URL.Path == "/ready":
+			http.Error(w, "Ingester not ready", http.StatusServiceUnavailable)
+		case r.URL.Path == "/loki/api/v1/push" && r.Header.Get("X-Scope-OrgID") == "":
+			http.Error(w, "no org id", http.StatusUnauthorized)
+		case r.URL.Path == "/loki/api/v1/push":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		check func() error
+		ready bool
+		fails bool
+	}{
+		{name: "loki ready", check: LokiReadyCheck(server.URL+"/", time.Second), ready: true},
+		{name: "loki not ready", check: LokiReadyCheck(server.URL, time.Second), fails: true},
+		{name: "tempo ready", check: TempoReadyCheck(server.URL, time.Second), ready: true},
+		{name: "loki push", check: LokiPushCheck(server.URL, time.Second, WithTenant("team-a"))},
+		{name: "loki push without tenant", check: LokiPushCheck(server.URL, time.Second), fails: true},
+	}
+
+	for _, tt := range tests {
+		ready = tt.ready
+		if err := tt.check(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package grpcprobe provides checks of gRPC upstreams that don't implement
+// the health checking protocol: the exposure of a service through the server
+// reflection, or the invocation of an idempotent method.
+package grpcprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrServiceNotFound is the error of a service not exposed by the server reflection.
+var ErrServiceNotFound = errors.New("service not exposed by the server")
+
+// ServiceCheck returns a Check that lists the services of the server through
+// its reflection service (v1, or v1alpha for older servers), failing with
+// ErrServiceNotFound if the fully-qualified service isn't among them.
+func ServiceCheck(conn grpc.ClientConnInterface, service string, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if conn == nil {
+			return errors.New("grpc connection is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		services, err := listServices(ctx, conn)
+		if status.Code(err) == codes.Unimplemented {
+			services, err = listServicesV1Alpha(ctx, conn)
+		}
+		if err != nil {
+			return fmt.Errorf("server reflection: %w", err)
+		}
+		if !slices.Contains(services, service) {
+			return fmt.Errorf("%w: %s", ErrServiceNotFound, service)
+		}
+		return nil
+	}
+}
+
+func listServices(ctx context.Context, conn grpc.ClientConnInterface) ([]string, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	req := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+	}
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	return services, nil
+}
+
+func listServicesV1Alpha(ctx context.Context, conn grpc.ClientConnInterface) ([]string, error) {
+	stream, err := reflectionv1alphapb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	req := &reflectionv1alphapb.ServerReflectionRequest{
+		MessageRequest: &reflectionv1alphapb.ServerReflectionRequest_ListServices{},
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+	}
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	return services, nil
+}
+
+// Option configures MethodCheck.
+type Option func(c *config)
+
+type config struct {
+	request any
+	codes   []codes.Code
+}
+
+// WithRequest sets the request message of the method, an empty message by default.
+func WithRequest(request any) Option {
+	return func(c *config) {
+		c.request = request
+	}
+}
+
+// WithCodes sets the status codes other than OK that pass the check, e.g.
+// NotFound for a lookup of a missing key, proving the server handles requests.
+func WithCodes(c ...codes.Code) Option {
+	return func(cfg *config) {
+		cfg.codes = c
+	}
+}
+
+// MethodCheck returns a Check that invokes the idempotent unary method
+// (e.g. "/inventory.v1.Inventory/GetItem") with the deadline of the timeout,
+// failing if it returns a status other than OK or the codes set by WithCodes.
+// The reply is discarded.
+func MethodCheck(conn grpc.ClientConnInterface, method string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := config{request: &emptypb.Empty{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func() error {
+		if conn == nil {
+			return errors.New("grpc connection is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := conn.Invoke(ctx, method, cfg.request, &emptypb.Empty{})
+		if err != nil && slices.Contains(cfg.codes, status.Code(err)) {
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,87 @@
+package grpcprobe
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func newServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServiceCheck(t *testing.T) {
+	conn := newServer(t)
+
+	tests := []struct {
+		name    string
+		service string
+		expect  error
+	}{
+		{name: "exposed service", service: "grpc.health.v1.Health"},
+		{name: "missing service", service: "inventory.v1.Inventory", expect: ErrServiceNotFound},
+	}
+
+	for _, tt := range tests {
+		err := ServiceCheck(conn, tt.service, time.Second)()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestMethodCheck(t *testing.T) {
+	conn := newServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		opts   []Option
+		fails  bool
+	}{
+		{name: "passing method", method: "/grpc.health.v1.Health/Check"},
+		{
+			name:   "accepted code",
+			method: "/grpc.health.v1.Health/Check",
+			opts:   []Option{WithRequest(&healthpb.HealthCheckRequest{Service: "missing"}), WithCodes(codes.NotFound)},
+		},
+		{
+			name:   "failing method",
+			method: "/grpc.health.v1.Health/Check",
+			opts:   []Option{WithRequest(&healthpb.HealthCheckRequest{Service: "missing"})},
+			fails:  true,
+		},
+		{name: "unimplemented method", method: "/inventory.v1.Inventory/GetItem", fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := MethodCheck(conn, tt.method, time.Second, tt.opts...)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
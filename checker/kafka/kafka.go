@@ -1,12 +1,13 @@
 package kafka
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net"
 	"time"
 
 	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/checker/misc"
 )
 
 // Checker Name is the name of the Kafka checker for
@@ -16,23 +17,32 @@ const CheckerName = "kafka"
 // DialCheck executes TCP dial to all Kafka endpoints
 // and returns an error if all endpoints returned errors.
 // If at least one node is alive, it will return OK.
+// See DialCheckWithOptions for TLS and SASL endpoints.
 func DialCheck(endpoints []string, timeout time.Duration) healthcheck.Check {
+	return DialCheckWithOptions(endpoints, timeout, DialOptions{})
+}
+
+// DialOptions configures the connections of DialCheckWithOptions.
+type DialOptions struct {
+	// DialOptions configures the TLS handshake, the dialer and the source address.
+	misc.DialOptions
+	// SASL authenticates the connections once connected if set.
+	SASL *SASL
+}
+
+// DialCheckWithOptions connects to all Kafka endpoints, completing the TLS
+// handshake and the SASL authentication if configured, and returns an error
+// if all endpoints returned errors. If at least one node is alive, it will return OK.
+func DialCheckWithOptions(endpoints []string, timeout time.Duration, opts DialOptions) healthcheck.Check {
 	return func() error {
 		if len(endpoints) == 0 {
 			return errors.New("empty kafka endpoints")
 		}
 
-		var errorsList []error
+		var errorsList dialErrors
 
 		for _, ep := range endpoints {
-			conn, err := net.DialTimeout("tcp", ep, timeout)
-			if err != nil {
-				errorsList = append(errorsList, err)
-				continue
-			}
-
-			err = conn.Close()
-			if err != nil {
+			if err := dial(ep, timeout, opts); err != nil {
 				errorsList = append(errorsList, err)
 				continue
 			}
@@ -40,6 +50,38 @@ func DialCheck(endpoints []string, timeout time.Duration) healthcheck.Check {
 			return nil
 		}
 
-		return fmt.Errorf("%s", errorsList)
+		return errorsList
+	}
+}
+
+// dialErrors are the errors of the endpoints, matched by errors.Is and errors.As.
+type dialErrors []error
+
+func (e dialErrors) Error() string {
+	return fmt.Sprintf("%s", []error(e))
+}
+
+func (e dialErrors) Unwrap() []error {
+	return e
+}
+
+func dial(endpoint string, timeout time.Duration, opts DialOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := opts.Dial(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if opts.SASL == nil {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
 	}
+	return opts.SASL.authenticate(conn)
 }
@@ -0,0 +1,107 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// SASLPlain is the PLAIN SASL mechanism, the only one supported by DialCheckWithOptions.
+const SASLPlain = "PLAIN"
+
+// Kafka API keys and versions of the SASL requests.
+const (
+	apiKeySaslHandshake    = 17
+	apiKeySaslAuthenticate = 36
+
+	saslHandshakeVersion    = 1
+	saslAuthenticateVersion = 0
+)
+
+// maxResponseSize is the maximum size of the SASL responses read.
+const maxResponseSize = 1 << 20
+
+// ErrSASLAuthentication is the error of SASL credentials rejected by the broker.
+var ErrSASLAuthentication = errors.New("kafka sasl authentication failed")
+
+// SASL are the SASL credentials of the brokers.
+type SASL struct {
+	// Mechanism is the SASL mechanism, SASLPlain by default.
+	Mechanism string
+	Username  string
+	Password  string
+}
+
+// authenticate authenticates the connection with the SASL handshake
+// and authenticate requests of the Kafka protocol.
+func (s *SASL) authenticate(conn net.Conn) error {
+	mechanism := s.Mechanism
+	if mechanism == "" {
+		mechanism = SASLPlain
+	}
+	if mechanism != SASLPlain {
+		return fmt.Errorf("unsupported sasl mechanism %q", mechanism)
+	}
+
+	resp, err := roundTrip(conn, apiKeySaslHandshake, saslHandshakeVersion, 1, appendString(nil, mechanism))
+	if err != nil {
+		return fmt.Errorf("sasl handshake: %w", err)
+	}
+	if len(resp) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	if code := int16(binary.BigEndian.Uint16(resp)); code != 0 {
+		return fmt.Errorf("sasl handshake: mechanism %s not enabled (error code %d)", mechanism, code)
+	}
+
+	token := []byte("\x00" + s.Username + "\x00" + s.Password)
+	body := binary.BigEndian.AppendUint32(nil, uint32(len(token)))
+	resp, err = roundTrip(conn, apiKeySaslAuthenticate, saslAuthenticateVersion, 2, append(body, token...))
+	if err != nil {
+		return fmt.Errorf("sasl authenticate: %w", err)
+	}
+	if len(resp) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	if code := int16(binary.BigEndian.Uint16(resp)); code != 0 {
+		return fmt.Errorf("%w: error code %d", ErrSASLAuthentication, code)
+	}
+	return nil
+}
+
+// roundTrip sends the request with a v1 header and returns the body of the response.
+func roundTrip(conn net.Conn, apiKey, apiVersion int16, correlationID int32, body []byte) ([]byte, error) {
+	req := make([]byte, 4, 64+len(body))
+	req = binary.BigEndian.AppendUint16(req, uint16(apiKey))
+	req = binary.BigEndian.AppendUint16(req, uint16(apiVersion))
+	req = binary.BigEndian.AppendUint32(req, uint32(correlationID))
+	req = appendString(req, "healthcheck")
+	req = append(req, body...)
+	binary.BigEndian.PutUint32(req, uint32(len(req)-4))
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	var header [8]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:4])
+	if size < 4 || size > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", size)
+	}
+	if id := int32(binary.BigEndian.Uint32(header[4:])); id != correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d", id)
+	}
+	resp := make([]byte, size-4)
+	_, err := io.ReadFull(conn, resp)
+	return resp, err
+}
+
+// appendString appends the string with its int16 length.
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
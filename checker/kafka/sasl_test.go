@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeBroker answers the SASL requests of the Kafka protocol,
+// accepting the PLAIN credentials "user"/"secret".
+func fakeBroker(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go serveSASL(conn)
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func serveSASL(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		apiKey, correlationID := binary.BigEndian.Uint16(req), req[4:8]
+		clientIDLen := binary.BigEndian.Uint16(req[8:])
+		body := req[10+clientIDLen:]
+
+		resp := append([]byte{}, correlationID...)
+		switch apiKey {
+		case apiKeySaslHandshake:
+			resp = append(resp, 0, 0) // no error
+			resp = binary.BigEndian.AppendUint32(resp, 1)
+			resp = appendString(resp, SASLPlain)
+		case apiKeySaslAuthenticate:
+			if bytes.Equal(body[4:], []byte("\x00user\x00secret")) {
+				resp = append(resp, 0, 0)
+			} else {
+				resp = append(resp, 0, 58) // SASL_AUTHENTICATION_FAILED
+			}
+			resp = append(resp, 0xff, 0xff, 0, 0, 0, 0) // null message, empty bytes
+		}
+		_, _ = conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(resp))), resp...))
+	}
+}
+
+func TestDialCheckWithOptions(t *testing.T) {
+	addr := fakeBroker(t)
+
+	tests := []struct {
+		name   string
+		sasl   *SASL
+		fails  bool
+		expect error
+	}{
+		{name: "without sasl"},
+		{name: "valid credentials", sasl: &SASL{Username: "user", Password: "secret"}},
+		{name: "invalid credentials", sasl: &SASL{Username: "user", Password: "wrong"}, fails: true, expect: ErrSASLAuthentication},
+		{name: "unsupported mechanism", sasl: &SASL{Mechanism: "SCRAM-SHA-512"}, fails: true},
+	}
+
+	for _, tt := range tests {
+		err := DialCheckWithOptions([]string{addr}, time.Second, DialOptions{SASL: tt.sasl})()
+		if (err != nil) != tt.fails || (tt.expect != nil && !errors.Is(err, tt.expect)) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package lock provides checks of the distributed lock backends, since an outage
+// of the lock backend breaks the correctness of the workers relying on it long
+// before they fail otherwise. The Redis and Redlock lockers are provided:
+//
+//	locker := lock.NewRedisLocker(client)
+//	handler.AddReadinessCheck("lock", lock.AcquireReleaseCheck(locker, "healthcheck:lock:"+hostname, 5*time.Second, time.Second))
+//
+// The probe lock must be dedicated to the instance (e.g. suffixed with its
+// hostname), so the probes of several instances don't contend for it.
+// Other backends (etcd leases, ZooKeeper locks...) are checked by implementing
+// Locker, e.g. with TryLock and Unlock of a concurrency.Mutex of the etcd client.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+var (
+	// ErrNotAcquired is the error of a lock held by someone else.
+	ErrNotAcquired = errors.New("lock not acquired")
+	// ErrNotReleased is the error of a lock acquired by the check but not released.
+	ErrNotReleased = errors.New("lock not released")
+	// ErrNoQuorum is the error of a Redlock whose lock isn't acquired on a majority of the instances.
+	ErrNoQuorum = errors.New("lock quorum not reached")
+)
+
+// Locker is a distributed lock backend.
+type Locker interface {
+	// TryLock acquires the lock held for ttl without waiting and returns the func
+	// releasing it, or ErrNotAcquired if it's held by someone else.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(context.Context) error, err error)
+}
+
+// LockerFunc is an adapter allowing the use of an ordinary function as a Locker.
+type LockerFunc func(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error)
+
+// TryLock calls f(ctx, key, ttl).
+func (f LockerFunc) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	return f(ctx, key, ttl)
+}
+
+// Inspector observes a distributed lock without acquiring it.
+type Inspector interface {
+	// Held returns true if the lock is held.
+	Held(ctx context.Context, key string) (bool, error)
+}
+
+// AcquireReleaseCheck returns a Check acquiring the lock for ttl and releasing it
+// right away, failing if either doesn't complete within timeout: with ErrNotAcquired
+// if the lock is held by someone else, with ErrNotReleased if the release fails
+// (the lock is then only released once its ttl expires).
+func AcquireReleaseCheck(locker Locker, key string, ttl, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if locker == nil {
+			return errors.New("locker is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		release, err := locker.TryLock(ctx, key, ttl)
+		if err != nil {
+			return fmt.Errorf("acquire %s: %w", key, err)
+		}
+		if err := release(ctx); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrNotReleased, key, err)
+		}
+		return nil
+	}
+}
+
+// ObserveCheck returns a Check inspecting the lock without acquiring it, failing
+// only if the backend can't be reached within timeout, e.g. for the services not
+// allowed to write to the lock backend. The check reports whether the lock is held
+// as the "held" detail.
+func ObserveCheck(name string, inspector Inspector, key string, timeout time.Duration) healthcheck.Checker {
+	return &observeChecker{name: name, inspector: inspector, key: key, timeout: timeout}
+}
+
+// observeChecker is the Checker returned by ObserveCheck.
+type observeChecker struct {
+	name      string
+	inspector Inspector
+	key       string
+	timeout   time.Duration
+}
+
+func (c *observeChecker) Name() string {
+	return c.name
+}
+
+func (c *observeChecker) Check(ctx context.Context) healthcheck.Result {
+	if c.inspector == nil {
+		return healthcheck.Result{Err: errors.New("lock inspector is nil")}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	held, err := c.inspector.Held(ctx, c.key)
+	if err != nil {
+		return healthcheck.Result{Err: fmt.Errorf("inspect %s: %w", c.key, err)}
+	}
+	return healthcheck.Result{Details: map[string]any{"held": held}}
+}
+
+// Redlock returns a Locker acquiring the lock on a majority of independent
+// lockers (typically the RedisLockers of independent Redis masters) as per the
+// Redlock algorithm: the lock is acquired if a majority is reached before the
+// ttl elapses, otherwise it's released everywhere and ErrNoQuorum is returned.
+func Redlock(lockers ...Locker) Locker {
+	return LockerFunc(func(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+		start := time.Now()
+
+		var (
+			releases []func(context.Context) error
+			errs     []error
+		)
+		for _, locker := range lockers {
+			release, err := locker.TryLock(ctx, key, ttl)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			releases = append(releases, release)
+		}
+
+		releaseAll := func(ctx context.Context) error {
+			var errs []error
+			for _, release := range releases {
+				if err := release(ctx); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			// a majority released is enough for the lock to be acquirable again
+			if len(errs) > 0 && len(releases)-len(errs) <= len(lockers)/2 {
+				return errors.Join(errs...)
+			}
+			return nil
+		}
+
+		if len(releases) <= len(lockers)/2 || time.Since(start) >= ttl {
+			_ = releaseAll(ctx)
+			return nil, fmt.Errorf("%w: %d of %d: %w", ErrNoQuorum, len(releases), len(lockers), errors.Join(errs...))
+		}
+		return releaseAll, nil
+	})
+}
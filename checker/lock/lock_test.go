@@ -0,0 +1,150 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis is an in-memory RedisClient.
+type fakeRedis struct {
+	mu       sync.Mutex
+	keys     map[string]string
+	down     bool
+	loseKeys bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{keys: make(map[string]string)}
+}
+
+var errDown = errors.New("connection refused")
+
+func (f *fakeRedis) SetNX(_ context.Context, key string, value any, _ time.Duration) *redis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down {
+		return redis.NewBoolResult(false, errDown)
+	}
+	if _, ok := f.keys[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	if !f.loseKeys {
+		f.keys[key] = value.(string)
+	}
+	return redis.NewBoolResult(true, nil)
+}
+
+func (f *fakeRedis) Eval(_ context.Context, _ string, keys []string, args ...any) *redis.Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down {
+		return redis.NewCmdResult(nil, errDown)
+	}
+	if f.keys[keys[0]] == args[0] {
+		delete(f.keys, keys[0])
+		return redis.NewCmdResult(int64(1), nil)
+	}
+	return redis.NewCmdResult(int64(0), nil)
+}
+
+func (f *fakeRedis) Exists(_ context.Context, keys ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down {
+		return redis.NewIntResult(0, errDown)
+	}
+	_, ok := f.keys[keys[0]]
+	if ok {
+		return redis.NewIntResult(1, nil)
+	}
+	return redis.NewIntResult(0, nil)
+}
+
+func TestAcquireReleaseCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(f *fakeRedis)
+		expect error
+	}{
+		{
+			name:  "acquired and released",
+			setup: func(*fakeRedis) {},
+		},
+		{
+			name:   "held by someone else",
+			setup:  func(f *fakeRedis) { f.keys["probe"] = "other" },
+			expect: ErrNotAcquired,
+		},
+		{
+			name:   "expired before the release",
+			setup:  func(f *fakeRedis) { f.loseKeys = true },
+			expect: ErrNotReleased,
+		},
+		{
+			name:   "backend down",
+			setup:  func(f *fakeRedis) { f.down = true },
+			expect: errDown,
+		},
+	}
+
+	for _, tt := range tests {
+		f := newFakeRedis()
+		tt.setup(f)
+
+		err := AcquireReleaseCheck(NewRedisLocker(f), "probe", time.Second, time.Second)()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+		if _, held := f.keys["probe"]; held && tt.expect == nil {
+			t.Errorf("Lock of %s not released", tt.name)
+		}
+	}
+}
+
+func TestRedlock(t *testing.T) {
+	instances := []*fakeRedis{newFakeRedis(), newFakeRedis(), newFakeRedis()}
+	lockers := make([]Locker, 0, len(instances))
+	for _, f := range instances {
+		lockers = append(lockers, NewRedisLocker(f))
+	}
+
+	// a minority down doesn't prevent the lock
+	instances[0].down = true
+	if err := AcquireReleaseCheck(Redlock(lockers...), "probe", time.Second, time.Second)(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+
+	// a majority down does, and the lock is released from the rest
+	instances[1].down = true
+	err := AcquireReleaseCheck(Redlock(lockers...), "probe", time.Second, time.Second)()
+	if !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrNoQuorum, err)
+	}
+	if len(instances[2].keys) != 0 {
+		t.Errorf("Lock not released from the minority: %v", instances[2].keys)
+	}
+}
+
+func TestObserveCheck(t *testing.T) {
+	f := newFakeRedis()
+	f.keys["jobs"] = "worker-1"
+	checker := ObserveCheck("lock", NewRedisLocker(f), "jobs", time.Second)
+
+	res := checker.Check(context.Background())
+	if res.Err != nil || res.Details["held"] != true {
+		t.Errorf("Wrong result\n"+"expected: %v\n"+"actual  : %v %v", "held", res.Err, res.Details)
+	}
+
+	f.down = true
+	if res := checker.Check(context.Background()); !errors.Is(res.Err, errDown) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", errDown, res.Err)
+	}
+}
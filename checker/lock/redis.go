@@ -0,0 +1,67 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock only if it's still held with the token.
+const releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// RedisClient is the part of the Redis clients used by RedisLocker,
+// implemented by *redis.Client, *redis.ClusterClient and *redis.Ring.
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// RedisLocker is a Locker and Inspector of the single instance Redis lock:
+// SET key token NX PX ttl, released by deleting the key if it still holds the token.
+type RedisLocker struct {
+	client RedisClient
+}
+
+// NewRedisLocker returns a RedisLocker on the client.
+func NewRedisLocker(client RedisClient) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+// TryLock implements Locker.
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b[:])
+
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotAcquired
+	}
+
+	return func(ctx context.Context) error {
+		deleted, err := l.client.Eval(ctx, releaseScript, []string{key}, token).Int()
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			// expired (or stolen) before the release
+			return ErrNotAcquired
+		}
+		return nil
+	}, nil
+}
+
+// Held implements Inspector.
+func (l *RedisLocker) Held(ctx context.Context, key string) (bool, error) {
+	n, err := l.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
@@ -0,0 +1,102 @@
+// Package minio provides checks of a MinIO deployment based on its cluster
+// health endpoints, which reflect the erasure coding quorum rather than the
+// availability of a single node as a generic S3 HeadBucket would:
+//
+//	handler.AddReadinessCheck("minio", minio.ClusterCheck("http://minio:9000", 5*time.Second))
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+var (
+	// ErrNoQuorum is the error of a cluster without read or write quorum.
+	ErrNoQuorum = errors.New("minio cluster has no quorum")
+	// ErrMaintenanceUnsafe is the error of a cluster which would lose its quorum
+	// if the node was taken down for maintenance, see WithMaintenance.
+	ErrMaintenanceUnsafe = errors.New("taking the minio node down would lose the quorum")
+)
+
+// Option configures the checks.
+type Option func(c *config)
+
+type config struct {
+	client      *http.Client
+	maintenance bool
+}
+
+// WithClient sets the HTTP client sending the requests, e.g. for TLS.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithMaintenance makes the check also fail if taking the node it's sent to down
+// for maintenance would make the cluster lose its quorum, e.g. as the readiness
+// of a node about to be restarted by a rolling update.
+func WithMaintenance() Option {
+	return func(c *config) {
+		c.maintenance = true
+	}
+}
+
+// ClusterCheck returns a Check failing when the cluster has no write quorum
+// (GET /minio/health/cluster).
+func ClusterCheck(endpoint string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	return check(endpoint, "/minio/health/cluster", "write", timeout, opts)
+}
+
+// ClusterReadCheck returns a Check failing when the cluster has no read quorum
+// (GET /minio/health/cluster/read), for read-only clients.
+func ClusterReadCheck(endpoint string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	return check(endpoint, "/minio/health/cluster/read", "read", timeout, opts)
+}
+
+func check(endpoint, path, quorum string, timeout time.Duration, opts []Option) healthcheck.Check {
+	cfg := config{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	url := strings.TrimSuffix(endpoint, "/") + path
+	if cfg.maintenance {
+		url += "?maintenance=true"
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusPreconditionFailed:
+			return ErrMaintenanceUnsafe
+		case http.StatusServiceUnavailable:
+			if n := resp.Header.Get("X-Minio-Write-Quorum"); n != "" && quorum == "write" {
+				return fmt.Errorf("%w: %s quorum of %s drives not met", ErrNoQuorum, quorum, n)
+			}
+			return fmt.Errorf("%w: %s quorum not met", ErrNoQuorum, quorum)
+		default:
+			return fmt.Errorf("%s responded with %s", path, resp.Status)
+		}
+	}
+}
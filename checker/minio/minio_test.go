@@ -0,0 +1,41 @@
+package minio
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecks(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("maintenance") == "true" && status == http.StatusOK {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("X-Minio-Write-Quorum", "3")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		status int
+		check  func() error
+		expect error
+	}{
+		{name: "healthy", status: http.StatusOK, check: ClusterCheck(server.URL, time.Second)},
+		{name: "no write quorum", status: http.StatusServiceUnavailable, check: ClusterCheck(server.URL, time.Second), expect: ErrNoQuorum},
+		{name: "no read quorum", status: http.StatusServiceUnavailable, check: ClusterReadCheck(server.URL, time.Second), expect: ErrNoQuorum},
+		{name: "maintenance unsafe", status: http.StatusOK, check: ClusterCheck(server.URL, time.Second, WithMaintenance()), expect: ErrMaintenanceUnsafe},
+	}
+
+	for _, tt := range tests {
+		status = tt.status
+		if err := tt.check(); !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong result of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package misc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DialOptions configures the connections of TCPDialCheckWithOptions.
+type DialOptions struct {
+	// TLSConfig makes the check complete a TLS handshake once connected. An
+	// empty ServerName is derived from the host of the address.
+	TLSConfig *tls.Config
+	// Dialer is the dialer of the connections, e.g. with a custom resolver
+	// or keep-alive. Its timeout is bounded by the timeout of the check.
+	Dialer *net.Dialer
+	// LocalAddr is the source address of the connections (e.g. "10.0.0.5:0"),
+	// to check the path from a specific interface.
+	LocalAddr string
+}
+
+// Dial connects to the address, then completes the TLS handshake if TLSConfig is set.
+func (o DialOptions) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	if o.Dialer != nil {
+		dialer = *o.Dialer
+	}
+	if o.LocalAddr != "" {
+		local, err := net.ResolveTCPAddr("tcp", o.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		dialer.LocalAddr = local
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil || o.TLSConfig == nil {
+		return conn, err
+	}
+
+	cfg := o.TLSConfig
+	if cfg.ServerName == "" {
+		cfg = cfg.Clone()
+		cfg.ServerName, _, _ = net.SplitHostPort(addr)
+	}
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// TCPDialCheckWithOptions returns a Check that checks the TCP connection
+// to the provided endpoint, and its TLS handshake if configured.
+func TCPDialCheckWithOptions(addr string, timeout time.Duration, opts DialOptions) healthcheck.Check {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		conn, err := opts.Dial(ctx, addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
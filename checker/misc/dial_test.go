@@ -0,0 +1,35 @@
+package misc
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTCPDialCheckWithOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+	addr := server.Listener.Addr().String()
+
+	tests := []struct {
+		name  string
+		opts  DialOptions
+		fails bool
+	}{
+		{name: "plain tcp", opts: DialOptions{}},
+		{name: "trusted tls", opts: DialOptions{TLSConfig: trusted}},
+		{name: "untrusted tls", opts: DialOptions{TLSConfig: &tls.Config{}}, fails: true},
+		{name: "source address", opts: DialOptions{TLSConfig: trusted, LocalAddr: "127.0.0.1:0"}},
+		{name: "invalid source address", opts: DialOptions{LocalAddr: "invalid"}, fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := TCPDialCheckWithOptions(addr, time.Second, tt.opts)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
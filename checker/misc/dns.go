@@ -0,0 +1,159 @@
+package misc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// dnsMessageType is the media type of DNS-over-HTTPS messages.
+const dnsMessageType = "application/dns-message"
+
+// dnsMaxSize is the maximum size of a DNS message.
+const dnsMaxSize = 65535
+
+// DoHCheck returns a Check that resolves the A records of the host with the
+// DNS-over-HTTPS resolver at the URL (e.g. https://dns.google/dns-query) using
+// the client, or http.DefaultClient if nil. The check fails if the resolver
+// doesn't answer with at least one record during the timeout.
+func DoHCheck(client *http.Client, url, host string, timeout time.Duration) healthcheck.Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		query, id, err := dnsQuery(host)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(query))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", dnsMessageType)
+		req.Header.Set("Accept", dnsMessageType)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+		answer, err := io.ReadAll(io.LimitReader(resp.Body, dnsMaxSize))
+		if err != nil {
+			return err
+		}
+		return checkDNSAnswer(answer, id)
+	}
+}
+
+// DoTCheck returns a Check that resolves the A records of the host with the
+// DNS-over-TLS resolver at the address (e.g. dns.google:853). The TLS
+// configuration may be nil, verifying the certificate against the host of
+// the address. The check fails if the resolver doesn't answer with at least
+// one record during the timeout.
+func DoTCheck(addr string, tlsConfig *tls.Config, host string, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		dialer := tls.Dialer{Config: tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				return err
+			}
+		}
+
+		query, id, err := dnsQuery(host)
+		if err != nil {
+			return err
+		}
+		// Messages over TCP are prefixed with their length.
+		if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(query)))); err != nil {
+			return err
+		}
+		if _, err := conn.Write(query); err != nil {
+			return err
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return err
+		}
+		answer := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, answer); err != nil {
+			return err
+		}
+		return checkDNSAnswer(answer, id)
+	}
+}
+
+// dnsQuery builds the query of the A records of the host and returns it with its ID.
+func dnsQuery(host string) ([]byte, uint16, error) {
+	name, err := dnsmessage.NewName(dnsFQDN(host))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, 0, err
+	}
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: binary.BigEndian.Uint16(id[:]), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  name,
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	query, err := msg.Pack()
+	return query, msg.Header.ID, err
+}
+
+// checkDNSAnswer checks the answer is a successful response to the query with the ID.
+func checkDNSAnswer(answer []byte, id uint16) error {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(answer); err != nil {
+		return fmt.Errorf("invalid dns answer: %w", err)
+	}
+	switch {
+	case !msg.Header.Response || msg.Header.ID != id:
+		return errors.New("dns answer doesn't match the query")
+	case msg.Header.RCode != dnsmessage.RCodeSuccess:
+		return fmt.Errorf("dns answer: %v", msg.Header.RCode)
+	case len(msg.Answers) == 0:
+		return fmt.Errorf("could not resolve host")
+	}
+	return nil
+}
+
+func dnsFQDN(host string) string {
+	if host == "" || host[len(host)-1] != '.' {
+		return host + "."
+	}
+	return host
+}
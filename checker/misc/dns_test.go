@@ -0,0 +1,115 @@
+package misc
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsAnswer answers the query with an A record of the known host,
+// or NXDOMAIN for any other host.
+func dnsAnswer(t *testing.T, query []byte) []byte {
+	t.Helper()
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(query); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+		return nil
+	}
+	msg.Header.Response = true
+	if q := msg.Questions[0]; q.Name.String() == "service.internal." {
+		msg.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+		}}
+	} else {
+		msg.Header.RCode = dnsmessage.RCodeNameError
+	}
+	answer, err := msg.Pack()
+	if err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	return answer
+}
+
+func TestDoHCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", dnsMessageType)
+		_, _ = w.Write(dnsAnswer(t, query))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		host  string
+		fails bool
+	}{
+		{name: "resolved host", host: "service.internal"},
+		{name: "unknown host", host: "missing.internal", fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := DoHCheck(server.Client(), server.URL+"/dns-query", tt.host, time.Second)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
+
+func TestDoTCheck(t *testing.T) {
+	// Borrow the certificate of a test TLS server.
+	certs := httptest.NewTLSServer(http.NotFoundHandler())
+	defer certs.Close()
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", certs.TLS)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	defer lis.Close()
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				answer := dnsAnswer(t, query)
+				_, _ = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(answer))), answer...))
+			}(conn)
+		}
+	}()
+
+	tlsConfig := certs.Client().Transport.(*http.Transport).TLSClientConfig
+	tests := []struct {
+		name  string
+		host  string
+		fails bool
+	}{
+		{name: "resolved host", host: "service.internal"},
+		{name: "unknown host", host: "missing.internal", fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := DoTCheck(lis.Addr().String(), tlsConfig, tt.host, time.Second)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package misc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// execOutputLimit is the maximum length of the output reported by ExecCheck.
+const execOutputLimit = 512
+
+// ExecCheck returns a Check that runs the command with the arguments, e.g. an
+// existing shell health script. The check fails if the command exits with a
+// non-zero code, reporting the code and the end of its stderr (or stdout if
+// empty), or if it doesn't exit during the timeout, in which case it's killed.
+func ExecCheck(cmd string, args []string, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		c := exec.CommandContext(ctx, cmd, args...)
+		c.Stdout, c.Stderr = &stdout, &stderr
+		// Don't wait for the children still holding the output once killed.
+		c.WaitDelay = time.Second
+
+		err := c.Run()
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s timed out after %v", cmd, timeout)
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+		output := strings.TrimSpace(stderr.String())
+		if output == "" {
+			output = strings.TrimSpace(stdout.String())
+		}
+		if len(output) > execOutputLimit {
+			output = "..." + output[len(output)-execOutputLimit:]
+		}
+		if output == "" {
+			return fmt.Errorf("%s exited with code %d", cmd, exitErr.ExitCode())
+		}
+		return fmt.Errorf("%s exited with code %d: %s", cmd, exitErr.ExitCode(), output)
+	}
+}
@@ -0,0 +1,41 @@
+package misc
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestExecCheck(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		expect string
+	}{
+		{name: "passing", script: "exit 0"},
+		{name: "failing with stderr", script: "echo ok; echo 'disk full' >&2; exit 2", expect: "sh exited with code 2: disk full"},
+		{name: "failing with stdout", script: "echo unhealthy; exit 1", expect: "sh exited with code 1: unhealthy"},
+		{name: "timed out", script: "sleep 5", expect: "sh timed out after 100ms"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ExecCheck("sh", []string{"-c", tt.script}, 100*time.Millisecond)()
+			var actual string
+			if err != nil {
+				actual = err.Error()
+			}
+			if actual != tt.expect {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package misc
+
+import (
+	"os"
+	"syscall"
+)
+
+// openFiles returns the number of open file descriptors
+// and the RLIMIT_NOFILE soft limit.
+func openFiles() (used, limit uint64, err error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var rlimit syscall.Rlimit
+	if err = syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+		return 0, 0, err
+	}
+
+	// ReadDir holds one descriptor for the directory itself
+	return uint64(len(entries) - 1), rlimit.Cur, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package misc
+
+import "errors"
+
+// openFiles isn't supported outside of Linux.
+func openFiles() (used, limit uint64, err error) {
+	return 0, 0, errors.New("open files check is not supported on this platform")
+}
@@ -0,0 +1,65 @@
+package misc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrNotHTTP2 is the error of an upstream which doesn't negotiate HTTP/2.
+var ErrNotHTTP2 = errors.New("http/2 not negotiated")
+
+// HTTP2Check returns a Check that executes an HTTP GET request to the URL over
+// HTTP/2: negotiated with ALPN for https:// URLs, h2c with prior knowledge for
+// http:// URLs. The check fails with ErrNotHTTP2 if the upstream silently
+// downgrades to HTTP/1.1, e.g. behind a misconfigured ingress, breaking gRPC
+// traffic. The status code isn't checked, so gRPC endpoints can be targeted.
+// The TLS configuration may be nil.
+func HTTP2Check(url string, timeout time.Duration, tlsConfig *tls.Config) healthcheck.Check {
+	var transport http.RoundTripper
+	if strings.HasPrefix(url, "http://") {
+		transport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
+			},
+		}
+	} else {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		if tlsConfig != nil {
+			t.TLSClientConfig = tlsConfig.Clone()
+		}
+		t.ForceAttemptHTTP2 = true
+		transport = t
+	}
+	client := http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// never follow redirects
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.ProtoMajor != 2 {
+			return fmt.Errorf("%w: upstream answered with %s", ErrNotHTTP2, resp.Proto)
+		}
+		return nil
+	}
+}
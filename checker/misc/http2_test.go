@@ -0,0 +1,53 @@
+package misc
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestHTTP2Check(t *testing.T) {
+	h2 := httptest.NewUnstartedServer(http.NotFoundHandler())
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	h1 := httptest.NewTLSServer(http.NotFoundHandler())
+	defer h1.Close()
+
+	cleartext := httptest.NewServer(h2c.NewHandler(http.NotFoundHandler(), &http2.Server{}))
+	defer cleartext.Close()
+
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+
+	tlsConfig := func(s *httptest.Server) *tls.Config {
+		return s.Client().Transport.(*http.Transport).TLSClientConfig
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		tlsConfig *tls.Config
+		fails     bool
+		expect    error
+	}{
+		{name: "h2", url: h2.URL, tlsConfig: tlsConfig(h2)},
+		{name: "downgraded to http/1.1", url: h1.URL, tlsConfig: tlsConfig(h1), fails: true, expect: ErrNotHTTP2},
+		{name: "h2c", url: cleartext.URL},
+		{name: "no h2c", url: plain.URL, fails: true},
+	}
+
+	for _, tt := range tests {
+		err := HTTP2Check(tt.url, time.Second, tt.tlsConfig)()
+		if (err != nil) != tt.fails || (tt.expect != nil && !errors.Is(err, tt.expect)) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
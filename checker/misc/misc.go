@@ -2,9 +2,11 @@ package misc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"runtime"
 	"time"
 
@@ -39,26 +41,57 @@ func DNSResolveCheck(host string, timeout time.Duration) healthcheck.Check {
 
 // TCPDialCheck returns a Check that checks the TCP connection to
 // the provided endpoint.
+// See TCPDialCheckWithOptions for TLS endpoints.
 func TCPDialCheck(addr string, timeout time.Duration) healthcheck.Check {
-	return func() error {
-		conn, err := net.DialTimeout("tcp", addr, timeout)
-		if err != nil {
-			return err
-		}
-		return conn.Close()
-	}
+	return TCPDialCheckWithOptions(addr, timeout, DialOptions{})
 }
 
 // HTTPGetCheck returns a checker that executes an HTTP GET request to the specified
 // URL. The check fails if the request is timed out or returns any code but 200 OK.
+// The requests go through the proxy of the environment (HTTP_PROXY, HTTPS_PROXY,
+// NO_PROXY), see HTTPGetCheckWithOptions for an explicit proxy.
 func HTTPGetCheck(url string, timeout time.Duration) healthcheck.Check {
-	client := http.Client{
+	return HTTPGetCheckWithOptions(url, timeout, HTTPOptions{})
+}
+
+// HTTPOptions configures the requests of HTTPGetCheckWithOptions.
+type HTTPOptions struct {
+	// Proxy returns the proxy of the requests, e.g. http.ProxyURL with an
+	// http://, https:// or socks5:// URL. The proxy of the environment
+	// (http.ProxyFromEnvironment) is used by default.
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLSConfig is the TLS configuration of https:// requests, e.g. for mTLS.
+	TLSConfig *tls.Config
+}
+
+// Client returns the HTTP client of the options, sharing the default transport
+// if neither the proxy nor the TLS configuration are set.
+func (o HTTPOptions) Client(timeout time.Duration) *http.Client {
+	client := &http.Client{
 		Timeout: timeout,
 		// never follow redirects
 		CheckRedirect: func(*http.Request, []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
+	if o.Proxy != nil || o.TLSConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if o.Proxy != nil {
+			transport.Proxy = o.Proxy
+		}
+		if o.TLSConfig != nil {
+			transport.TLSClientConfig = o.TLSConfig
+		}
+		client.Transport = transport
+	}
+	return client
+}
+
+// HTTPGetCheckWithOptions returns a checker that executes an HTTP GET request to the
+// specified URL with the options. The check fails if the request is timed out or
+// returns any code but 200 OK.
+func HTTPGetCheckWithOptions(url string, timeout time.Duration, opts HTTPOptions) healthcheck.Check {
+	client := opts.Client(timeout)
 	return func() error {
 		resp, err := client.Get(url)
 		if err != nil {
@@ -0,0 +1,39 @@
+package misc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHTTPGetCheckWithOptions(t *testing.T) {
+	// The proxy answers the requests of the hosts it knows.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "service.internal" {
+			http.Error(w, "unknown host", http.StatusBadGateway)
+		}
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	opts := HTTPOptions{Proxy: http.ProxyURL(proxyURL)}
+
+	tests := []struct {
+		name  string
+		url   string
+		opts  HTTPOptions
+		fails bool
+	}{
+		{name: "through the proxy", url: "http://service.internal/health", opts: opts},
+		{name: "unknown host of the proxy", url: "http://missing.internal/health", opts: opts, fails: true},
+		{name: "without the proxy", url: "http://service.invalid/health", fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := HTTPGetCheckWithOptions(tt.url, time.Second, tt.opts)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
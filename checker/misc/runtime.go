@@ -0,0 +1,177 @@
+package misc
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+const (
+	HeapUsage     = "heap_threshold"
+	OpenFiles     = "open_files_threshold"
+	GCCPUFraction = "gc_cpu_threshold"
+
+	// DefaultMaxGoroutines is the default goroutines threshold of RuntimeChecks.
+	DefaultMaxGoroutines = 10000
+	// DefaultMaxHeapRatio is the default share of the memory limit the heap may use.
+	DefaultMaxHeapRatio = 0.9
+	// DefaultMaxOpenFilesRatio is the default share of RLIMIT_NOFILE that may be open.
+	DefaultMaxOpenFilesRatio = 0.9
+	// DefaultMaxGCCPUFraction is the default share of CPU time the GC may use.
+	DefaultMaxGCCPUFraction = 0.5
+)
+
+// RuntimeOptions configures the thresholds of RuntimeChecks.
+// Zero values are replaced with container-aware defaults,
+// negative values disable the corresponding check.
+type RuntimeOptions struct {
+	// MaxGoroutines is the goroutines count threshold,
+	// DefaultMaxGoroutines by default.
+	MaxGoroutines int
+	// MaxHeapBytes is the heap size threshold, DefaultMaxHeapRatio of
+	// the memory limit (cgroup limit or GOMEMLIMIT) by default.
+	// The check is skipped if there's no limit to derive it from.
+	MaxHeapBytes int64
+	// MaxOpenFilesRatio is the threshold of open file descriptors relative to
+	// the RLIMIT_NOFILE soft limit, DefaultMaxOpenFilesRatio by default.
+	// The check is skipped on platforms where it's not supported.
+	MaxOpenFilesRatio float64
+	// MaxGCCPUFraction is the threshold of CPU time spent in GC since
+	// the previous check run, DefaultMaxGCCPUFraction by default.
+	MaxGCCPUFraction float64
+}
+
+// RuntimeChecks registers a curated set of process-level liveness checks
+// (goroutines, heap, open files, GC) on the handler in one call.
+func RuntimeChecks(h healthcheck.CheckRegistrar, opts RuntimeOptions) {
+	if opts.MaxGoroutines == 0 {
+		opts.MaxGoroutines = DefaultMaxGoroutines
+	}
+	if opts.MaxGoroutines > 0 {
+		h.AddLivenessCheck(GoroutinesCount, GoroutineCountCheck(opts.MaxGoroutines))
+	}
+
+	if opts.MaxHeapBytes == 0 {
+		if limit := memoryLimit(); limit > 0 {
+			opts.MaxHeapBytes = int64(float64(limit) * DefaultMaxHeapRatio)
+		}
+	}
+	if opts.MaxHeapBytes > 0 {
+		h.AddLivenessCheck(HeapUsage, HeapCheck(opts.MaxHeapBytes))
+	}
+
+	if opts.MaxOpenFilesRatio == 0 {
+		opts.MaxOpenFilesRatio = DefaultMaxOpenFilesRatio
+	}
+	if _, _, err := openFiles(); opts.MaxOpenFilesRatio > 0 && err == nil {
+		h.AddLivenessCheck(OpenFiles, OpenFilesCheck(opts.MaxOpenFilesRatio))
+	}
+
+	if opts.MaxGCCPUFraction == 0 {
+		opts.MaxGCCPUFraction = DefaultMaxGCCPUFraction
+	}
+	if opts.MaxGCCPUFraction > 0 {
+		h.AddLivenessCheck(GCCPUFraction, GCCPUFractionCheck(opts.MaxGCCPUFraction))
+	}
+}
+
+// HeapCheck returns a checker that fails if the heap
+// (allocated objects) grows beyond the threshold in bytes.
+func HeapCheck(threshold int64) healthcheck.Check {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	return func() error {
+		metrics.Read(sample)
+		heap := int64(sample[0].Value.Uint64())
+		if heap > threshold {
+			return fmt.Errorf("heap is too large (%d > %d bytes)", heap, threshold)
+		}
+		return nil
+	}
+}
+
+// OpenFilesCheck returns a checker that fails if the process uses
+// more than the given ratio of the open file descriptors limit.
+func OpenFilesCheck(ratio float64) healthcheck.Check {
+	return func() error {
+		used, limit, err := openFiles()
+		if err != nil {
+			return err
+		}
+		if float64(used) > float64(limit)*ratio {
+			return fmt.Errorf("too many open files (%d of %d allowed)", used, limit)
+		}
+		return nil
+	}
+}
+
+// GCCPUFractionCheck returns a checker that fails if the garbage collector
+// used more than the given fraction of the CPU time since the previous run
+// (which usually means the process is thrashing near its memory limit).
+func GCCPUFractionCheck(threshold float64) healthcheck.Check {
+	var (
+		mu        sync.Mutex
+		prevGC    float64
+		prevTotal float64
+		sample    = []metrics.Sample{
+			{Name: "/cpu/classes/gc/total:cpu-seconds"},
+			{Name: "/cpu/classes/total:cpu-seconds"},
+		}
+	)
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		metrics.Read(sample)
+		gc, total := sample[0].Value.Float64(), sample[1].Value.Float64()
+		deltaGC, deltaTotal := gc-prevGC, total-prevTotal
+		prevGC, prevTotal = gc, total
+
+		if deltaTotal <= 0 {
+			return nil
+		}
+		if fraction := deltaGC / deltaTotal; fraction > threshold {
+			return fmt.Errorf("gc uses too much cpu (%.2f > %.2f)", fraction, threshold)
+		}
+		return nil
+	}
+}
+
+// memoryLimit returns the memory limit of the process: the cgroup (v2 or v1)
+// limit of the container or the GOMEMLIMIT soft limit, whichever is lower.
+// Zero means there's no limit.
+func memoryLimit() uint64 {
+	var limit uint64
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",                   // cgroup v2
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes", // cgroup v1
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil { // "max" means unlimited
+			break
+		}
+		// cgroup v1 reports a huge page-aligned number when unlimited
+		if value < math.MaxInt64/2 {
+			limit = value
+		}
+		break
+	}
+
+	if goLimit := debug.SetMemoryLimit(-1); goLimit > 0 && goLimit < math.MaxInt64 {
+		if limit == 0 || uint64(goLimit) < limit {
+			limit = uint64(goLimit)
+		}
+	}
+
+	return limit
+}
@@ -0,0 +1,137 @@
+package misc
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// fakeRegistrar records the names of the registered liveness checks.
+type fakeRegistrar struct {
+	healthcheck.CheckRegistrar
+
+	liveness []string
+}
+
+func (r *fakeRegistrar) AddLivenessCheck(name string, _ healthcheck.Check, _ ...healthcheck.CheckOption) {
+	r.liveness = append(r.liveness, name)
+}
+
+func TestRuntimeChecks(t *testing.T) {
+	_, _, openFilesErr := openFiles()
+
+	tests := []struct {
+		name   string
+		opts   RuntimeOptions
+		expect []string
+	}{
+		{
+			name:   "explicit heap threshold",
+			opts:   RuntimeOptions{MaxHeapBytes: 1 << 30},
+			expect: []string{GCCPUFraction, GoroutinesCount, HeapUsage, OpenFiles},
+		},
+		{
+			name:   "disabled checks",
+			opts:   RuntimeOptions{MaxGoroutines: -1, MaxHeapBytes: -1, MaxOpenFilesRatio: -1, MaxGCCPUFraction: -1},
+			expect: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var registrar fakeRegistrar
+			RuntimeChecks(&registrar, tt.opts)
+
+			expect := make([]string, 0, len(tt.expect))
+			for _, name := range tt.expect {
+				// the open files check is only registered where it's supported
+				if name != OpenFiles || openFilesErr == nil {
+					expect = append(expect, name)
+				}
+			}
+			sort.Strings(registrar.liveness)
+			if strings.Join(registrar.liveness, ",") != strings.Join(expect, ",") {
+				t.Errorf("Wrong checks\n"+"expected: %v\n"+"actual  : %v", expect, registrar.liveness)
+			}
+		})
+	}
+}
+
+func TestHeapCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int64
+		fails     bool
+	}{
+		{name: "below the threshold", threshold: math.MaxInt64},
+		{name: "above the threshold", threshold: 1, fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := HeapCheck(tt.threshold)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
+
+func TestOpenFilesCheck(t *testing.T) {
+	if _, _, err := openFiles(); err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name  string
+		ratio float64
+		fails bool
+	}{
+		{name: "below the threshold", ratio: 1},
+		{name: "above the threshold", ratio: 0, fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := OpenFilesCheck(tt.ratio)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
+
+func TestGCCPUFractionCheck(t *testing.T) {
+	// the CPU time metrics are updated by the collections
+	runtime.GC()
+
+	tests := []struct {
+		name      string
+		threshold float64
+		fails     bool
+	}{
+		{name: "below the threshold", threshold: 1},
+		{name: "above the threshold", threshold: -1, fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := GCCPUFractionCheck(tt.threshold)(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	prev := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(prev)
+
+	// the cgroup limit of the container may be lower than GOMEMLIMIT
+	const goLimit = 64 << 20
+	debug.SetMemoryLimit(goLimit)
+	if limit := memoryLimit(); limit == 0 || limit > goLimit {
+		t.Errorf("Wrong memory limit\n"+"expected: %v at most\n"+"actual  : %v", goLimit, limit)
+	}
+}
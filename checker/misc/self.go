@@ -0,0 +1,44 @@
+package misc
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// SelfDialCheck returns a Check that dials the listening address of the
+// service's own main server (e.g. ":8080" or the Addr of its net.Listener),
+// confirming it's bound and accepting connections when the health endpoints
+// are served on another port. Unspecified hosts are dialed on the loopback.
+func SelfDialCheck(addr string, timeout time.Duration) healthcheck.Check {
+	return TCPDialCheck(loopbackAddr(addr), timeout)
+}
+
+// SelfHTTPCheck returns a Check that executes an HTTP GET request to the path
+// (e.g. "/ping") of the service's own main server at its listening address,
+// confirming it's not only bound but also serving requests. The check fails
+// if the request is timed out or returns any code but 200 OK.
+func SelfHTTPCheck(addr, path string, timeout time.Duration) healthcheck.Check {
+	u := url.URL{Scheme: "http", Host: loopbackAddr(addr), Path: path}
+	return HTTPGetCheck(u.String(), timeout)
+}
+
+// loopbackAddr returns the address dialing the listening address,
+// replacing its host with the loopback if it's unspecified.
+func loopbackAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	ip := net.ParseIP(host)
+	switch {
+	case host == "" || (ip != nil && ip.To4() != nil && ip.IsUnspecified()):
+		host = "127.0.0.1"
+	case ip != nil && ip.IsUnspecified():
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,54 @@
+package misc
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		expect string
+	}{
+		{addr: ":8080", expect: "127.0.0.1:8080"},
+		{addr: "0.0.0.0:8080", expect: "127.0.0.1:8080"},
+		{addr: "[::]:8080", expect: "[::1]:8080"},
+		{addr: "10.0.0.1:8080", expect: "10.0.0.1:8080"},
+		{addr: "localhost:8080", expect: "localhost:8080"},
+	}
+
+	for _, tt := range tests {
+		if actual := loopbackAddr(tt.addr); actual != tt.expect {
+			t.Errorf("Wrong address of %s\n"+"expected: %v\n"+"actual  : %v", tt.addr, tt.expect, actual)
+		}
+	}
+}
+
+func TestSelfChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ping" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	addr := ":" + port
+
+	if err := SelfDialCheck(addr, time.Second)(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	if err := SelfHTTPCheck(addr, "/ping", time.Second)(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	if err := SelfHTTPCheck(addr, "/missing", time.Second)(); err == nil {
+		t.Errorf("Expected the check of a missing path to fail")
+	}
+	server.Close()
+	if err := SelfDialCheck(addr, time.Second)(); err == nil {
+		t.Errorf("Expected the check of a closed listener to fail")
+	}
+}
@@ -0,0 +1,67 @@
+package misc
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// MaxUptime is the suggested name of the MaxUptimeCheck liveness check.
+const MaxUptime = "max_uptime"
+
+// ErrMaxUptime is the error of a MaxUptimeCheck once the process is too old.
+var ErrMaxUptime = errors.New("max uptime exceeded")
+
+// processStart approximates the start of the process by the initialization of the package.
+var processStart = time.Now()
+
+// uptimeCheck is the configuration of a MaxUptimeCheck.
+type uptimeCheck struct {
+	start time.Time
+	clock healthcheck.Clock
+}
+
+// UptimeOption configures a MaxUptimeCheck.
+type UptimeOption func(c *uptimeCheck)
+
+// WithUptimeClock sets the Clock measuring the uptime, healthcheck.SystemClock by default.
+func WithUptimeClock(clock healthcheck.Clock) UptimeOption {
+	return func(c *uptimeCheck) {
+		c.clock = clock
+	}
+}
+
+// WithProcessStart sets the time the uptime is measured from,
+// the initialization of the package by default.
+func WithProcessStart(start time.Time) UptimeOption {
+	return func(c *uptimeCheck) {
+		c.start = start
+	}
+}
+
+// MaxUptimeCheck returns a liveness check failing once the process has been
+// running for longer than maxAge plus a random jitter up to jitter, so the
+// orchestrator deliberately recycles long-lived instances (leaking memory,
+// stale caches...) through the normal probes, without all the replicas
+// started together being restarted at the same time:
+//
+//	h.AddLivenessCheck(misc.MaxUptime, misc.MaxUptimeCheck(24*time.Hour, time.Hour))
+func MaxUptimeCheck(maxAge, jitter time.Duration, opts ...UptimeOption) healthcheck.Check {
+	c := uptimeCheck{start: processStart, clock: healthcheck.SystemClock}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if jitter > 0 {
+		maxAge += rand.N(jitter)
+	}
+	return func() error {
+		if uptime := c.clock.Now().Sub(c.start); uptime > maxAge {
+			return fmt.Errorf("%w: running for %v (max %v)", ErrMaxUptime, uptime.Round(time.Second), maxAge.Round(time.Second))
+		}
+		return nil
+	}
+}
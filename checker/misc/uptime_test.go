@@ -0,0 +1,39 @@
+package misc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestMaxUptimeCheck(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		uptime time.Duration
+		jitter time.Duration
+		fails  bool
+	}{
+		{name: "young process", uptime: time.Hour},
+		{name: "old process", uptime: 23*time.Hour + time.Second, fails: true},
+		{name: "young process with jitter", uptime: 23 * time.Hour, jitter: time.Hour},
+		{name: "old process with jitter", uptime: 24*time.Hour + time.Second, jitter: time.Hour, fails: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := healthcheck.NewManualClock(start.Add(tt.uptime))
+			err := MaxUptimeCheck(23*time.Hour, tt.jitter, WithUptimeClock(clock), WithProcessStart(start))()
+			if errors.Is(err, ErrMaxUptime) != tt.fails {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.fails, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package mongo provides MongoDB checks going beyond a basic ping:
+// the availability of a primary in the replica set.
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// hello is the part of the hello (isMaster) command response the checks use.
+type hello struct {
+	IsWritablePrimary bool   `bson:"isWritablePrimary"`
+	IsMaster          bool   `bson:"ismaster"`
+	SetName           string `bson:"setName"`
+	Primary           string `bson:"primary"`
+	Msg               string `bson:"msg"`
+}
+
+// commandNotFound is the code of the error of an unknown command.
+const commandNotFound = 59
+
+// runHello executes the hello command, falling back to isMaster
+// for the servers older than MongoDB 4.4.
+func runHello(ctx context.Context, client *mongo.Client) (hello, error) {
+	var res hello
+	admin := client.Database("admin")
+
+	err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&res)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == commandNotFound {
+		err = admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&res)
+		res.IsWritablePrimary = res.IsMaster
+	}
+	return res, err
+}
+
+// PrimaryCheck returns a Check that fails when the replica set has no primary,
+// e.g. during an election or when a majority of its members are down: it runs
+// hello on a member and then selects a writable server from the driver topology.
+// For standalone servers and mongos routers it checks the server is writable.
+func PrimaryCheck(client *mongo.Client, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if client == nil {
+			return errors.New("mongo client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		res, err := runHello(ctx, client)
+		if err != nil {
+			return fmt.Errorf("hello: %w", err)
+		}
+		switch {
+		case res.SetName != "" && res.Primary == "":
+			return fmt.Errorf("replica set %s has no primary", res.SetName)
+		case res.SetName == "" && res.Msg != "isdbgrid" && !res.IsWritablePrimary:
+			return errors.New("server isn't writable")
+		}
+
+		if err := client.Ping(ctx, readpref.Primary()); err != nil {
+			return fmt.Errorf("no writable server: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPrimaryCheck(t *testing.T) {
+	ok := bson.D{{Key: "ok", Value: 1}}
+	pingFailed := mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 189, Name: "PrimarySteppedDown", Message: "stepped down"})
+
+	tests := []struct {
+		name      string
+		responses []bson.D
+		expectErr string
+	}{
+		{
+			name: "replica set with a primary",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "setName", Value: "rs0"}, {Key: "primary", Value: "db-0:27017"}},
+				ok,
+			},
+		},
+		{
+			name: "replica set without a primary",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "setName", Value: "rs0"}},
+			},
+			expectErr: "replica set rs0 has no primary",
+		},
+		{
+			name: "writable standalone server",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "isWritablePrimary", Value: true}},
+				ok,
+			},
+		},
+		{
+			name: "read-only standalone server",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "isWritablePrimary", Value: false}},
+			},
+			expectErr: "server isn't writable",
+		},
+		{
+			name: "mongos router",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "msg", Value: "isdbgrid"}},
+				ok,
+			},
+		},
+		{
+			name: "server without hello",
+			responses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: commandNotFound, Name: "CommandNotFound", Message: "no such command: 'hello'"}),
+				{{Key: "ok", Value: 1}, {Key: "ismaster", Value: true}},
+				ok,
+			},
+		},
+		{
+			name: "no writable server",
+			responses: []bson.D{
+				{{Key: "ok", Value: 1}, {Key: "setName", Value: "rs0"}, {Key: "primary", Value: "db-0:27017"}},
+				pingFailed,
+			},
+			expectErr: "no writable server: (PrimarySteppedDown) stepped down",
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tt := range tests {
+		tt := tt
+
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tt.responses...)
+
+			err := PrimaryCheck(mt.Client, time.Second)()
+			switch {
+			case tt.expectErr == "" && err != nil:
+				mt.Errorf("Received unexpected error:\n%+v", err)
+			case tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr):
+				mt.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestPrimaryCheckNilClient(t *testing.T) {
+	if err := PrimaryCheck(nil, time.Second)(); err == nil {
+		t.Errorf("Expected an error of the nil client")
+	}
+}
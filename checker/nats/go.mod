@@ -0,0 +1,22 @@
+module github.com/catalystgo/healthcheck/checker/nats
+
+go 1.22
+
+require (
+	github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+	github.com/catalystgo/healthcheck/checker/roundtrip v0.0.0-00010101000000-000000000000
+	github.com/nats-io/nats.go v1.37.0
+)
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+)
+
+replace (
+	github.com/catalystgo/healthcheck => ../..
+	github.com/catalystgo/healthcheck/checker/roundtrip => ../roundtrip
+)
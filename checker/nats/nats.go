@@ -0,0 +1,74 @@
+// Package nats provides a publish/consume round-trip check of NATS.
+package nats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/checker/roundtrip"
+)
+
+// RoundTripCheck returns a Check that subscribes to the health subject, publishes
+// a message to it and waits for it, failing with roundtrip.ErrNotConsumed if it
+// isn't received before the timeout.
+func RoundTripCheck(conn *nats.Conn, subject string, timeout time.Duration) healthcheck.Check {
+	b := broker{subject: subject}
+	if conn != nil {
+		b.conn = conn
+	}
+	return roundtrip.Check(b, timeout)
+}
+
+// connection is the part of *nats.Conn used by the check.
+type connection interface {
+	Subscribe(subject string, cb nats.MsgHandler) (*nats.Subscription, error)
+	Publish(subject string, data []byte) error
+	FlushWithContext(ctx context.Context) error
+}
+
+// broker is the roundtrip.Broker of a NATS subject. Every subscriber of a
+// subject receives its messages, so the executions of several instances don't
+// compete for them.
+type broker struct {
+	conn    connection
+	subject string
+}
+
+func (b broker) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	if b.conn == nil {
+		return nil, errors.New("nats connection is nil")
+	}
+
+	messages := make(chan []byte, 16)
+	sub, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		select {
+		case messages <- msg.Data:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the server registered the subscription before publishing.
+	if err := b.conn.FlushWithContext(ctx); err != nil {
+		_ = sub.Unsubscribe()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = sub.Unsubscribe()
+	}()
+	return messages, nil
+}
+
+func (b broker) Publish(ctx context.Context, payload []byte) error {
+	if err := b.conn.Publish(b.subject, payload); err != nil {
+		return err
+	}
+	return b.conn.FlushWithContext(ctx)
+}
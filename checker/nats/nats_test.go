@@ -0,0 +1,76 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/catalystgo/healthcheck/checker/roundtrip"
+)
+
+// fakeConn delivers the published messages to all the subscribers of the subject.
+type fakeConn struct {
+	mu          sync.Mutex
+	subscribers map[string][]nats.MsgHandler
+	lost        bool
+}
+
+func (c *fakeConn) Subscribe(subject string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscribers == nil {
+		c.subscribers = make(map[string][]nats.MsgHandler)
+	}
+	c.subscribers[subject] = append(c.subscribers[subject], cb)
+	return &nats.Subscription{Subject: subject}, nil
+}
+
+func (c *fakeConn) Publish(subject string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lost {
+		return nil
+	}
+	for _, cb := range c.subscribers[subject] {
+		cb(&nats.Msg{Subject: subject, Data: data})
+	}
+	return nil
+}
+
+func (c *fakeConn) FlushWithContext(context.Context) error {
+	return nil
+}
+
+func TestRoundTripCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		conn   connection
+		expect error
+	}{
+		{name: "consumed", conn: &fakeConn{}},
+		{name: "lost", conn: &fakeConn{lost: true}, expect: roundtrip.ErrNotConsumed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := roundtrip.Check(broker{conn: tt.conn, subject: "health"}, 100*time.Millisecond)()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+
+	if err := RoundTripCheck(nil, "health", time.Second)(); err == nil {
+		t.Errorf("Expected an error for a nil connection")
+	}
+}
@@ -0,0 +1,158 @@
+// Package postgres provides PostgreSQL specific checks, on top of the
+// connectivity checked by checker/db: the role of the server, the replication
+// lag, the connections usage, the long-running transactions and the
+// transaction ID wraparound vacuum. The checks work with any database/sql
+// driver (lib/pq, pgx/stdlib).
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// Role is the replication role of a server.
+type Role string
+
+const (
+	// RolePrimary is a server accepting writes.
+	RolePrimary Role = "primary"
+	// RoleStandby is a server in recovery, replicating a primary.
+	RoleStandby Role = "standby"
+)
+
+// DefaultMaxXIDAge is the default threshold of VacuumCheck, PostgreSQL forces
+// an anti-wraparound vacuum at 200 million and stops at about 2 billion.
+const DefaultMaxXIDAge = 1_000_000_000
+
+// query executes the query with the timeout and scans its single row into dest.
+func query(database *sql.DB, timeout time.Duration, q string, dest ...any) error {
+	if database == nil {
+		return errors.New("database is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return database.QueryRowContext(ctx, q).Scan(dest...)
+}
+
+// RoleCheck returns a Check that fails if the server doesn't have the expected
+// role according to pg_is_in_recovery(), e.g. after an unexpected failover
+// left the application connected to a standby.
+func RoleCheck(database *sql.DB, expected Role, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		var recovery bool
+		if err := query(database, timeout, "SELECT pg_is_in_recovery()", &recovery); err != nil {
+			return err
+		}
+
+		role := RolePrimary
+		if recovery {
+			role = RoleStandby
+		}
+		if role != expected {
+			return fmt.Errorf("server is a %s, expected a %s", role, expected)
+		}
+		return nil
+	}
+}
+
+// ReplicationLagCheck returns a Check executed on a primary that fails if the
+// replay lag of any standby in pg_stat_replication exceeds maxLag, or if
+// there are fewer than minStandbys connected standbys.
+func ReplicationLagCheck(database *sql.DB, maxLag time.Duration, minStandbys int, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		var (
+			standbys int
+			lag      float64
+		)
+		err := query(database, timeout, `SELECT count(*), COALESCE(MAX(EXTRACT(EPOCH FROM replay_lag)), 0)
+			FROM pg_stat_replication`, &standbys, &lag)
+		if err != nil {
+			return err
+		}
+
+		if standbys < minStandbys {
+			return fmt.Errorf("%d standbys connected, expected at least %d", standbys, minStandbys)
+		}
+		if l := seconds(lag); l > maxLag {
+			return fmt.Errorf("replication lag %v exceeds %v", l, maxLag)
+		}
+		return nil
+	}
+}
+
+// ConnectionsCheck returns a Check that fails if the share of max_connections
+// in use (0 to 1) exceeds maxRatio, before the clients start being rejected.
+func ConnectionsCheck(database *sql.DB, maxRatio float64, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		var used, limit int
+		err := query(database, timeout, `SELECT (SELECT count(*) FROM pg_stat_activity),
+			current_setting('max_connections')::int`, &used, &limit)
+		if err != nil {
+			return err
+		}
+
+		if limit > 0 && float64(used)/float64(limit) > maxRatio {
+			return fmt.Errorf("%d of %d connections in use (threshold %.0f%%)", used, limit, maxRatio*100)
+		}
+		return nil
+	}
+}
+
+// LongTransactionsCheck returns a Check that fails if a transaction has been
+// open for more than maxDuration, as they hold locks and prevent vacuum.
+func LongTransactionsCheck(database *sql.DB, maxDuration time.Duration, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		var (
+			pid      int64
+			duration float64
+		)
+		err := query(database, timeout, `SELECT COALESCE(MAX(pid), 0), COALESCE(MAX(EXTRACT(EPOCH FROM now() - xact_start)), 0)
+			FROM (SELECT pid, xact_start FROM pg_stat_activity
+				WHERE xact_start IS NOT NULL AND pid <> pg_backend_pid()
+				ORDER BY xact_start LIMIT 1) oldest`, &pid, &duration)
+		if err != nil {
+			return err
+		}
+
+		if d := seconds(duration); d > maxDuration {
+			return fmt.Errorf("transaction of backend %d open for %v (threshold %v)", pid, d, maxDuration)
+		}
+		return nil
+	}
+}
+
+// VacuumCheck returns a Check that fails if the age of the oldest unfrozen
+// transaction ID of a database exceeds maxAge (DefaultMaxXIDAge if 0),
+// i.e. vacuum doesn't keep up and the transaction ID wraparound approaches.
+func VacuumCheck(database *sql.DB, maxAge int64, timeout time.Duration) healthcheck.Check {
+	if maxAge == 0 {
+		maxAge = DefaultMaxXIDAge
+	}
+	return func() error {
+		var (
+			name string
+			age  int64
+		)
+		err := query(database, timeout, `SELECT datname, age(datfrozenxid) FROM pg_database
+			ORDER BY age(datfrozenxid) DESC LIMIT 1`, &name, &age)
+		if err != nil {
+			return err
+		}
+
+		if age > maxAge {
+			return fmt.Errorf("transaction ID age of database %s is %d (threshold %d)", name, age, maxAge)
+		}
+		return nil
+	}
+}
+
+// seconds converts a number of seconds returned by EXTRACT(EPOCH ...) to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
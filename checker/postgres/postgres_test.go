@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDriver answers every query with the single row registered
+// for the first registered fragment the query contains.
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows map[string][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (fakeConn) Close() error                                { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                   { return nil, driver.ErrSkip }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	for fragment, row := range s.d.rows {
+		if strings.Contains(s.query, fragment) {
+			return &fakeRows{row: row}, nil
+		}
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return make([]string, len(r.row))
+}
+
+func (*fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done || r.row == nil {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+var fake = &fakeDriver{rows: make(map[string][]driver.Value)}
+
+func init() {
+	sql.Register("postgres-fake", fake)
+}
+
+func TestChecks(t *testing.T) {
+	database, err := sql.Open("postgres-fake", "")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	defer database.Close()
+
+	tests := []struct {
+		name     string
+		fragment string
+		row      []driver.Value
+		check    func() error
+		fails    bool
+	}{
+		{
+			name:     "primary",
+			fragment: "pg_is_in_recovery",
+			row:      []driver.Value{false},
+			check:    RoleCheck(database, RolePrimary, time.Second),
+		},
+		{
+			name:     "unexpected standby",
+			fragment: "pg_is_in_recovery",
+			row:      []driver.Value{true},
+			check:    RoleCheck(database, RolePrimary, time.Second),
+			fails:    true,
+		},
+		{
+			name:     "replication lag",
+			fragment: "pg_stat_replication",
+			row:      []driver.Value{int64(2), 12.5},
+			check:    ReplicationLagCheck(database, 10*time.Second, 1, time.Second),
+			fails:    true,
+		},
+		{
+			name:     "missing standby",
+			fragment: "pg_stat_replication",
+			row:      []driver.Value{int64(0), 0.0},
+			check:    ReplicationLagCheck(database, 10*time.Second, 1, time.Second),
+			fails:    true,
+		},
+		{
+			name:     "connections",
+			fragment: "max_connections",
+			row:      []driver.Value{int64(50), int64(100)},
+			check:    ConnectionsCheck(database, 0.9, time.Second),
+		},
+		{
+			name:     "connections exhausted",
+			fragment: "max_connections",
+			row:      []driver.Value{int64(95), int64(100)},
+			check:    ConnectionsCheck(database, 0.9, time.Second),
+			fails:    true,
+		},
+		{
+			name:     "long transaction",
+			fragment: "xact_start",
+			row:      []driver.Value{int64(4242), 3600.0},
+			check:    LongTransactionsCheck(database, time.Minute, time.Second),
+			fails:    true,
+		},
+		{
+			name:     "vacuum",
+			fragment: "datfrozenxid",
+			row:      []driver.Value{"orders", int64(300_000_000)},
+			check:    VacuumCheck(database, 0, time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.mu.Lock()
+			fake.rows = map[string][]driver.Value{tt.fragment: tt.row}
+			fake.mu.Unlock()
+
+			if err := tt.check(); (err != nil) != tt.fails {
+				t.Errorf("Wrong result\n"+"expected failure: %v\n"+"actual  : %v", tt.fails, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package pubsub provides checks of Google Cloud Pub/Sub topics and
+// subscriptions, for event-driven services running in GCP.
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultEndpoint is the endpoint of the Pub/Sub REST API.
+const DefaultEndpoint = "https://pubsub.googleapis.com/v1"
+
+// pubsubScope is the OAuth2 scope of the access to Pub/Sub.
+const pubsubScope = "https://www.googleapis.com/auth/pubsub"
+
+// healthAttribute is the attribute identifying the messages published by RoundTripCheck.
+const healthAttribute = "healthcheck"
+
+// pullInterval is the pause of RoundTripCheck between empty pulls.
+const pullInterval = 50 * time.Millisecond
+
+// ErrMessageNotReceived is the error of a round trip whose message
+// isn't pulled from the subscription before the timeout.
+var ErrMessageNotReceived = errors.New("health message not received")
+
+// Option configures the checks.
+type Option func(c *config)
+
+type config struct {
+	endpoint string
+}
+
+// WithEndpoint sets the endpoint of the REST API, e.g. of the emulator
+// (http://localhost:8085/v1), DefaultEndpoint by default.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{endpoint: DefaultEndpoint}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// NewClient creates a client authenticated by the Application Default
+// Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud credentials,
+// workload identity or the metadata server) for the checks.
+func NewClient(ctx context.Context) (*http.Client, error) {
+	client, err := google.DefaultClient(ctx, pubsubScope)
+	if err != nil {
+		return nil, fmt.Errorf("application default credentials: %w", err)
+	}
+	return client, nil
+}
+
+// TopicCheck returns a Check that retrieves the topic, failing if it
+// doesn't exist, the credentials can't access it or the API is unreachable.
+func TopicCheck(client *http.Client, project, topic string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := newConfig(opts)
+	target := cfg.endpoint + "/projects/" + url.PathEscape(project) + "/topics/" + url.PathEscape(topic)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return call(ctx, client, http.MethodGet, target, nil, nil)
+	}
+}
+
+// SubscriptionCheck returns a Check that retrieves the subscription, failing if it
+// doesn't exist, the credentials can't access it or the API is unreachable.
+func SubscriptionCheck(client *http.Client, project, subscription string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := newConfig(opts)
+	target := cfg.endpoint + "/projects/" + url.PathEscape(project) + "/subscriptions/" + url.PathEscape(subscription)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return call(ctx, client, http.MethodGet, target, nil, nil)
+	}
+}
+
+// RoundTripCheck returns a Check that publishes a message to the health topic and
+// pulls the subscription until it receives it, failing with ErrMessageNotReceived
+// if it doesn't before the timeout. The pulled messages are acknowledged, so the
+// topic and the subscription must be dedicated to the check.
+func RoundTripCheck(
+	client *http.Client, project, topic, subscription string, timeout time.Duration, opts ...Option,
+) healthcheck.Check {
+	cfg := newConfig(opts)
+	var (
+		publishURL = cfg.endpoint + "/projects/" + url.PathEscape(project) + "/topics/" + url.PathEscape(topic) + ":publish"
+		subURL     = cfg.endpoint + "/projects/" + url.PathEscape(project) + "/subscriptions/" + url.PathEscape(subscription)
+	)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		id := strconv.FormatInt(time.Now().UnixNano(), 36)
+		publish := publishRequest{Messages: []message{{
+			Data:       base64.StdEncoding.EncodeToString([]byte("healthcheck")),
+			Attributes: map[string]string{healthAttribute: id},
+		}}}
+		if err := call(ctx, client, http.MethodPost, publishURL, publish, nil); err != nil {
+			return fmt.Errorf("publish: %w", err)
+		}
+
+		for {
+			var pulled pullResponse
+			if err := call(ctx, client, http.MethodPost, subURL+":pull", pullRequest{MaxMessages: 10}, &pulled); err != nil {
+				if ctx.Err() != nil {
+					return ErrMessageNotReceived
+				}
+				return fmt.Errorf("pull: %w", err)
+			}
+
+			var (
+				ack      acknowledgeRequest
+				received bool
+			)
+			for _, m := range pulled.ReceivedMessages {
+				ack.AckIDs = append(ack.AckIDs, m.AckID)
+				received = received || m.Message.Attributes[healthAttribute] == id
+			}
+			if len(ack.AckIDs) > 0 {
+				if err := call(ctx, client, http.MethodPost, subURL+":acknowledge", ack, nil); err != nil {
+					return fmt.Errorf("acknowledge: %w", err)
+				}
+			}
+			if received {
+				return nil
+			}
+			if len(pulled.ReceivedMessages) == 0 {
+				select {
+				case <-ctx.Done():
+					return ErrMessageNotReceived
+				case <-time.After(pullInterval):
+				}
+			}
+		}
+	}
+}
+
+type message struct {
+	Data       string            `json:"data,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type publishRequest struct {
+	Messages []message `json:"messages"`
+}
+
+type pullRequest struct {
+	MaxMessages int `json:"maxMessages"`
+}
+
+type pullResponse struct {
+	ReceivedMessages []struct {
+		AckID   string  `json:"ackId"`
+		Message message `json:"message"`
+	} `json:"receivedMessages"`
+}
+
+type acknowledgeRequest struct {
+	AckIDs []string `json:"ackIds"`
+}
+
+// call sends the request to the REST API and decodes its response into out if not nil.
+func call(ctx context.Context, client *http.Client, method, target string, in, out any) error {
+	if client == nil {
+		return errors.New("pubsub client is nil")
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,101 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is an in-memory Pub/Sub with the topic "health" delivering
+// to the subscription "health-sub" if deliver is set.
+type fakeServer struct {
+	deliver bool
+
+	mu      sync.Mutex
+	pending []message
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/projects/p/topics/health", "/v1/projects/p/subscriptions/health-sub":
+		_, _ = w.Write([]byte(`{}`))
+	case "/v1/projects/p/topics/health:publish":
+		var req publishRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if f.deliver {
+			f.pending = append(f.pending, req.Messages...)
+		}
+		_, _ = w.Write([]byte(`{"messageIds": ["1"]}`))
+	case "/v1/projects/p/subscriptions/health-sub:pull":
+		var resp pullResponse
+		for _, m := range f.pending {
+			resp.ReceivedMessages = append(resp.ReceivedMessages, struct {
+				AckID   string  `json:"ackId"`
+				Message message `json:"message"`
+			}{AckID: "ack", Message: m})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	case "/v1/projects/p/subscriptions/health-sub:acknowledge":
+		f.pending = nil
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		http.Error(w, `{"error": {"code": 404, "message": "Resource not found"}}`, http.StatusNotFound)
+	}
+}
+
+func TestResourceChecks(t *testing.T) {
+	server := httptest.NewServer(&fakeServer{})
+	defer server.Close()
+
+	endpoint := WithEndpoint(server.URL + "/v1/")
+	tests := []struct {
+		name  string
+		check func() error
+		fails bool
+	}{
+		{name: "existing topic", check: TopicCheck(server.Client(), "p", "health", time.Second, endpoint)},
+		{name: "missing topic", check: TopicCheck(server.Client(), "p", "orders", time.Second, endpoint), fails: true},
+		{name: "existing subscription", check: SubscriptionCheck(server.Client(), "p", "health-sub", time.Second, endpoint)},
+		{name: "missing subscription", check: SubscriptionCheck(server.Client(), "p", "orders", time.Second, endpoint), fails: true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.check(); (err != nil) != tt.fails {
+			t.Errorf("Wrong result of %s\n"+"expected failure: %v\n"+"actual  : %v", tt.name, tt.fails, err)
+		}
+	}
+}
+
+func TestRoundTripCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		deliver bool
+		expect  error
+	}{
+		{name: "delivered", deliver: true},
+		{name: "not delivered", expect: ErrMessageNotReceived},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(&fakeServer{deliver: tt.deliver})
+			defer server.Close()
+
+			check := RoundTripCheck(server.Client(), "p", "health", "health-sub", 200*time.Millisecond, WithEndpoint(server.URL+"/v1"))
+			if err := check(); !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
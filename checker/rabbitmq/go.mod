@@ -0,0 +1,14 @@
+module github.com/catalystgo/healthcheck/checker/rabbitmq
+
+go 1.22
+
+require (
+	github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+	github.com/catalystgo/healthcheck/checker/roundtrip v0.0.0-00010101000000-000000000000
+	github.com/rabbitmq/amqp091-go v1.10.0
+)
+
+replace (
+	github.com/catalystgo/healthcheck => ../..
+	github.com/catalystgo/healthcheck/checker/roundtrip => ../roundtrip
+)
@@ -0,0 +1,103 @@
+// Package rabbitmq provides a publish/consume round-trip check of RabbitMQ.
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/checker/roundtrip"
+)
+
+// RoundTripCheck returns a Check that publishes a message through the default
+// exchange and consumes it, failing with roundtrip.ErrNotConsumed if it isn't
+// consumed before the timeout. Every execution declares its own exclusive,
+// auto-deleted queue with a server-generated name, so the executions of several
+// instances or overlapping executions never consume each other's messages.
+func RoundTripCheck(conn *amqp.Connection, timeout time.Duration) healthcheck.Check {
+	return roundTripCheck(func() (channel, error) {
+		if conn == nil {
+			return nil, errors.New("rabbitmq connection is nil")
+		}
+		return conn.Channel()
+	}, timeout)
+}
+
+// roundTripCheck returns the round-trip check of the channels opened by open.
+func roundTripCheck(open func() (channel, error), timeout time.Duration) healthcheck.Check {
+	return func() error {
+		return roundtrip.Check(&broker{open: open}, timeout)()
+	}
+}
+
+// channel is the part of *amqp.Channel used by the check.
+type channel interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	ConsumeWithContext(
+		ctx context.Context, queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table,
+	) (<-chan amqp.Delivery, error)
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// broker is the roundtrip.Broker of an execution of the check, publishing
+// on the channel its queue is declared on, since an exclusive queue is only
+// usable by the connection declaring it.
+type broker struct {
+	open func() (channel, error)
+
+	ch    channel
+	queue string
+}
+
+func (b *broker) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	ch, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	// server-generated name, non-durable, auto-deleted and exclusive
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+	deliveries, err := ch.ConsumeWithContext(ctx, queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+	b.ch, b.queue = ch, queue.Name
+
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		defer ch.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- d.Body:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
+
+func (b *broker) Publish(ctx context.Context, payload []byte) error {
+	return b.ch.PublishWithContext(ctx, "", b.queue, false, false, amqp.Publishing{
+		ContentType: "text/plain",
+		Body:        payload,
+	})
+}
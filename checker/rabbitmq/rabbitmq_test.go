@@ -0,0 +1,133 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/catalystgo/healthcheck/checker/roundtrip"
+)
+
+// fakeServer routes the messages published through the default exchange to the
+// queues, delivering the messages of a queue to its consumers in turn.
+type fakeServer struct {
+	mu     sync.Mutex
+	queues map[string][]chan amqp.Delivery
+	next   map[string]int
+	lost   bool
+	count  int
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{queues: make(map[string][]chan amqp.Delivery), next: make(map[string]int)}
+}
+
+func (s *fakeServer) open() (channel, error) {
+	return &fakeChannel{server: s}, nil
+}
+
+type fakeChannel struct {
+	server *fakeServer
+}
+
+func (c *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, _ amqp.Table) (amqp.Queue, error) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == "" {
+		s.count++
+		name = fmt.Sprintf("amq.gen-%d", s.count)
+	}
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) ConsumeWithContext(
+	_ context.Context, queue, _ string, _, _, _, _ bool, _ amqp.Table,
+) (<-chan amqp.Delivery, error) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries := make(chan amqp.Delivery, 16)
+	s.queues[queue] = append(s.queues[queue], deliveries)
+	return deliveries, nil
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, _, key string, _, _ bool, msg amqp.Publishing) error {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	consumers := s.queues[key]
+	if s.lost || len(consumers) == 0 {
+		return nil
+	}
+	consumers[s.next[key]%len(consumers)] <- amqp.Delivery{Body: msg.Body}
+	s.next[key]++
+	return nil
+}
+
+func (c *fakeChannel) Close() error {
+	return nil
+}
+
+func TestRoundTripCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		lost   bool
+		expect error
+	}{
+		{name: "consumed"},
+		{name: "lost", lost: true, expect: roundtrip.ErrNotConsumed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeServer()
+			server.lost = tt.lost
+
+			err := roundTripCheck(server.open, 100*time.Millisecond)()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestRoundTripCheckConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// the executions of several instances on the same broker
+	server := newFakeServer()
+	check := roundTripCheck(server.open, time.Second)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = check()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Received unexpected error of execution %d:\n%+v", i, err)
+		}
+	}
+	if len(server.queues) != len(errs) {
+		t.Errorf("Wrong number of queues\n"+"expected: %v\n"+"actual  : %v", len(errs), len(server.queues))
+	}
+}
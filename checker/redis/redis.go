@@ -0,0 +1,149 @@
+// Package redis provides checks of Redis: standalone, Cluster slot coverage
+// and Sentinel failover state, since a simple PING to a node hides
+// split-cluster and lost-quorum conditions.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// clusterSlots is the number of hash slots of a Redis Cluster.
+const clusterSlots = 16384
+
+var (
+	// ErrClusterDown is the error of a cluster whose state isn't ok.
+	ErrClusterDown = errors.New("redis cluster is down")
+	// ErrSlotsUncovered is the error of a cluster whose slots aren't all served.
+	ErrSlotsUncovered = errors.New("redis cluster slots not covered")
+	// ErrTooFewMasters is the error of a cluster with fewer masters than the minimum.
+	ErrTooFewMasters = errors.New("redis cluster has too few masters")
+	// ErrMasterDown is the error of a master flagged down by the Sentinels.
+	ErrMasterDown = errors.New("redis master is down")
+)
+
+// Pinger is the part of the Redis clients used by PingCheck,
+// implemented by *redis.Client, *redis.ClusterClient and *redis.Ring.
+type Pinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}
+
+// PingCheck returns a Check that pings the server.
+func PingCheck(client Pinger, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if client == nil {
+			return errors.New("redis client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return client.Ping(ctx).Err()
+	}
+}
+
+// ClusterInfoer is the part of the Redis clients used by ClusterCheck,
+// implemented by *redis.ClusterClient and the *redis.Client of a node.
+type ClusterInfoer interface {
+	ClusterInfo(ctx context.Context) *redis.StringCmd
+}
+
+// ClusterCheck returns a Check that reads CLUSTER INFO, failing if the cluster
+// state isn't ok (ErrClusterDown), if any of the 16384 slots isn't served by
+// a healthy node (ErrSlotsUncovered), or if fewer than minMasters masters
+// serve slots (ErrTooFewMasters).
+func ClusterCheck(client ClusterInfoer, minMasters int, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if client == nil {
+			return errors.New("redis client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		raw, err := client.ClusterInfo(ctx).Result()
+		if err != nil {
+			return err
+		}
+		info := parseInfo(raw)
+
+		if state := info["cluster_state"]; state != "ok" {
+			return fmt.Errorf("%w: state %q", ErrClusterDown, state)
+		}
+		if slots, _ := strconv.Atoi(info["cluster_slots_ok"]); slots < clusterSlots {
+			return fmt.Errorf("%w: %d of %d slots ok", ErrSlotsUncovered, slots, clusterSlots)
+		}
+		if masters, _ := strconv.Atoi(info["cluster_size"]); masters < minMasters {
+			return fmt.Errorf("%w: %d (minimum %d)", ErrTooFewMasters, masters, minMasters)
+		}
+		return nil
+	}
+}
+
+// Sentinel is the part of the Sentinel client used by SentinelCheck,
+// implemented by *redis.SentinelClient.
+type Sentinel interface {
+	CkQuorum(ctx context.Context, name string) *redis.StringCmd
+	GetMasterAddrByName(ctx context.Context, name string) *redis.StringSliceCmd
+	Master(ctx context.Context, name string) *redis.MapStringStringCmd
+}
+
+// SentinelCheck returns a Check that verifies with the Sentinel that the
+// Sentinels monitoring the master reach the quorum needed to fail it over
+// (SENTINEL CKQUORUM), that the master has an address, and that it isn't
+// flagged down or disconnected (ErrMasterDown).
+func SentinelCheck(sentinel Sentinel, master string, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if sentinel == nil {
+			return errors.New("redis sentinel client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := sentinel.CkQuorum(ctx, master).Err(); err != nil {
+			return fmt.Errorf("sentinel quorum: %w", err)
+		}
+
+		addr, err := sentinel.GetMasterAddrByName(ctx, master).Result()
+		if err != nil {
+			return fmt.Errorf("master address: %w", err)
+		}
+		if len(addr) != 2 {
+			return fmt.Errorf("master %s has no address", master)
+		}
+
+		state, err := sentinel.Master(ctx, master).Result()
+		if err != nil {
+			return fmt.Errorf("master state: %w", err)
+		}
+		for _, flag := range strings.Split(state["flags"], ",") {
+			switch flag {
+			case "s_down", "o_down", "disconnected":
+				return fmt.Errorf("%w: %s at %s:%s is %s", ErrMasterDown, master, addr[0], addr[1], flag)
+			}
+		}
+		return nil
+	}
+}
+
+// parseInfo parses the "key:value" lines of an INFO-like reply.
+func parseInfo(raw string) map[string]string {
+	info := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":"); ok {
+			info[key] = value
+		}
+	}
+	return info
+}
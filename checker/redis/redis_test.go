@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeCluster string
+
+func (f fakeCluster) ClusterInfo(context.Context) *redis.StringCmd {
+	return redis.NewStringResult(string(f), nil)
+}
+
+func TestClusterCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		info   string
+		expect error
+	}{
+		{
+			name: "healthy",
+			info: "cluster_state:ok\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\ncluster_size:3\r\n",
+		},
+		{
+			name:   "down",
+			info:   "cluster_state:fail\r\ncluster_slots_ok:16384\r\ncluster_size:3\r\n",
+			expect: ErrClusterDown,
+		},
+		{
+			name:   "uncovered slots",
+			info:   "cluster_state:ok\r\ncluster_slots_ok:10923\r\ncluster_size:3\r\n",
+			expect: ErrSlotsUncovered,
+		},
+		{
+			name:   "too few masters",
+			info:   "cluster_state:ok\r\ncluster_slots_ok:16384\r\ncluster_size:2\r\n",
+			expect: ErrTooFewMasters,
+		},
+	}
+
+	for _, tt := range tests {
+		err := ClusterCheck(fakeCluster(tt.info), 3, time.Second)()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+type fakeSentinel struct {
+	quorum error
+	flags  string
+}
+
+func (f fakeSentinel) CkQuorum(context.Context, string) *redis.StringCmd {
+	return redis.NewStringResult("OK 3 usable Sentinels", f.quorum)
+}
+
+func (f fakeSentinel) GetMasterAddrByName(context.Context, string) *redis.StringSliceCmd {
+	return redis.NewStringSliceResult([]string{"10.0.0.1", "6379"}, nil)
+}
+
+func (f fakeSentinel) Master(context.Context, string) *redis.MapStringStringCmd {
+	return redis.NewMapStringStringResult(map[string]string{"name": "mymaster", "flags": f.flags}, nil)
+}
+
+func TestSentinelCheck(t *testing.T) {
+	noQuorum := errors.New("NOQUORUM 1 usable Sentinels")
+
+	tests := []struct {
+		name     string
+		sentinel fakeSentinel
+		expect   error
+	}{
+		{name: "healthy", sentinel: fakeSentinel{flags: "master"}},
+		{name: "no quorum", sentinel: fakeSentinel{quorum: noQuorum, flags: "master"}, expect: noQuorum},
+		{name: "master down", sentinel: fakeSentinel{flags: "master,s_down,o_down"}, expect: ErrMasterDown},
+	}
+
+	for _, tt := range tests {
+		err := SentinelCheck(tt.sentinel, "mymaster", time.Second)()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
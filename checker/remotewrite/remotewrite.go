@@ -0,0 +1,160 @@
+// Package remotewrite provides a check of a Prometheus remote-write endpoint
+// (Prometheus, Mimir, Cortex, Thanos receive, VictoriaMetrics...), so agents
+// depending on remote storage fail readiness when the sink is down:
+//
+//	handler.AddReadinessCheck("remote-write", remotewrite.Check(url, 5*time.Second,
+//		remotewrite.WithBearerToken(token)))
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultMetricName is the name of the sample sent with WithSample without a name.
+const DefaultMetricName = "healthcheck_remote_write_probe"
+
+// Option configures the check.
+type Option func(c *config)
+
+type config struct {
+	client  *http.Client
+	headers http.Header
+	sample  map[string]string
+}
+
+// WithClient sets the HTTP client sending the requests, e.g. for mTLS.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithHeader adds a header to the requests, e.g. X-Scope-OrgID for multi-tenant sinks.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithBasicAuth authenticates the requests with HTTP basic auth.
+func WithBasicAuth(username, password string) Option {
+	return func(c *config) {
+		req := http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(username, password)
+		c.headers.Set("Authorization", req.Header.Get("Authorization"))
+	}
+}
+
+// WithBearerToken authenticates the requests with the bearer token.
+func WithBearerToken(token string) Option {
+	return func(c *config) {
+		c.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithSample makes the check write a sample with value 1 and the given labels
+// (DefaultMetricName if there's no __name__ label) rather than an empty request,
+// so the ingestion path is checked too. The series should be dropped or
+// ignored by the sink if it's billed per series.
+func WithSample(labels map[string]string) Option {
+	return func(c *config) {
+		c.sample = map[string]string{"__name__": DefaultMetricName}
+		for name, value := range labels {
+			c.sample[name] = value
+		}
+	}
+}
+
+// Check returns a Check sending a remote-write request to the URL, empty unless
+// WithSample is used, and failing unless the endpoint responds with 2xx: a down
+// sink, an invalid authentication and a rejected sample all fail the check.
+func Check(url string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := config{
+		client:  http.DefaultClient,
+		headers: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		body := snappy.Encode(nil, writeRequest(cfg.sample, time.Now()))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for key, values := range cfg.headers {
+			req.Header[key] = values
+		}
+		req.Header.Set("Content-Encoding", "snappy")
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+			return fmt.Errorf("remote write responded with %s: %s", resp.Status, bytes.TrimSpace(msg))
+		}
+		return nil
+	}
+}
+
+// writeRequest encodes a prometheus.WriteRequest with a single sample of
+// the series with the given labels, or without any series if labels is nil.
+func writeRequest(labels map[string]string, now time.Time) []byte {
+	if labels == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	// the labels of a series must be sorted by name
+	sort.Strings(names)
+
+	var series []byte
+	for _, name := range names {
+		var label []byte
+		label = protowire.AppendTag(label, 1, protowire.BytesType)
+		label = protowire.AppendString(label, name)
+		label = protowire.AppendTag(label, 2, protowire.BytesType)
+		label = protowire.AppendString(label, labels[name])
+
+		series = protowire.AppendTag(series, 1, protowire.BytesType)
+		series = protowire.AppendBytes(series, label)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(1))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(now.UnixMilli()))
+
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	series = protowire.AppendBytes(series, sample)
+
+	var req []byte
+	req = protowire.AppendTag(req, 1, protowire.BytesType)
+	return protowire.AppendBytes(req, series)
+}
@@ -0,0 +1,73 @@
+package remotewrite
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCheck(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		compressed, _ := io.ReadAll(r.Body)
+		var err error
+		if body, err = snappy.Decode(nil, compressed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := Check(server.URL, time.Second)(); err == nil {
+		t.Errorf("Expected an error without authentication")
+	}
+	if err := Check(server.URL, time.Second, WithBearerToken("secret"))(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	if len(body) != 0 {
+		t.Errorf("Expected an empty write request, got %d bytes", len(body))
+	}
+
+	err := Check(server.URL, time.Second, WithBearerToken("secret"), WithSample(map[string]string{"job": "agent"}))()
+	if err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+
+	// WriteRequest.timeseries
+	num, typ, n := protowire.ConsumeTag(body)
+	if num != 1 || typ != protowire.BytesType || n < 0 {
+		t.Fatalf("Wrong write request: %x", body)
+	}
+	series, n := protowire.ConsumeBytes(body[n:])
+	if n < 0 {
+		t.Fatalf("Wrong write request: %x", body)
+	}
+
+	var labels []string
+	for len(series) > 0 {
+		num, _, n := protowire.ConsumeTag(series)
+		value, m := protowire.ConsumeBytes(series[n:])
+		series = series[n+m:]
+		if num != 1 {
+			continue
+		}
+		name, k := protowire.ConsumeString(value[1:])
+		labelValue, _ := protowire.ConsumeString(value[1+k+1:])
+		labels = append(labels, name+"="+labelValue)
+	}
+
+	expect := []string{"__name__=" + DefaultMetricName, "job=agent"}
+	if len(labels) != len(expect) || labels[0] != expect[0] || labels[1] != expect[1] {
+		t.Errorf("Wrong labels\n"+"expected: %v\n"+"actual  : %v", expect, labels)
+	}
+}
@@ -0,0 +1,262 @@
+// Package revocation provides checks that certificates aren't revoked,
+// through OCSP (including stapled responses) or their CRL, for compliance
+// sensitive deployments.
+package revocation
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+var (
+	// ErrRevoked is the error of a revoked certificate.
+	ErrRevoked = errors.New("certificate is revoked")
+	// ErrNoRevocationInfo is the error of a certificate with
+	// neither an OCSP responder nor a CRL distribution point.
+	ErrNoRevocationInfo = errors.New("certificate has no OCSP responder nor CRL distribution point")
+)
+
+// maxResponseSize is the maximum size of the OCSP responses and CRLs read.
+const maxResponseSize = 10 << 20
+
+// Option configures the checks.
+type Option func(c *config)
+
+type config struct {
+	client    *http.Client
+	tlsConfig *tls.Config
+}
+
+// WithClient sets the HTTP client querying the OCSP responders
+// and downloading the CRLs, http.DefaultClient by default.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the connections of RemoteCheck,
+// e.g. with the root CAs of a private PKI.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *config) {
+		c.tlsConfig = cfg
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// FileCheck returns a Check that verifies the certificate of the PEM file is
+// not revoked, through OCSP or else its CRL, failing with ErrRevoked if it is.
+// The issuer is the second certificate of the file, or the first certificate
+// of issuerFile if not empty.
+func FileCheck(certFile, issuerFile string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := newConfig(opts)
+
+	return func() error {
+		certs, err := readCertificates(certFile)
+		if err != nil {
+			return err
+		}
+		if issuerFile != "" {
+			issuers, err := readCertificates(issuerFile)
+			if err != nil {
+				return err
+			}
+			certs = append(certs[:1], issuers[0])
+		}
+		if len(certs) < 2 {
+			return fmt.Errorf("%s: issuer certificate not found", certFile)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return cfg.checkRevocation(ctx, certs[0], certs[1], nil)
+	}
+}
+
+// RemoteCheck returns a Check that verifies the leaf certificate presented by
+// the TLS endpoint at the address (host:port) is not revoked, using the OCSP
+// response stapled in the handshake if any, OCSP or else its CRL otherwise.
+// The check fails with ErrRevoked if it is.
+func RemoteCheck(addr string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := newConfig(opts)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		dialer := tls.Dialer{NetDialer: &net.Dialer{}, Config: cfg.tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		state := conn.(*tls.Conn).ConnectionState()
+		_ = conn.Close()
+
+		leaf, issuer := state.PeerCertificates[0], issuerOf(state)
+		if issuer == nil {
+			return fmt.Errorf("%s: issuer certificate not found", addr)
+		}
+		return cfg.checkRevocation(ctx, leaf, issuer, state.OCSPResponse)
+	}
+}
+
+// issuerOf returns the issuer of the leaf from the verified chain, or
+// from the presented certificates if verification is skipped.
+func issuerOf(state tls.ConnectionState) *x509.Certificate {
+	if len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 1 {
+		return state.VerifiedChains[0][1]
+	}
+	if len(state.PeerCertificates) > 1 {
+		return state.PeerCertificates[1]
+	}
+	return nil
+}
+
+// checkRevocation checks the revocation of the certificate with the stapled
+// OCSP response if any, its OCSP responder or else its CRL.
+func (c config) checkRevocation(ctx context.Context, cert, issuer *x509.Certificate, staple []byte) error {
+	switch {
+	case len(staple) > 0:
+		resp, err := ocsp.ParseResponseForCert(staple, cert, issuer)
+		if err != nil {
+			return fmt.Errorf("stapled ocsp response: %w", err)
+		}
+		return ocspStatus(resp)
+	case len(cert.OCSPServer) > 0:
+		return c.checkOCSP(ctx, cert, issuer)
+	case len(cert.CRLDistributionPoints) > 0:
+		return c.checkCRL(ctx, cert, issuer)
+	default:
+		return ErrNoRevocationInfo
+	}
+}
+
+func (c config) checkOCSP(ctx context.Context, cert, issuer *x509.Certificate) error {
+	body, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cert.OCSPServer[0], bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	raw, err := c.get(req)
+	if err != nil {
+		return fmt.Errorf("ocsp: %w", err)
+	}
+	resp, err := ocsp.ParseResponseForCert(raw, cert, issuer)
+	if err != nil {
+		return fmt.Errorf("ocsp: %w", err)
+	}
+	return ocspStatus(resp)
+}
+
+// ocspStatus returns the error of the status of the OCSP response.
+func ocspStatus(resp *ocsp.Response) error {
+	if !resp.NextUpdate.IsZero() && time.Now().After(resp.NextUpdate) {
+		return fmt.Errorf("ocsp response is stale since %v", resp.NextUpdate)
+	}
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("%w at %v", ErrRevoked, resp.RevokedAt)
+	default:
+		return errors.New("ocsp responder doesn't know the certificate")
+	}
+}
+
+func (c config) checkCRL(ctx context.Context, cert, issuer *x509.Certificate) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cert.CRLDistributionPoints[0], nil)
+	if err != nil {
+		return err
+	}
+	raw, err := c.get(req)
+	if err != nil {
+		return fmt.Errorf("crl: %w", err)
+	}
+	crl, err := x509.ParseRevocationList(raw)
+	if err != nil {
+		return fmt.Errorf("crl: %w", err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return fmt.Errorf("crl: %w", err)
+	}
+	if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+		return fmt.Errorf("crl is stale since %v", crl.NextUpdate)
+	}
+
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return fmt.Errorf("%w at %v", ErrRevoked, entry.RevocationTime)
+		}
+	}
+	return nil
+}
+
+// get sends the request and returns the body of its successful response.
+func (c config) get(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+}
+
+// readCertificates reads the certificates of the PEM file.
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s: no certificate found", path)
+	}
+	return certs, nil
+}
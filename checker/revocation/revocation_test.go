@@ -0,0 +1,197 @@
+package revocation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// pki is a test CA with an OCSP responder and a CRL
+// revoking the certificates with an odd serial number.
+type pki struct {
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+	server *httptest.Server
+}
+
+func newPKI(t *testing.T) *pki {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+
+	p := &pki{ca: ca, caKey: key}
+	p.server = httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *pki) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/ocsp":
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(p.ocspResponse(req.SerialNumber))
+	case "/crl":
+		crl, _ := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: time.Now().Add(-time.Minute),
+			NextUpdate: time.Now().Add(time.Hour),
+			RevokedCertificateEntries: []x509.RevocationListEntry{
+				{SerialNumber: big.NewInt(3), RevocationTime: time.Now().Add(-time.Minute)},
+			},
+		}, p.ca, p.caKey)
+		_, _ = w.Write(crl)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *pki) ocspResponse(serial *big.Int) []byte {
+	tmpl := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: serial,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}
+	if serial.Bit(0) == 1 {
+		tmpl.Status, tmpl.RevokedAt = ocsp.Revoked, time.Now().Add(-time.Minute)
+	}
+	resp, _ := ocsp.CreateResponse(p.ca, p.ca, tmpl, p.caKey)
+	return resp
+}
+
+// issue issues a certificate for 127.0.0.1 with the serial number,
+// checked through OCSP or else the CRL.
+func (p *pki) issue(t *testing.T, serial int64, useOCSP bool) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if useOCSP {
+		tmpl.OCSPServer = []string{p.server.URL + "/ocsp"}
+	} else {
+		tmpl.CRLDistributionPoints = []string{p.server.URL + "/crl"}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, key.Public(), p.caKey)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der, p.ca.Raw}, PrivateKey: key}
+}
+
+func TestFileCheck(t *testing.T) {
+	p := newPKI(t)
+
+	tests := []struct {
+		name    string
+		serial  int64
+		useOCSP bool
+		expect  error
+	}{
+		{name: "good ocsp", serial: 2, useOCSP: true},
+		{name: "revoked ocsp", serial: 3, useOCSP: true, expect: ErrRevoked},
+		{name: "good crl", serial: 2},
+		{name: "revoked crl", serial: 3, expect: ErrRevoked},
+	}
+
+	for _, tt := range tests {
+		cert := p.issue(t, tt.serial, tt.useOCSP)
+		path := filepath.Join(t.TempDir(), "cert.pem")
+		var data []byte
+		for _, der := range cert.Certificate {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+
+		err := FileCheck(path, "", time.Second)()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestRemoteCheck(t *testing.T) {
+	p := newPKI(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca)
+
+	tests := []struct {
+		name   string
+		serial int64
+		staple bool
+		expect error
+	}{
+		{name: "good", serial: 2},
+		{name: "revoked", serial: 3, expect: ErrRevoked},
+		{name: "revoked stapled", serial: 5, staple: true, expect: ErrRevoked},
+	}
+
+	for _, tt := range tests {
+		cert := p.issue(t, tt.serial, true)
+		if tt.staple {
+			cert.OCSPStaple = p.ocspResponse(big.NewInt(tt.serial))
+			// Make sure the responder isn't queried.
+			p.server.Config.Handler = http.NotFoundHandler()
+		}
+
+		server := httptest.NewUnstartedServer(http.NotFoundHandler())
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+		server.StartTLS()
+
+		err := RemoteCheck(server.Listener.Addr().String(), time.Second, WithTLSConfig(&tls.Config{RootCAs: roots}))()
+		if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package roundtrip
+
+import (
+	"context"
+	"errors"
+)
+
+// PublishFunc publishes a message with the payload to the health topic.
+type PublishFunc func(ctx context.Context, payload []byte) error
+
+// ConsumeFunc consumes the next message of the health topic and returns its payload.
+type ConsumeFunc func(ctx context.Context) ([]byte, error)
+
+// Funcs returns the Broker publishing and consuming the messages with the
+// functions, adapting any client, e.g. the segmentio/kafka-go one:
+//
+//	roundtrip.Check(roundtrip.Funcs(
+//		func(ctx context.Context, payload []byte) error {
+//			return writer.WriteMessages(ctx, kafkago.Message{Value: payload})
+//		},
+//		func(ctx context.Context) ([]byte, error) {
+//			msg, err := reader.ReadMessage(ctx)
+//			return msg.Value, err
+//		},
+//	), 5*time.Second)
+//
+// The consumer must not skip the published message, e.g. by joining its
+// group with the latest offset after the message is published.
+func Funcs(publish PublishFunc, consume ConsumeFunc) Broker {
+	return funcBroker{publish: publish, consume: consume}
+}
+
+// funcBroker is the Broker of the functions.
+type funcBroker struct {
+	publish PublishFunc
+	consume ConsumeFunc
+}
+
+func (b funcBroker) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	if b.publish == nil || b.consume == nil {
+		return nil, errors.New("publish or consume function is nil")
+	}
+
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+
+		for {
+			payload, err := b.consume(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case messages <- payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return messages, nil
+}
+
+func (b funcBroker) Publish(ctx context.Context, payload []byte) error {
+	return b.publish(ctx, payload)
+}
@@ -0,0 +1,7 @@
+module github.com/catalystgo/healthcheck/checker/roundtrip
+
+go 1.22
+
+require github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+
+replace github.com/catalystgo/healthcheck => ../..
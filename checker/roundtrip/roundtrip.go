@@ -0,0 +1,81 @@
+// Package roundtrip provides a publish/consume round-trip check of a message
+// broker: the only check validating that the broker actually delivers messages
+// end to end, where dialing it or reading its metadata only validates it's up.
+//
+// The checker/rabbitmq and checker/nats packages provide the Broker of their
+// brokers, Funcs adapts any other client, e.g. a Kafka one.
+package roundtrip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// payloadPrefix prefixes the nonce in the payload of the health messages.
+const payloadPrefix = "healthcheck:"
+
+// ErrNotConsumed is the error of a message not consumed before the timeout.
+var ErrNotConsumed = errors.New("health message not consumed")
+
+// Broker publishes and consumes the messages of a health topic or queue
+// dedicated to the check.
+type Broker interface {
+	// Subscribe starts consuming the health topic or queue, sending the
+	// payloads of the consumed messages to the returned channel until ctx is done.
+	Subscribe(ctx context.Context) (<-chan []byte, error)
+	// Publish publishes a message with the payload to the health topic or queue.
+	Publish(ctx context.Context, payload []byte) error
+}
+
+// Check returns a Check that subscribes to the health topic or queue,
+// publishes a message with a random nonce and waits for it to be consumed,
+// failing with ErrNotConsumed if it isn't before the timeout. Messages
+// of previous executions are skipped.
+func Check(broker Broker, timeout time.Duration) healthcheck.Check {
+	return func() error {
+		if broker == nil {
+			return errors.New("broker is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		nonce := make([]byte, 8)
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		payload := []byte(payloadPrefix + hex.EncodeToString(nonce))
+
+		messages, err := broker.Subscribe(ctx)
+		if err != nil {
+			return fmt.Errorf("subscribe: %w", err)
+		}
+		if err := broker.Publish(ctx, payload); err != nil {
+			return fmt.Errorf("publish: %w", err)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ErrNotConsumed
+			case msg, ok := <-messages:
+				if !ok {
+					if ctx.Err() != nil {
+						return ErrNotConsumed
+					}
+					return errors.New("subscription closed")
+				}
+				if bytes.Equal(msg, payload) {
+					return nil
+				}
+			}
+		}
+	}
+}
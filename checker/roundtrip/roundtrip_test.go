@@ -0,0 +1,96 @@
+package roundtrip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeBroker delivers the published messages after the stale ones, unless lost is set.
+type fakeBroker struct {
+	stale [][]byte
+	lost  bool
+
+	messages chan []byte
+}
+
+func (b *fakeBroker) Subscribe(context.Context) (<-chan []byte, error) {
+	b.messages = make(chan []byte, len(b.stale)+1)
+	for _, msg := range b.stale {
+		b.messages <- msg
+	}
+	return b.messages, nil
+}
+
+func (b *fakeBroker) Publish(_ context.Context, payload []byte) error {
+	if !b.lost {
+		b.messages <- payload
+	}
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		broker *fakeBroker
+		expect error
+	}{
+		{name: "delivered", broker: &fakeBroker{}},
+		{name: "delivered after stale messages", broker: &fakeBroker{stale: [][]byte{[]byte("healthcheck:0011223344556677")}}},
+		{name: "lost", broker: &fakeBroker{lost: true}, expect: ErrNotConsumed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(tt.broker, 100*time.Millisecond)()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		name   string
+		lost   bool
+		expect error
+	}{
+		{name: "consumed"},
+		{name: "lost", lost: true, expect: ErrNotConsumed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			topic := make(chan []byte, 1)
+			publish := func(_ context.Context, payload []byte) error {
+				if !tt.lost {
+					topic <- payload
+				}
+				return nil
+			}
+			consume := func(ctx context.Context) ([]byte, error) {
+				select {
+				case payload := <-topic:
+					return payload, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+
+			err := Check(Funcs(publish, consume), 100*time.Millisecond)()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/catalystgo/healthcheck/checker/sqs
+
+go 1.22
+
+require (
+	github.com/aws/aws-sdk-go-v2/service/sqs v1.37.3
+	github.com/catalystgo/healthcheck v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.32.7 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.3.26 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.6.26 // indirect
+	github.com/aws/smithy-go v1.22.1 // indirect
+)
+
+replace github.com/catalystgo/healthcheck => ../..
@@ -0,0 +1,82 @@
+// Package sqs provides a check of an AWS SQS queue: its existence and,
+// optionally, its backlog, catching stuck or too slow consumers.
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrBacklog is the error of a queue with more messages than the threshold set by WithMaxMessages.
+var ErrBacklog = errors.New("sqs queue backlog exceeds the threshold")
+
+// API is the part of the SQS client used by the check, implemented by *sqs.Client.
+type API interface {
+	GetQueueAttributes(
+		ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options),
+	) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// Option configures the check.
+type Option func(c *config)
+
+type config struct {
+	maxMessages int
+}
+
+// WithMaxMessages makes the check fail when the approximate number of visible
+// messages of the queue exceeds max. Register the check as NonCritical or
+// ReportOnly to degrade rather than fail the readiness on a backlog.
+func WithMaxMessages(threshold int) Option {
+	return func(c *config) {
+		c.maxMessages = threshold
+	}
+}
+
+// QueueCheck returns a Check that gets the attributes of the queue,
+// failing if it doesn't exist or isn't accessible with the credentials
+// of the client, or if its backlog exceeds the threshold set by WithMaxMessages.
+func QueueCheck(client API, queueURL string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func() error {
+		if client == nil {
+			return errors.New("sqs client is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		out, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       &queueURL,
+			AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+		})
+		if err != nil {
+			return err
+		}
+		if cfg.maxMessages <= 0 {
+			return nil
+		}
+
+		raw := out.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)]
+		messages, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid ApproximateNumberOfMessages %q: %w", raw, err)
+		}
+		if messages > cfg.maxMessages {
+			return fmt.Errorf("%w: %d messages (threshold %d)", ErrBacklog, messages, cfg.maxMessages)
+		}
+		return nil
+	}
+}
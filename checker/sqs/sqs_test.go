@@ -0,0 +1,55 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+type fakeAPI struct {
+	messages string
+	err      error
+}
+
+func (f fakeAPI) GetQueueAttributes(
+	context.Context, *sqs.GetQueueAttributesInput, ...func(*sqs.Options),
+) (*sqs.GetQueueAttributesOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &sqs.GetQueueAttributesOutput{
+		Attributes: map[string]string{"ApproximateNumberOfMessages": f.messages},
+	}, nil
+}
+
+func TestQueueCheck(t *testing.T) {
+	missing := errors.New("AWS.SimpleQueueService.NonExistentQueue")
+
+	tests := []struct {
+		name   string
+		api    fakeAPI
+		opts   []Option
+		expect error
+	}{
+		{name: "existing queue", api: fakeAPI{messages: "100000"}},
+		{name: "missing queue", api: fakeAPI{err: missing}, expect: missing},
+		{name: "backlog under the threshold", api: fakeAPI{messages: "10"}, opts: []Option{WithMaxMessages(100)}},
+		{name: "backlog over the threshold", api: fakeAPI{messages: "1000"}, opts: []Option{WithMaxMessages(100)}, expect: ErrBacklog},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := QueueCheck(tt.api, "https://sqs.eu-west-1.amazonaws.com/123456789012/orders", time.Second, tt.opts...)()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package ssh provides a check of the SSH endpoints (bastions, SFTP servers...):
+// it completes the key exchange and validates the host key of the server,
+// optionally authenticating with a probe credential:
+//
+//	hostKeys, err := knownhosts.New("/etc/ssh/ssh_known_hosts")
+//	...
+//	handler.AddReadinessCheck("sftp", ssh.HandshakeCheck("sftp.internal:22", 3*time.Second, ssh.Options{
+//		HostKeyCallback: hostKeys,
+//	}))
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultUser is the user of the handshakes without Options.User.
+const DefaultUser = "healthcheck"
+
+var (
+	// ErrHostKey is the error of a server whose host key isn't valid.
+	ErrHostKey = errors.New("ssh host key rejected")
+	// ErrAuthFailed is the error of a server rejecting the probe credential.
+	ErrAuthFailed = errors.New("ssh authentication failed")
+)
+
+// Options configures the handshakes of HandshakeCheck.
+type Options struct {
+	// HostKeyCallback validates the host key of the server (e.g. knownhosts.New
+	// or ssh.FixedHostKey), required: a handshake with an unvalidated server
+	// doesn't tell it's the expected one.
+	HostKeyCallback ssh.HostKeyCallback
+	// HostKeyAlgorithms are the accepted host key algorithms, in order of preference.
+	HostKeyAlgorithms []string
+	// User is the user of the handshake, DefaultUser if empty.
+	User string
+	// Auth are the methods to authenticate with the probe credential. Without
+	// any, the check passes once the server asks for authentication.
+	Auth []ssh.AuthMethod
+	// Dialer is the dialer of the connections. Its timeout is bounded by the timeout of the check.
+	Dialer *net.Dialer
+}
+
+// HandshakeCheck returns a Check connecting to the SSH server at addr and completing
+// the handshake within timeout, failing with ErrHostKey if the host key isn't valid
+// and with ErrAuthFailed if the probe credential set in the options is rejected.
+func HandshakeCheck(addr string, timeout time.Duration, opts Options) healthcheck.Check {
+	return func() error {
+		if opts.HostKeyCallback == nil {
+			return errors.New("ssh host key callback is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var dialer net.Dialer
+		if opts.Dialer != nil {
+			dialer = *opts.Dialer
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		deadline, _ := ctx.Deadline()
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+
+		// the host key is validated before the authentication,
+		// which tells an authentication failure from a handshake one
+		var hostKeyErr error
+		hostKeyValidated := false
+		config := &ssh.ClientConfig{
+			User: opts.User,
+			Auth: opts.Auth,
+			HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+				hostKeyErr = opts.HostKeyCallback(hostname, remote, key)
+				hostKeyValidated = hostKeyErr == nil
+				return hostKeyErr
+			},
+			HostKeyAlgorithms: opts.HostKeyAlgorithms,
+		}
+		if config.User == "" {
+			config.User = DefaultUser
+		}
+
+		sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+		switch {
+		case err == nil:
+			go ssh.DiscardRequests(reqs)
+			go func() {
+				for ch := range chans {
+					_ = ch.Reject(ssh.Prohibited, "health check")
+				}
+			}()
+			return sshConn.Close()
+		case hostKeyErr != nil:
+			return fmt.Errorf("%w: %w", ErrHostKey, hostKeyErr)
+		case errors.Is(err, os.ErrDeadlineExceeded):
+			return err
+		case hostKeyValidated && len(opts.Auth) == 0:
+			// the server asked for authentication, the handshake is complete
+			return nil
+		case hostKeyValidated:
+			return fmt.Errorf("%w: %w", ErrAuthFailed, err)
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startServer starts an SSH server accepting the password "secret"
+// and returns its address and host key.
+func startServer(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "secret" {
+				return nil, errors.New("wrong password")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				sshConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				go func() {
+					for ch := range chans {
+						_ = ch.Reject(ssh.Prohibited, "test")
+					}
+				}()
+				_ = sshConn.Wait()
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), signer.PublicKey()
+}
+
+func TestHandshakeCheck(t *testing.T) {
+	addr, hostKey := startServer(t)
+
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, _ := ssh.NewPublicKey(otherPub)
+
+	// a server accepting connections without ever completing the handshake
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	defer silent.Close()
+
+	tests := []struct {
+		name   string
+		addr   string
+		opts   Options
+		fails  bool
+		expect error
+	}{
+		{
+			name: "handshake without credential",
+			addr: addr,
+			opts: Options{HostKeyCallback: ssh.FixedHostKey(hostKey)},
+		},
+		{
+			name: "probe credential",
+			addr: addr,
+			opts: Options{HostKeyCallback: ssh.FixedHostKey(hostKey), Auth: []ssh.AuthMethod{ssh.Password("secret")}},
+		},
+		{
+			name:   "wrong credential",
+			addr:   addr,
+			opts:   Options{HostKeyCallback: ssh.FixedHostKey(hostKey), Auth: []ssh.AuthMethod{ssh.Password("guess")}},
+			fails:  true,
+			expect: ErrAuthFailed,
+		},
+		{
+			name:   "unknown host key",
+			addr:   addr,
+			opts:   Options{HostKeyCallback: ssh.FixedHostKey(otherKey)},
+			fails:  true,
+			expect: ErrHostKey,
+		},
+		{
+			name:  "no host key callback",
+			addr:  addr,
+			fails: true,
+		},
+		{
+			name:  "timeout",
+			addr:  silent.Addr().String(),
+			opts:  Options{HostKeyCallback: ssh.FixedHostKey(hostKey)},
+			fails: true,
+		},
+	}
+
+	for _, tt := range tests {
+		err := HandshakeCheck(tt.addr, 500*time.Millisecond, tt.opts)()
+		if (err != nil) != tt.fails || tt.expect != nil && !errors.Is(err, tt.expect) {
+			t.Errorf("Wrong error of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, err)
+		}
+	}
+}
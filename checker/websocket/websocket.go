@@ -0,0 +1,133 @@
+// Package websocket provides a check of a WebSocket endpoint,
+// for services depending on realtime gateways.
+package websocket
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrNoPong is the error of a ping frame not answered before the timeout.
+var ErrNoPong = errors.New("websocket ping not answered")
+
+// Option configures the check.
+type Option func(c *config)
+
+type config struct {
+	headers      http.Header
+	subprotocols []string
+	tlsConfig    *tls.Config
+	ping         bool
+}
+
+// WithHeader adds a header to the handshake request, e.g. for authentication.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithSubprotocols sets the subprotocols requested in the handshake.
+func WithSubprotocols(protocols ...string) Option {
+	return func(c *config) {
+		c.subprotocols = protocols
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of wss:// connections, e.g. for mTLS.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *config) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithPing makes the check send a ping frame once connected and
+// fail with ErrNoPong if the pong frame isn't received before the timeout.
+func WithPing() Option {
+	return func(c *config) {
+		c.ping = true
+	}
+}
+
+// Check returns a Check that completes the WebSocket handshake with the
+// ws:// or wss:// URL, then closes the connection, all within the timeout.
+func Check(url string, timeout time.Duration, opts ...Option) healthcheck.Check {
+	cfg := config{headers: make(http.Header)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: timeout,
+		Subprotocols:     cfg.subprotocols,
+		TLSClientConfig:  cfg.tlsConfig,
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		conn, resp, err := dialer.DialContext(ctx, url, cfg.headers)
+		if err != nil {
+			if resp != nil {
+				return fmt.Errorf("handshake: %w: %s", err, resp.Status)
+			}
+			return fmt.Errorf("handshake: %w", err)
+		}
+		defer conn.Close()
+
+		deadline, _ := ctx.Deadline()
+		if cfg.ping {
+			if err := ping(conn, deadline); err != nil {
+				return err
+			}
+		}
+
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		return conn.WriteControl(websocket.CloseMessage, msg, deadline)
+	}
+}
+
+// ping sends a ping frame and reads the connection until the pong frame
+// is received, discarding the data messages received meanwhile.
+func ping(conn *websocket.Conn, deadline time.Time) error {
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	var ponged bool
+	conn.SetPongHandler(func(data string) error {
+		if data == nonce {
+			// Control frames are handled while waiting for the next data
+			// message: expire the read to stop waiting.
+			ponged = true
+			return conn.SetReadDeadline(time.Now())
+		}
+		return nil
+	})
+
+	if err := conn.WriteControl(websocket.PingMessage, []byte(nonce), deadline); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			if ponged {
+				return nil
+			}
+			var netErr interface{ Timeout() bool }
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return ErrNoPong
+			}
+			return fmt.Errorf("ping: %w", err)
+		}
+	}
+}
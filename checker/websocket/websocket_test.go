@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCheck(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if r.URL.Path == "/mute" {
+			// Don't read the connection, so that pings aren't answered.
+			time.Sleep(time.Second)
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("welcome"))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	auth := WithHeader("Authorization", "Bearer token")
+
+	tests := []struct {
+		name   string
+		path   string
+		opts   []Option
+		fails  bool
+		expect error
+	}{
+		{name: "handshake", opts: []Option{auth}},
+		{name: "ping", opts: []Option{auth, WithPing()}},
+		{name: "unauthorized", fails: true},
+		{name: "ping not answered", path: "/mute", opts: []Option{auth, WithPing()}, fails: true, expect: ErrNoPong},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(url+tt.path, 200*time.Millisecond, tt.opts...)()
+			if (err != nil) != tt.fails || (tt.expect != nil && !errors.Is(err, tt.expect)) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
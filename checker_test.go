@@ -0,0 +1,75 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testChecker struct {
+	name    string
+	details map[string]any
+}
+
+func (c testChecker) Name() string {
+	return c.name
+}
+
+func (c testChecker) Check(context.Context) Result {
+	return Result{Details: c.details}
+}
+
+func TestHandlerChecker(t *testing.T) {
+	h := NewHandler()
+	h.AddLivenessCheck("plain", func() error { return nil })
+	h.AddReadinessChecker(testChecker{
+		name:    "disk",
+		details: map[string]any{"free_bytes": 1024, "version": "1.2"},
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/ready?full=1", nil)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	expectBody := "{\n" +
+		"    \"disk\": {\n" +
+		"        \"status\": \"OK\",\n" +
+		"        \"kind\": \"ok\",\n" +
+		"        \"details\": {\n" +
+		"            \"free_bytes\": 1024,\n" +
+		"            \"version\": \"1.2\"\n" +
+		"        }\n" +
+		"    },\n" +
+		"    \"plain\": \"OK\"\n" +
+		"}\n"
+	if rr.Body.String() != expectBody {
+		t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", expectBody, rr.Body.String())
+	}
+}
+
+func TestResultMetrics(t *testing.T) {
+	result := Result{Details: map[string]any{
+		"latency":   250 * time.Millisecond,
+		"free":      int64(42),
+		"ratio":     0.5,
+		"connected": true,
+		"version":   "1.2",
+	}}
+
+	expect := map[string]float64{"latency": 0.25, "free": 42, "ratio": 0.5, "connected": 1}
+	metrics := result.Metrics()
+	if len(metrics) != len(expect) {
+		t.Errorf("Wrong metrics\n"+"expected: %v\n"+"actual  : %v", expect, metrics)
+	}
+	for key, value := range expect {
+		if metrics[key] != value {
+			t.Errorf("Wrong metric %q\n"+"expected: %v\n"+"actual  : %v", key, value, metrics[key])
+		}
+	}
+}
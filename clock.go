@@ -0,0 +1,149 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the handler: the background checks scheduling,
+// durations and timestamps. It can be replaced with WithClock, e.g. with a
+// ManualClock to test schedules and TTLs without sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer sending the current time on its channel after d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, see time.Timer.Stop.
+	Stop() bool
+}
+
+// SystemClock is the Clock backed by the time package, used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// WithClock sets the Clock of the handler, SystemClock by default.
+func WithClock(clock Clock) Option {
+	return func(h *basicHandler) {
+		h.clock = clock
+	}
+}
+
+// ManualClock is a Clock which only moves forward when told to, for tests.
+type ManualClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualClock creates a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	c := &ManualClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a Timer firing once the clock is advanced by d.
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &manualTimer{
+		clock: c,
+		when:  c.now.Add(d),
+		c:     make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, firing the timers due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.when.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil blocks until n timers are waiting to fire, which allows
+// to advance the clock only once a background check has been rescheduled.
+func (c *ManualClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+type manualTimer struct {
+	clock *ManualClock
+	when  time.Time
+	c     chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
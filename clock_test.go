@@ -0,0 +1,35 @@
+package healthcheck
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManualClockSchedule(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock))
+
+	var runs atomic.Int32
+	h.AddReadinessCheck("scheduled", func() error {
+		runs.Add(1)
+		return nil
+	}, WithSchedule(Every(time.Minute)))
+
+	// the check is executed once on start, then rescheduled
+	clock.BlockUntil(1)
+	if n := runs.Load(); n != 1 {
+		t.Errorf("Wrong number of runs\n"+"expected: %v\n"+"actual  : %v", 1, n)
+	}
+
+	clock.Advance(30 * time.Second)
+	if n := runs.Load(); n != 1 {
+		t.Errorf("Wrong number of runs\n"+"expected: %v\n"+"actual  : %v", 1, n)
+	}
+
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+	if n := runs.Load(); n != 2 {
+		t.Errorf("Wrong number of runs\n"+"expected: %v\n"+"actual  : %v", 2, n)
+	}
+}
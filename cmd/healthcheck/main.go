@@ -0,0 +1,66 @@
+// Command healthcheck probes the endpoints served by a healthcheck.Handler,
+// e.g. as a Docker HEALTHCHECK or a Kubernetes exec probe in distroless images
+// without curl:
+//
+//	HEALTHCHECK CMD ["/healthcheck", "probe", "http://localhost:8080/ready"]
+//
+// Usage:
+//
+//	healthcheck probe [-unix socket] [-timeout 5s] [-q] [url]
+//	healthcheck run [-config healthcheck.json] [-format table|json] [-profile prod]
+//
+// The probe prints a summary of the checks and exits with 0 if the probe
+// passes, 1 if it fails and 2 if the endpoint can't be reached.
+//
+// The run command executes the checks declared in a configuration file
+// (see the config package) directly, without any server, e.g. for pre-deploy
+// smoke tests. It exits with 1 if any check fails.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes of the commands.
+const (
+	exitPass  = 0
+	exitFail  = 1
+	exitError = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+
+	switch args[0] {
+	case "probe":
+		return probe(args[1:], stdout, stderr)
+	case "run":
+		return runChecks(args[1:], stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+		return exitPass
+	default:
+		fmt.Fprintf(stderr, "healthcheck: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitError
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: healthcheck <command> [flags]
+
+Commands:
+  probe   probe a health endpoint and exit non-zero if it fails
+  run     execute the checks of a configuration file
+`)
+}
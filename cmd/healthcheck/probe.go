@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultURL is the endpoint probed without an URL argument.
+const defaultURL = "http://localhost:8080/ready"
+
+// probe requests the endpoint with ?full=1, prints the summary
+// of the checks and returns the exit code.
+func probe(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("probe", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		socket  = flags.String("unix", "", "path of the unix socket to connect to, the URL host is ignored")
+		timeout = flags.Duration("timeout", 5*time.Second, "timeout of the probe")
+		quiet   = flags.Bool("q", false, "don't print the summary")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "healthcheck: probe takes a single URL")
+		return exitError
+	}
+
+	target := defaultURL
+	if flags.NArg() == 1 {
+		target = flags.Arg(0)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		fmt.Fprintf(stderr, "healthcheck: %v\n", err)
+		return exitError
+	}
+	query := u.Query()
+	query.Set("full", "1")
+	u.RawQuery = query.Encode()
+
+	client := &http.Client{Timeout: *timeout}
+	if *socket != "" {
+		path := *socket
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		fmt.Fprintf(stderr, "healthcheck: %v\n", err)
+		return exitError
+	}
+	defer resp.Body.Close()
+
+	passed := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !*quiet {
+		body, _ := io.ReadAll(resp.Body)
+		printSummary(stdout, target, resp.Status, passed, parseResults(body))
+	}
+
+	if !passed {
+		return exitFail
+	}
+	return exitPass
+}
+
+// parseResults parses the full output of a probe into the status of each check,
+// nil if the body isn't the full output of a healthcheck.Handler.
+func parseResults(body []byte) map[string]string {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	results := make(map[string]string, len(raw))
+	for name, value := range raw {
+		var status string
+		if err := json.Unmarshal(value, &status); err == nil {
+			results[name] = status
+			continue
+		}
+
+		// checks with details or a code are objects
+		var detailed struct {
+			Status string `json:"status"`
+			Code   string `json:"code"`
+		}
+		if err := json.Unmarshal(value, &detailed); err != nil {
+			results[name] = string(value)
+			continue
+		}
+		results[name] = detailed.Status
+		if detailed.Code != "" {
+			results[name] = detailed.Code + ": " + detailed.Status
+		}
+	}
+	return results
+}
+
+// printSummary prints the outcome of the probe followed by the checks sorted by name.
+func printSummary(w io.Writer, target, status string, passed bool, results map[string]string) {
+	outcome := "PASS"
+	if !passed {
+		outcome = "FAIL"
+	}
+	fmt.Fprintf(w, "%s %s (%s)\n", outcome, target, status)
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, results[name])
+	}
+	_ = tw.Flush()
+}
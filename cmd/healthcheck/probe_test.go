@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestProbe(t *testing.T) {
+	h := healthcheck.NewHandler()
+	h.AddLivenessCheck("goroutines", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("connection refused") })
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect int
+		output []string
+	}{
+		{
+			name:   "passing probe",
+			args:   []string{"probe", server.URL + "/live"},
+			expect: exitPass,
+			output: []string{"PASS", "goroutines  OK"},
+		},
+		{
+			name:   "failing probe",
+			args:   []string{"probe", server.URL + "/ready"},
+			expect: exitFail,
+			output: []string{"FAIL", "database    connection refused", "goroutines  OK"},
+		},
+		{
+			name:   "quiet",
+			args:   []string{"probe", "-q", server.URL + "/ready"},
+			expect: exitFail,
+		},
+		{
+			name:   "unreachable endpoint",
+			args:   []string{"probe", "-timeout", "1s", "http://127.0.0.1:1/ready"},
+			expect: exitError,
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"unknown"},
+			expect: exitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.expect {
+				t.Errorf("Wrong exit code\n"+"expected: %v\n"+"actual  : %v\n%s", tt.expect, code, stderr.String())
+			}
+			for _, output := range tt.output {
+				if !strings.Contains(stdout.String(), output) {
+					t.Errorf("Missing %q in the output:\n%s", output, stdout.String())
+				}
+			}
+			if len(tt.output) == 0 && stdout.Len() > 0 {
+				t.Errorf("Unexpected output:\n%s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestProbeUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "health.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(healthcheck.NewHandler())
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"probe", "-unix", socket, "http://localhost/ready"}, &stdout, &stderr); code != exitPass {
+		t.Errorf("Wrong exit code\n"+"expected: %v\n"+"actual  : %v\n%s", exitPass, code, stderr.String())
+	}
+}
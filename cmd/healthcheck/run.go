@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/catalystgo/healthcheck/config"
+)
+
+// runResult is the outcome of a check executed by the run command.
+type runResult struct {
+	Name     string  `json:"name"`
+	Probe    string  `json:"probe"`
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// runChecks executes the checks of the configuration file directly, without
+// a handler or an HTTP server, prints their results and returns the exit code.
+func runChecks(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		path    = flags.String("config", "healthcheck.json", "path of the checks configuration file")
+		format  = flags.String("format", "table", "output format: table or json")
+		profile = flags.String("profile", "", "profile of the checks, $"+config.ProfileEnv+" by default")
+	)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "healthcheck: unknown format %q\n", *format)
+		return exitError
+	}
+
+	var opts []config.Option
+	if *profile != "" {
+		opts = append(opts, config.WithProfile(*profile))
+	}
+	cfg, err := config.Load(*path, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "healthcheck: %v\n", err)
+		return exitError
+	}
+
+	var (
+		results = make([]runResult, len(cfg.Checks))
+		wg      sync.WaitGroup
+	)
+	for i, c := range cfg.Checks {
+		check, err := c.Check()
+		if err != nil {
+			fmt.Fprintf(stderr, "healthcheck: check %q: %v\n", c.Name, err)
+			return exitError
+		}
+
+		wg.Add(1)
+		go func(i int, c config.CheckConfig) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check()
+			results[i] = runResult{
+				Name:     c.Name,
+				Probe:    c.Probe,
+				Status:   "pass",
+				Duration: time.Since(start).Seconds(),
+			}
+			if err != nil {
+				results[i].Status, results[i].Error = "fail", err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	code := exitPass
+	for i, c := range cfg.Checks {
+		if results[i].Error != "" && !c.ReportOnly {
+			code = exitFail
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "    ")
+		_ = enc.Encode(results)
+		return code
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tPROBE\tSTATUS\tDURATION\tERROR")
+	for _, res := range results {
+		duration := time.Duration(res.Duration * float64(time.Second)).Round(time.Millisecond)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\n", res.Name, res.Probe, res.Status, duration, res.Error)
+	}
+	_ = tw.Flush()
+	return code
+}
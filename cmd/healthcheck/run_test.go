@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "healthcheck.json")
+	err := os.WriteFile(path, []byte(`{"checks": [
+		{"name": "goroutines", "type": "goroutines", "probe": "liveness", "threshold": 1000000},
+		{"name": "database", "type": "tcp", "target": "127.0.0.1:1", "timeout": "1s"}
+	]}`), 0o600)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"run", "-config", path}, &stdout, &stderr); code != exitFail {
+		t.Errorf("Wrong exit code\n"+"expected: %v\n"+"actual  : %v\n%s", exitFail, code, stderr.String())
+	}
+	for _, output := range []string{"CHECK", "goroutines  liveness   pass", "database    readiness  fail"} {
+		if !strings.Contains(stdout.String(), output) {
+			t.Errorf("Missing %q in the output:\n%s", output, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := run([]string{"run", "-config", path, "-format", "json"}, &stdout, &stderr); code != exitFail {
+		t.Errorf("Wrong exit code\n"+"expected: %v\n"+"actual  : %v\n%s", exitFail, code, stderr.String())
+	}
+	var results []runResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if len(results) != 2 || results[0].Status != "pass" || results[1].Status != "fail" {
+		t.Errorf("Wrong results: %+v", results)
+	}
+
+	if code := run([]string{"run", "-config", filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr); code != exitError {
+		t.Errorf("Wrong exit code\n"+"expected: %v\n"+"actual  : %v", exitError, code)
+	}
+}
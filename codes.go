@@ -0,0 +1,50 @@
+package healthcheck
+
+import "errors"
+
+// CodedError is the error of a failed check carrying a stable machine-readable
+// code (e.g. "DB_TIMEOUT", "KAFKA_NO_BROKERS"), reported in the full output
+// alongside the message so automation can branch on the failure type.
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+// NewCodedError attaches the code to err, it returns nil if err is nil.
+//
+//	if err := db.PingContext(ctx); err != nil {
+//		return healthcheck.NewCodedError("DB_UNREACHABLE", err)
+//	}
+func NewCodedError(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// Error implements error.
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the first CodedError in the chain of err, empty if none.
+func ErrorCode(err error) string {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+// WithErrorCode sets the code of the failures of the check
+// which don't carry one in a CodedError.
+func WithErrorCode(code string) CheckOption {
+	return func(c *checkConfig) {
+		c.errorCode = code
+	}
+}
@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	err := fmt.Errorf("database: %w", NewCodedError("DB_TIMEOUT", errors.New("i/o timeout")))
+	if code := ErrorCode(err); code != "DB_TIMEOUT" {
+		t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", "DB_TIMEOUT", code)
+	}
+	if NewCodedError("DB_TIMEOUT", nil) != nil {
+		t.Errorf("Expected a nil error")
+	}
+}
+
+func TestHandlerErrorCodes(t *testing.T) {
+	h := NewHandler()
+	h.AddReadinessCheck("database", func() error {
+		return NewCodedError("DB_TIMEOUT", errors.New("i/o timeout"))
+	}, WithErrorCode("DB_UNAVAILABLE"))
+	h.AddReadinessCheck("kafka", func() error {
+		return errors.New("no brokers")
+	}, WithErrorCode("KAFKA_NO_BROKERS"))
+	h.AddReadinessCheck("cache", func() error { return nil }, WithErrorCode("CACHE_UNAVAILABLE"))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready?full=1&compact=1", nil))
+
+	expect := `{"cache":"OK",` +
+		`"database":{"status":"i/o timeout","kind":"failed","code":"DB_TIMEOUT"},` +
+		`"kafka":{"status":"no brokers","kind":"failed","code":"KAFKA_NO_BROKERS"}}` + "\n"
+	if rr.Body.String() != expect {
+		t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", expect, rr.Body.String())
+	}
+}
@@ -0,0 +1,233 @@
+// Package config declares the checks of a healthcheck.Handler in a JSON file
+// instead of code, so they can be reviewed and changed without a rebuild and
+// executed by the healthcheck command:
+//
+//	{
+//	    "checks": [
+//	        {"name": "database", "type": "tcp", "target": "db:5432", "timeout": "2s"},
+//	        {"name": "kafka", "type": "kafka", "targets": ["kafka-1:9092", "kafka-2:9092"]},
+//	        {"name": "goroutines", "type": "goroutines", "probe": "liveness", "threshold": 10000}
+//	    ]
+//	}
+//
+// The supported types are dns, tcp, http, kafka (see the checker packages)
+// and the runtime checks goroutines, heap, open_files and gc_cpu.
+//
+// The checks can be enabled, relaxed or tightened per environment by profiles
+// (see CheckOverride), selected by WithProfile or the HEALTHCHECK_PROFILE
+// environment variable.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/checker/kafka"
+	"github.com/catalystgo/healthcheck/checker/misc"
+)
+
+// DefaultTimeout is the timeout of the network checks without one.
+const DefaultTimeout = 5 * time.Second
+
+// Probes of the checks.
+const (
+	ProbeLiveness  = "liveness"
+	ProbeReadiness = "readiness"
+)
+
+// Config is the declarative configuration of the checks.
+type Config struct {
+	Checks []CheckConfig `json:"checks"`
+	// Profile is the profile applied to the checks, see WithProfile.
+	Profile string `json:"-"`
+}
+
+// CheckConfig is the declarative configuration of a check.
+type CheckConfig struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+	// Type is the type of the check: dns, tcp, http, kafka, goroutines, heap, open_files or gc_cpu.
+	Type string `json:"type"`
+	// Probe is the probe the check is added to, readiness by default.
+	Probe string `json:"probe,omitempty"`
+	// Target is the host (dns), address (tcp) or URL (http) to check.
+	Target string `json:"target,omitempty"`
+	// Proxy is the URL of the proxy of the requests (http), e.g.
+	// "http://proxy:3128" or "socks5://proxy:1080". The proxy of the
+	// environment (HTTP_PROXY, HTTPS_PROXY, NO_PROXY) is used by default.
+	Proxy string `json:"proxy,omitempty"`
+	// Targets are the brokers addresses (kafka).
+	Targets []string `json:"targets,omitempty"`
+	// Timeout is the timeout of the network checks, DefaultTimeout by default.
+	Timeout Duration `json:"timeout,omitempty"`
+	// Threshold is the threshold of the runtime checks: goroutines count,
+	// heap bytes, share of the open files limit or GC CPU fraction.
+	Threshold float64 `json:"threshold,omitempty"`
+	// Interval executes the check in background every interval if set.
+	Interval Duration `json:"interval,omitempty"`
+	// Tags are the tags of the check, see healthcheck.WithTags.
+	Tags []string `json:"tags,omitempty"`
+	// Labels are the labels of the check, see healthcheck.WithLabels.
+	Labels map[string]string `json:"labels,omitempty"`
+	// ReportOnly registers the check in report-only mode, see healthcheck.ReportOnly.
+	ReportOnly bool `json:"report_only,omitempty"`
+	// Profiles are the overrides of the check by profile, see WithProfile.
+	Profiles map[string]CheckOverride `json:"profiles,omitempty"`
+}
+
+// Duration is a time.Duration read from a string such as "1.5s" or "2m".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load reads and validates the configuration file.
+func Load(path string, opts ...Option) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, opts...)
+}
+
+// Parse parses the configuration, applies the selected profile and validates it.
+func Parse(data []byte, opts ...Option) (*Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.applyProfile(newOptions(opts).profile)
+
+	names := make(map[string]bool, len(cfg.Checks))
+	for i := range cfg.Checks {
+		c := &cfg.Checks[i]
+		if c.Name == "" {
+			return nil, fmt.Errorf("check %d: empty name", i)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("check %q: duplicate name", c.Name)
+		}
+		names[c.Name] = true
+
+		switch c.Probe {
+		case "":
+			c.Probe = ProbeReadiness
+		case ProbeLiveness, ProbeReadiness:
+		default:
+			return nil, fmt.Errorf("check %q: unknown probe %q", c.Name, c.Probe)
+		}
+		if _, err := c.Check(); err != nil {
+			return nil, fmt.Errorf("check %q: %w", c.Name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// Register adds the checks to the handler.
+func (c *Config) Register(h healthcheck.CheckRegistrar) error {
+	for _, check := range c.Checks {
+		fn, err := check.Check()
+		if err != nil {
+			return fmt.Errorf("check %q: %w", check.Name, err)
+		}
+
+		if check.Probe == ProbeLiveness {
+			h.AddLivenessCheck(check.Name, fn, check.options()...)
+		} else {
+			h.AddReadinessCheck(check.Name, fn, check.options()...)
+		}
+	}
+	return nil
+}
+
+// options returns the options of the check.
+func (c *CheckConfig) options() []healthcheck.CheckOption {
+	var opts []healthcheck.CheckOption
+	if c.Interval > 0 {
+		opts = append(opts, healthcheck.WithSchedule(healthcheck.Every(time.Duration(c.Interval))))
+	}
+	if len(c.Tags) > 0 {
+		opts = append(opts, healthcheck.WithTags(c.Tags...))
+	}
+	if len(c.Labels) > 0 {
+		opts = append(opts, healthcheck.WithLabels(c.Labels))
+	}
+	if c.ReportOnly {
+		opts = append(opts, healthcheck.ReportOnly())
+	}
+	return opts
+}
+
+// Check builds the check.
+func (c *CheckConfig) Check() (healthcheck.Check, error) {
+	timeout := time.Duration(c.Timeout)
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	switch c.Type {
+	case "dns", "tcp", "http":
+		if c.Target == "" {
+			return nil, errors.New("empty target")
+		}
+	case "kafka":
+		if len(c.Targets) == 0 {
+			return nil, errors.New("empty targets")
+		}
+	case "goroutines", "heap", "open_files", "gc_cpu":
+		if c.Threshold <= 0 {
+			return nil, errors.New("threshold must be positive")
+		}
+	}
+
+	switch c.Type {
+	case "dns":
+		return misc.DNSResolveCheck(c.Target, timeout), nil
+	case "tcp":
+		return misc.TCPDialCheck(c.Target, timeout), nil
+	case "http":
+		var opts misc.HTTPOptions
+		if c.Proxy != "" {
+			proxy, err := url.Parse(c.Proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy: %w", err)
+			}
+			opts.Proxy = http.ProxyURL(proxy)
+		}
+		return misc.HTTPGetCheckWithOptions(c.Target, timeout, opts), nil
+	case "kafka":
+		return kafka.DialCheck(c.Targets, timeout), nil
+	case "goroutines":
+		return misc.GoroutineCountCheck(int(c.Threshold)), nil
+	case "heap":
+		return misc.HeapCheck(int64(c.Threshold)), nil
+	case "open_files":
+		return misc.OpenFilesCheck(c.Threshold), nil
+	case "gc_cpu":
+		return misc.GCCPUFractionCheck(c.Threshold), nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", c.Type)
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		fails bool
+	}{
+		{
+			name: "valid",
+			data: `{"checks": [
+				{"name": "database", "type": "tcp", "target": "localhost:5432", "timeout": "2s", "interval": "30s"},
+				{"name": "goroutines", "type": "goroutines", "probe": "liveness", "threshold": 10000}
+			]}`,
+		},
+		{
+			name:  "unknown type",
+			data:  `{"checks": [{"name": "database", "type": "postgres"}]}`,
+			fails: true,
+		},
+		{
+			name:  "missing target",
+			data:  `{"checks": [{"name": "database", "type": "tcp"}]}`,
+			fails: true,
+		},
+		{
+			name:  "duplicate name",
+			data:  `{"checks": [{"name": "a", "type": "dns", "target": "a"}, {"name": "a", "type": "dns", "target": "b"}]}`,
+			fails: true,
+		},
+		{
+			name:  "unknown probe",
+			data:  `{"checks": [{"name": "a", "type": "dns", "target": "a", "probe": "startup"}]}`,
+			fails: true,
+		},
+		{
+			name:  "invalid proxy",
+			data:  `{"checks": [{"name": "a", "type": "http", "target": "http://a", "proxy": "://proxy"}]}`,
+			fails: true,
+		},
+		{
+			name:  "invalid duration",
+			data:  `{"checks": [{"name": "a", "type": "dns", "target": "a", "timeout": 5}]}`,
+			fails: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.fails {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.fails, err)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	cfg, err := Parse([]byte(`{"checks": [
+		{"name": "goroutines", "type": "goroutines", "probe": "liveness", "threshold": 1000000},
+		{"name": "unreachable", "type": "tcp", "target": "127.0.0.1:1", "timeout": "1s"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if timeout := time.Duration(cfg.Checks[1].Timeout); timeout != time.Second {
+		t.Errorf("Wrong timeout\n"+"expected: %v\n"+"actual  : %v", time.Second, timeout)
+	}
+
+	h := healthcheck.NewHandler()
+	if err := cfg.Register(h); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	if _, err := h.RunCheck(context.Background(), "goroutines"); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+	if result, err := h.RunCheck(context.Background(), "unreachable"); err != nil || result.Err == nil {
+		t.Errorf("Expected the unreachable check to fail: %v", err)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	data := []byte(`{"checks": [
+		{"name": "database", "type": "tcp", "target": "db:5432", "timeout": "2s", "profiles": {
+			"dev": {"enabled": false},
+			"staging": {"timeout": "10s", "report_only": true}
+		}},
+		{"name": "goroutines", "type": "goroutines", "threshold": 10000, "profiles": {
+			"prod": {"threshold": 5000}
+		}}
+	]}`)
+
+	tests := []struct {
+		name       string
+		profile    string
+		checks     []string
+		timeout    time.Duration
+		reportOnly bool
+		threshold  float64
+	}{
+		{name: "no profile", checks: []string{"database", "goroutines"}, timeout: 2 * time.Second, threshold: 10000},
+		{name: "dev", profile: "dev", checks: []string{"goroutines"}, threshold: 10000},
+		{name: "staging", profile: "staging", checks: []string{"database", "goroutines"}, timeout: 10 * time.Second, reportOnly: true, threshold: 10000},
+		{name: "prod", profile: "prod", checks: []string{"database", "goroutines"}, timeout: 2 * time.Second, threshold: 5000},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Parse(data, WithProfile(tt.profile))
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			checks := make(map[string]CheckConfig)
+			var names []string
+			for _, c := range cfg.Checks {
+				checks[c.Name] = c
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tt.checks) {
+				t.Fatalf("Wrong checks\n"+"expected: %v\n"+"actual  : %v", tt.checks, names)
+			}
+			if db, ok := checks["database"]; ok {
+				if time.Duration(db.Timeout) != tt.timeout || db.ReportOnly != tt.reportOnly {
+					t.Errorf("Wrong database check\n"+"expected: %v %v\n"+"actual  : %v %v",
+						tt.timeout, tt.reportOnly, time.Duration(db.Timeout), db.ReportOnly)
+				}
+			}
+			if threshold := checks["goroutines"].Threshold; threshold != tt.threshold {
+				t.Errorf("Wrong threshold\n"+"expected: %v\n"+"actual  : %v", tt.threshold, threshold)
+			}
+		})
+	}
+}
+
+func TestProfileEnv(t *testing.T) {
+	t.Setenv(ProfileEnv, "dev")
+
+	cfg, err := Parse([]byte(`{"checks": [
+		{"name": "database", "type": "tcp", "target": "db:5432", "profiles": {"dev": {"enabled": false}}}
+	]}`))
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if cfg.Profile != "dev" || len(cfg.Checks) != 0 {
+		t.Errorf("Wrong configuration\n"+"expected: %v %v\n"+"actual  : %v %v", "dev", 0, cfg.Profile, len(cfg.Checks))
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import "os"
+
+// ProfileEnv is the environment variable selecting the profile
+// of the configuration when it isn't set by WithProfile.
+const ProfileEnv = "HEALTHCHECK_PROFILE"
+
+// Option configures the loading of the configuration.
+type Option func(o *options)
+
+type options struct {
+	profile    string
+	profileSet bool
+}
+
+// WithProfile selects the profile (e.g. "dev", "staging" or "prod") applied to the
+// checks, the profile of the HEALTHCHECK_PROFILE environment variable by default.
+// An empty profile applies no overrides.
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+		o.profileSet = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.profileSet {
+		o.profile = os.Getenv(ProfileEnv)
+	}
+	return o
+}
+
+// CheckOverride overrides the configuration of a check in a profile:
+//
+//	{
+//	    "name": "database", "type": "tcp", "target": "db:5432", "timeout": "2s",
+//	    "profiles": {
+//	        "dev": {"enabled": false},
+//	        "staging": {"timeout": "10s", "report_only": true}
+//	    }
+//	}
+//
+// The unset fields keep the value of the check.
+type CheckOverride struct {
+	// Enabled removes the check from the profile if false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Timeout overrides the timeout of the network checks.
+	Timeout Duration `json:"timeout,omitempty"`
+	// Threshold overrides the threshold of the runtime checks.
+	Threshold float64 `json:"threshold,omitempty"`
+	// Interval overrides the background execution interval.
+	Interval Duration `json:"interval,omitempty"`
+	// ReportOnly overrides the report-only mode.
+	ReportOnly *bool `json:"report_only,omitempty"`
+}
+
+// applyProfile applies the overrides of the profile to the checks
+// and removes the ones it disables.
+func (c *Config) applyProfile(profile string) {
+	c.Profile = profile
+	if profile == "" {
+		return
+	}
+
+	checks := c.Checks[:0]
+	for _, check := range c.Checks {
+		override, ok := check.Profiles[profile]
+		if !ok {
+			checks = append(checks, check)
+			continue
+		}
+		if override.Enabled != nil && !*override.Enabled {
+			continue
+		}
+
+		if override.Timeout > 0 {
+			check.Timeout = override.Timeout
+		}
+		if override.Threshold > 0 {
+			check.Threshold = override.Threshold
+		}
+		if override.Interval > 0 {
+			check.Interval = override.Interval
+		}
+		if override.ReportOnly != nil {
+			check.ReportOnly = *override.ReportOnly
+		}
+		checks = append(checks, check)
+	}
+	c.Checks = checks
+}
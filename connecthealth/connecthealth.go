@@ -0,0 +1,136 @@
+// Package connecthealth implements the gRPC health checking protocol
+// (grpc.health.v1.Health) with connect-go, backed by a healthcheck.Handler.
+// It serves the gRPC, gRPC-Web and Connect protocols on any net/http server,
+// where the grpc-go health server doesn't fit.
+package connecthealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/healthsvc"
+)
+
+const (
+	// ServiceName is the fully-qualified name of the health service.
+	ServiceName = "grpc.health.v1.Health"
+
+	checkProcedure = "/" + ServiceName + "/Check"
+	watchProcedure = "/" + ServiceName + "/Watch"
+
+	// DefaultWatchInterval is the default interval at which Watch re-evaluates the status.
+	DefaultWatchInterval = 5 * time.Second
+)
+
+// Option configures the health service.
+type Option func(s *service)
+
+// WithWatchInterval sets the interval at which Watch re-evaluates the status,
+// DefaultWatchInterval by default.
+func WithWatchInterval(interval time.Duration) Option {
+	return func(s *service) {
+		s.watchInterval = interval
+	}
+}
+
+// WithHandlerOptions sets the connect options of the service handlers
+// (interceptors, compression...).
+func WithHandlerOptions(opts ...connect.HandlerOption) Option {
+	return func(s *service) {
+		s.handlerOpts = append(s.handlerOpts, opts...)
+	}
+}
+
+// NewHandler returns the path the health service must be mounted on and its handler:
+//
+//	mux.Handle(connecthealth.NewHandler(handler))
+//
+// The status of the empty service name is the readiness of the handler, the status
+// of any other service name is the result of the check with this name; unknown
+// names are reported as NotFound, as required by the protocol.
+func NewHandler(h healthcheck.Handler, opts ...Option) (string, http.Handler) {
+	s := &service{
+		handler:       h,
+		watchInterval: DefaultWatchInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(checkProcedure, connect.NewUnaryHandler(checkProcedure, s.check, s.handlerOpts...))
+	mux.Handle(watchProcedure, connect.NewServerStreamHandler(watchProcedure, s.watch, s.handlerOpts...))
+	return "/" + ServiceName + "/", mux
+}
+
+type service struct {
+	handler       healthcheck.Handler
+	watchInterval time.Duration
+	handlerOpts   []connect.HandlerOption
+}
+
+func (s *service) check(
+	ctx context.Context,
+	req *connect.Request[healthpb.HealthCheckRequest],
+) (*connect.Response[healthpb.HealthCheckResponse], error) {
+	status, err := s.status(ctx, req.Msg.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&healthpb.HealthCheckResponse{Status: status}), nil
+}
+
+func (s *service) watch(
+	ctx context.Context,
+	req *connect.Request[healthpb.HealthCheckRequest],
+	stream *connect.ServerStream[healthpb.HealthCheckResponse],
+) error {
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(healthsvc.Unknown)
+	for {
+		status, err := s.status(ctx, req.Msg.GetService())
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			// unknown services are watched until they're known, as required by the protocol
+			status, err = healthpb.HealthCheckResponse_SERVICE_UNKNOWN, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if status != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+			last = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// status evaluates the status of the service, with the connect error codes.
+func (s *service) status(ctx context.Context, name string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	serving, err := healthsvc.Status(ctx, s.handler, name)
+	status := healthpb.HealthCheckResponse_ServingStatus(serving)
+	switch {
+	case errors.Is(err, healthcheck.ErrCheckNotFound):
+		return status, connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status, connect.NewError(connect.CodeDeadlineExceeded, err)
+	case err != nil:
+		return status, connect.NewError(connect.CodeCanceled, err)
+	}
+	return status, nil
+}
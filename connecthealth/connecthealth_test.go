@@ -0,0 +1,90 @@
+package connecthealth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/healthsvc"
+)
+
+func TestCheck(t *testing.T) {
+	h := healthcheck.NewHandler()
+	h.AddLivenessCheck("live", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("failed") })
+
+	mux := http.NewServeMux()
+	mux.Handle(NewHandler(h))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](
+		server.Client(), server.URL+checkProcedure)
+
+	tests := []struct {
+		name         string
+		service      string
+		expect       healthpb.HealthCheckResponse_ServingStatus
+		expectedCode connect.Code
+	}{
+		{
+			name:    "overall readiness",
+			service: "",
+			expect:  healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "passing check",
+			service: "live",
+			expect:  healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:    "failing check",
+			service: "database",
+			expect:  healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:         "unknown check",
+			service:      "unknown",
+			expectedCode: connect.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.CallUnary(context.Background(),
+				connect.NewRequest(&healthpb.HealthCheckRequest{Service: tt.service}))
+			if tt.expectedCode != 0 {
+				if connect.CodeOf(err) != tt.expectedCode {
+					t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expectedCode, connect.CodeOf(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if resp.Msg.GetStatus() != tt.expect {
+				t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", tt.expect, resp.Msg.GetStatus())
+			}
+		})
+	}
+}
+
+func TestServingStatusValues(t *testing.T) {
+	tests := map[healthsvc.ServingStatus]healthpb.HealthCheckResponse_ServingStatus{
+		healthsvc.StatusUnknown: healthpb.HealthCheckResponse_UNKNOWN,
+		healthsvc.Serving:       healthpb.HealthCheckResponse_SERVING,
+		healthsvc.NotServing:    healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+
+	for status, expect := range tests {
+		if actual := healthpb.HealthCheckResponse_ServingStatus(status); actual != expect {
+			t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", expect, actual)
+		}
+	}
+}
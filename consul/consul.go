@@ -0,0 +1,202 @@
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/probe"
+)
+
+const (
+	// DefaultAddress is the default address of the Consul agent.
+	DefaultAddress = "http://127.0.0.1:8500"
+	// DefaultTTL is the default TTL of the Consul check.
+	DefaultTTL = 30 * time.Second
+)
+
+// Consul check statuses.
+const (
+	StatusPassing  = "passing"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+)
+
+// Config configures the registration of the service in Consul.
+type Config struct {
+	// Address is the address of the Consul agent, DefaultAddress by default.
+	Address string
+	// Token is the ACL token, if any.
+	Token string
+
+	// ServiceID is the ID of the service instance, ServiceName by default.
+	ServiceID string
+	// ServiceName is the name of the service.
+	ServiceName string
+	// ServiceAddress and ServicePort are the address of the service instance.
+	ServiceAddress string
+	ServicePort    int
+	// Tags are the tags of the service.
+	Tags []string
+
+	// TTL is the TTL of the check, DefaultTTL by default: the check becomes
+	// critical if the updater doesn't push an update in time.
+	TTL time.Duration
+	// Interval is the interval between updates, a third of TTL by default.
+	Interval time.Duration
+	// DeregisterCriticalServiceAfter makes Consul deregister the service
+	// if the check stays critical for that long, never by default.
+	DeregisterCriticalServiceAfter time.Duration
+}
+
+// Updater registers the service in Consul with a TTL check and periodically pushes
+// the aggregated state of the handler to it: passing when liveness and readiness pass,
+// warning when they pass but some (non-critical or report-only) checks fail,
+// critical otherwise.
+type Updater struct {
+	handler healthcheck.Prober
+	config  Config
+	client  *http.Client
+}
+
+// NewUpdater creates an Updater pushing the state of the handler.
+func NewUpdater(h healthcheck.Prober, config Config) *Updater {
+	if config.Address == "" {
+		config.Address = DefaultAddress
+	}
+	if config.ServiceID == "" {
+		config.ServiceID = config.ServiceName
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.Interval <= 0 {
+		config.Interval = config.TTL / 3
+	}
+
+	return &Updater{
+		handler: h,
+		config:  config,
+		client:  &http.Client{Timeout: config.Interval},
+	}
+}
+
+// CheckID returns the ID of the TTL check registered in Consul.
+func (u *Updater) CheckID() string {
+	return "service:" + u.config.ServiceID
+}
+
+// Run registers the service and pushes updates until the context is done,
+// then deregisters the service. Failed updates are retried on the next interval,
+// only the registration error is returned.
+func (u *Updater) Run(ctx context.Context) error {
+	if err := u.Register(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		// the context is done already, so deregister with a fresh one
+		deregisterCtx, cancel := context.WithTimeout(context.Background(), u.config.Interval)
+		defer cancel()
+		_ = u.Deregister(deregisterCtx)
+	}()
+
+	ticker := time.NewTicker(u.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = u.Update(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Register registers the service along with its TTL check.
+func (u *Updater) Register(ctx context.Context) error {
+	check := map[string]any{
+		"CheckID": u.CheckID(),
+		"Name":    "healthcheck",
+		"TTL":     u.config.TTL.String(),
+	}
+	if u.config.DeregisterCriticalServiceAfter > 0 {
+		check["DeregisterCriticalServiceAfter"] = u.config.DeregisterCriticalServiceAfter.String()
+	}
+
+	return u.put(ctx, "/v1/agent/service/register", map[string]any{
+		"ID":      u.config.ServiceID,
+		"Name":    u.config.ServiceName,
+		"Address": u.config.ServiceAddress,
+		"Port":    u.config.ServicePort,
+		"Tags":    u.config.Tags,
+		"Check":   check,
+	})
+}
+
+// Deregister removes the service from Consul.
+func (u *Updater) Deregister(ctx context.Context) error {
+	return u.put(ctx, "/v1/agent/service/deregister/"+url.PathEscape(u.config.ServiceID), nil)
+}
+
+// Update evaluates the handler and pushes its state to the TTL check.
+func (u *Updater) Update(ctx context.Context) error {
+	status, output := u.state()
+	return u.put(ctx, "/v1/agent/check/update/"+url.PathEscape(u.CheckID()), map[string]string{
+		"Status": status,
+		"Output": output,
+	})
+}
+
+// state returns the Consul status and output of the handler state.
+func (u *Updater) state() (status, output string) {
+	live := probe.Live(u.handler)
+	if !live.Healthy() {
+		return StatusCritical, live.Summary()
+	}
+
+	ready := probe.Ready(u.handler)
+	switch {
+	case !ready.Healthy():
+		return StatusCritical, ready.Summary()
+	case len(ready.Failed()) > 0:
+		return StatusWarning, ready.Summary()
+	default:
+		return StatusPassing, ready.Summary()
+	}
+}
+
+func (u *Updater) put(ctx context.Context, path string, body any) error {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.config.Address+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if u.config.Token != "" {
+		req.Header.Set("X-Consul-Token", u.config.Token)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("consul returned status %d for %s", resp.StatusCode, path)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// fakeAgent records the requests to the Consul agent API.
+type fakeAgent struct {
+	mu       sync.Mutex
+	requests []string
+	updates  []map[string]string
+	updated  chan struct{}
+}
+
+func newFakeAgent(t *testing.T) (*fakeAgent, *httptest.Server) {
+	agent := &fakeAgent{updated: make(chan struct{}, 1)}
+	srv := httptest.NewServer(agent)
+	t.Cleanup(srv.Close)
+	return agent, srv
+}
+
+func (a *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r.Method != http.MethodPut || r.Header.Get("X-Consul-Token") != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	a.requests = append(a.requests, r.URL.Path)
+
+	if r.URL.Path == "/v1/agent/check/update/service:api-1" {
+		var update map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		a.updates = append(a.updates, update)
+
+		select {
+		case a.updated <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (a *fakeAgent) snapshot() ([]string, []map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.requests...), append([]map[string]string(nil), a.updates...)
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(h healthcheck.Handler)
+		expect string
+	}{
+		{
+			name:   "passing",
+			setup:  func(healthcheck.Handler) {},
+			expect: StatusPassing,
+		},
+		{
+			name: "warning",
+			setup: func(h healthcheck.Handler) {
+				h.AddReadinessCheck("cache", func() error { return errors.New("timeout") }, healthcheck.WithCriticality(healthcheck.Important))
+			},
+			expect: StatusWarning,
+		},
+		{
+			name: "critical",
+			setup: func(h healthcheck.Handler) {
+				h.AddReadinessCheck("database", func() error { return errors.New("connection refused") })
+			},
+			expect: StatusCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			agent, srv := newFakeAgent(t)
+
+			h := healthcheck.NewHandler()
+			tt.setup(h)
+
+			u := NewUpdater(h, Config{Address: srv.URL, Token: "secret", ServiceID: "api-1", ServiceName: "api"})
+			if err := u.Update(context.Background()); err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			_, updates := agent.snapshot()
+			if len(updates) != 1 || updates[0]["Status"] != tt.expect {
+				t.Errorf("Wrong check updates\n"+"expected: %v\n"+"actual  : %v", tt.expect, updates)
+			}
+		})
+	}
+}
+
+func TestUpdateError(t *testing.T) {
+	_, srv := newFakeAgent(t)
+
+	u := NewUpdater(healthcheck.NewHandler(), Config{Address: srv.URL, ServiceName: "api"})
+	if err := u.Update(context.Background()); err == nil {
+		t.Errorf("Expected an error of the rejected update")
+	}
+}
+
+func TestRun(t *testing.T) {
+	agent, srv := newFakeAgent(t)
+
+	u := NewUpdater(healthcheck.NewHandler(), Config{
+		Address:     srv.URL,
+		Token:       "secret",
+		ServiceID:   "api-1",
+		ServiceName: "api",
+		TTL:         time.Minute,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- u.Run(ctx) }()
+
+	<-agent.updated
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	requests, _ := agent.snapshot()
+	expect := []string{
+		"/v1/agent/service/register",
+		"/v1/agent/check/update/service:api-1",
+		"/v1/agent/service/deregister/api-1",
+	}
+	if len(requests) != len(expect) {
+		t.Fatalf("Wrong requests\n"+"expected: %v\n"+"actual  : %v", expect, requests)
+	}
+	for i := range expect {
+		if requests[i] != expect[i] {
+			t.Errorf("Wrong request\n"+"expected: %v\n"+"actual  : %v", expect[i], requests[i])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package healthcheck
+
+import "net/http"
+
+// Criticality is the impact of the failure of a readiness check on the readiness probe.
+type Criticality int
+
+const (
+	// Critical checks fail the readiness probe with 503 Service Unavailable, the default.
+	Critical Criticality = iota
+	// Important checks degrade the readiness probe: it responds with the status
+	// set by WithDegradedStatus (200 OK by default) and Check reports StatusWarn.
+	Important
+	// Informational checks never affect the readiness probe,
+	// they're only reported in the full output and metrics.
+	Informational
+)
+
+// String returns the name of the criticality.
+func (c Criticality) String() string {
+	switch c {
+	case Critical:
+		return "critical"
+	case Important:
+		return "important"
+	case Informational:
+		return "informational"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCriticality sets the criticality of the readiness check, Critical by default,
+// enabling a policy per dependency: e.g. the database is Critical, the
+// recommendations service Important and the analytics pipeline Informational.
+// The liveness probe isn't affected.
+func WithCriticality(c Criticality) CheckOption {
+	return func(cfg *checkConfig) {
+		cfg.criticality = c
+	}
+}
+
+// WithDegradedStatus sets the HTTP status of the readiness probe when Important
+// checks fail but no Critical one does, 200 OK by default so the instance stays
+// in rotation; e.g. 207 Multi-Status lets load balancers matching on the status
+// tell degraded instances apart.
+func WithDegradedStatus(code int) Option {
+	return func(h *basicHandler) {
+		h.degradedStatus = code
+	}
+}
+
+// critical reports whether the failure of the readiness check fails the readiness probe.
+func (c *checkConfig) critical() bool {
+	return !c.nonCritical && c.criticality == Critical
+}
+
+// probeState is the outcome of a probe.
+type probeState int
+
+const (
+	probePass probeState = iota
+	probeDegraded
+	probeFail
+)
+
+// statusCode returns the HTTP status of the probe outcome.
+func (s *basicHandler) statusCode(state probeState) int {
+	switch state {
+	case probePass:
+		return http.StatusOK
+	case probeDegraded:
+		return s.degradedStatus
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
@@ -0,0 +1,91 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCriticality(t *testing.T) {
+	failing := func() error { return errors.New("failed") }
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		failing      []Criticality
+		expectCode   int
+		expectStatus Status
+	}{
+		{
+			name:         "no failure",
+			expectCode:   http.StatusOK,
+			expectStatus: StatusPass,
+		},
+		{
+			name:         "critical failure",
+			failing:      []Criticality{Critical, Important},
+			expectCode:   http.StatusServiceUnavailable,
+			expectStatus: StatusFail,
+		},
+		{
+			name:         "important failure",
+			failing:      []Criticality{Important, Informational},
+			expectCode:   http.StatusOK,
+			expectStatus: StatusWarn,
+		},
+		{
+			name:         "important failure with degraded status",
+			opts:         []Option{WithDegradedStatus(http.StatusMultiStatus)},
+			failing:      []Criticality{Important},
+			expectCode:   http.StatusMultiStatus,
+			expectStatus: StatusWarn,
+		},
+		{
+			name:         "informational failure",
+			failing:      []Criticality{Informational},
+			expectCode:   http.StatusOK,
+			expectStatus: StatusPass,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.opts...)
+			h.AddReadinessCheck("passing", func() error { return nil })
+			for _, c := range tt.failing {
+				h.AddReadinessCheck(c.String(), failing, WithCriticality(c))
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+			if rr.Code != tt.expectCode {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expectCode, rr.Code)
+			}
+
+			status, _, err := h.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if status != tt.expectStatus {
+				t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", tt.expectStatus, status)
+			}
+		})
+	}
+}
+
+func TestCriticalityLiveness(t *testing.T) {
+	h := NewHandler()
+	h.AddLivenessCheck("informational", func() error { return errors.New("failed") }, WithCriticality(Informational))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, LivenessHandlerPath, nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+	}
+}
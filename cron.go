@@ -0,0 +1,206 @@
+package healthcheck
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds the search of the next matching time,
+// so impossible expressions (e.g. "0 0 30 2 *") don't loop forever.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDays   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// cronField describes the allowed values of a cron expression field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] is an alias of min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonths},
+	{name: "day of week", min: 0, max: 7, names: cronDays}, // 7 is Sunday as well
+}
+
+// cronSchedule is a Schedule defined by a cron expression.
+// Every field is a bit set of the matching values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are set when the corresponding day field is "*".
+	// If both day fields are restricted, a day matches if either of them matches.
+	domAny, dowAny bool
+}
+
+// ParseCron parses a standard 5 field cron expression
+// ("minute hour day-of-month month day-of-week") into a Schedule.
+// Fields support "*", values, ranges ("1-5"), steps ("*/15", "0-30/10"),
+// lists ("1,15") and month/day names ("jan", "mon"). Descriptors
+// "@yearly", "@monthly", "@weekly", "@daily" and "@hourly" are supported too.
+// The schedule is evaluated in the location of the time passed to Next.
+func ParseCron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q: expected %d fields, got %d", expr, len(cronFields), len(parts))
+	}
+
+	sets := make([]uint64, len(parts))
+	for i, part := range parts {
+		set, err := cronFields[i].parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be written both as 0 and 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression can't be parsed.
+func MustParseCron(expr string) Schedule {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			rng = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, item[i+1:])
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			var err error
+			bounds := strings.SplitN(rng, "-", 2)
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "5/10" means "from 5 to the end every 10"
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rng)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// Next returns the first matching minute after t.
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Duration(c.minutesToNext(t.Minute())) * time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// minutesToNext returns the number of minutes until the next matching
+// minute of the hour, or until the next hour if there's none.
+func (c *cronSchedule) minutesToNext(minute int) int {
+	if rest := c.minute >> uint(minute); rest != 0 {
+		return bits.TrailingZeros64(rest)
+	}
+	return 60 - minute
+}
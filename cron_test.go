@@ -0,0 +1,102 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name   string
+		expr   string
+		expect time.Time
+	}{
+		{
+			name:   "every minute",
+			expr:   "* * * * *",
+			expect: time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:   "every 15 minutes",
+			expr:   "*/15 * * * *",
+			expect: time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:   "daily at 03:00",
+			expr:   "0 3 * * *",
+			expect: time.Date(2024, time.March, 16, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "hourly descriptor",
+			expr:   "@hourly",
+			expect: time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "weekdays by name",
+			expr:   "0 9 * * mon-fri",
+			expect: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "sunday as 7",
+			expr:   "0 0 * * 7",
+			expect: time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "day of month or day of week",
+			expr:   "0 0 20 * sat",
+			expect: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "first of a month with a list",
+			expr:   "0 0 1 jan,jul *",
+			expect: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "leap day",
+			expr:   "0 0 29 2 *",
+			expect: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "impossible date",
+			expr: "0 0 30 2 *",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			if next := schedule.Next(from); !next.Equal(tt.expect) {
+				t.Errorf("Wrong next time for %q\n"+
+					"expected: %v\n"+
+					"actual  : %v", tt.expr, tt.expect, next)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * foo *",
+		"*/0 * * * *",
+		"10-5 * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
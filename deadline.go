@@ -0,0 +1,66 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrExpired is the error of a DeadlineCheck whose expiry has passed.
+var ErrExpired = errors.New("expired")
+
+// DeadlineOption configures a DeadlineCheck.
+type DeadlineOption func(d *deadlineCheck)
+
+// WithDeadlineClock sets the Clock of the deadline check, SystemClock by default.
+func WithDeadlineClock(clock Clock) DeadlineOption {
+	return func(d *deadlineCheck) {
+		d.clock = clock
+	}
+}
+
+// DeadlineCheck returns a Checker of a known expiry such as a license, an API
+// token or a service account key. Within warnBefore of the expiry it degrades:
+// it still passes but reports expiring_soon in its details, along with the
+// expiry and the remaining time (exported as a metric to alert on). Once the
+// expiry has passed it fails with ErrExpired.
+//
+//	handler.AddReadinessChecker(healthcheck.DeadlineCheck("license", license.NotAfter, 30*24*time.Hour))
+func DeadlineCheck(name string, expiresAt time.Time, warnBefore time.Duration, opts ...DeadlineOption) Checker {
+	d := &deadlineCheck{
+		name:       name,
+		expiresAt:  expiresAt,
+		warnBefore: warnBefore,
+		clock:      SystemClock,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// deadlineCheck is the Checker returned by DeadlineCheck.
+type deadlineCheck struct {
+	name       string
+	expiresAt  time.Time
+	warnBefore time.Duration
+	clock      Clock
+}
+
+func (d *deadlineCheck) Name() string {
+	return d.name
+}
+
+func (d *deadlineCheck) Check(context.Context) Result {
+	remaining := d.expiresAt.Sub(d.clock.Now())
+	res := Result{Details: map[string]any{
+		"expires_at":    d.expiresAt.UTC().Format(time.RFC3339),
+		"remaining":     remaining,
+		"expiring_soon": remaining <= d.warnBefore,
+	}}
+	if remaining <= 0 {
+		res.Err = fmt.Errorf("%s: %w since %v", d.name, ErrExpired, -remaining.Round(time.Second))
+	}
+	return res
+}
@@ -0,0 +1,46 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadlineCheck(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		expiring bool
+		expired  bool
+	}{
+		{name: "valid", elapsed: time.Hour},
+		{name: "expiring soon", elapsed: 29 * 24 * time.Hour, expiring: true},
+		{name: "expired", elapsed: 31 * 24 * time.Hour, expiring: true, expired: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := NewManualClock(start)
+			clock.Advance(tt.elapsed)
+			check := DeadlineCheck("license", start.Add(30*24*time.Hour), 7*24*time.Hour, WithDeadlineClock(clock))
+
+			res := check.Check(context.Background())
+			if expired := errors.Is(res.Err, ErrExpired); expired != tt.expired {
+				t.Errorf("Wrong error\n"+"expected expired: %v\n"+"actual  : %v", tt.expired, res.Err)
+			}
+			if expiring := res.Details["expiring_soon"]; expiring != tt.expiring {
+				t.Errorf("Wrong expiring_soon\n"+"expected: %v\n"+"actual  : %v", tt.expiring, expiring)
+			}
+			if remaining := res.Metrics()["remaining"]; remaining != (30*24*time.Hour - tt.elapsed).Seconds() {
+				t.Errorf("Wrong remaining\n"+"expected: %v\n"+"actual  : %v", (30*24*time.Hour - tt.elapsed).Seconds(), remaining)
+			}
+		})
+	}
+}
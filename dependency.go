@@ -0,0 +1,107 @@
+package healthcheck
+
+import "fmt"
+
+// DependsOn declares that the check depends on the checks with the given names
+// (e.g. a schema check depends on a ping check). The check is executed after its
+// dependencies and, if any of them fails, it's skipped and reported as failed with
+// "skipped: dependency failed" instead of piling up duplicate errors and load on
+// a dependency that is down. Dependencies which aren't evaluated by a probe
+// (e.g. readiness checks for the liveness probe) are ignored.
+// Adding a check that creates a dependency cycle panics.
+func DependsOn(names ...string) CheckOption {
+	return func(c *checkConfig) {
+		c.dependencies = append(c.dependencies, names...)
+	}
+}
+
+// checkRun is the state of a check during a single evaluation.
+type checkRun struct {
+	done   chan struct{}
+	result Result
+}
+
+// waitDependencies waits for the dependencies of the check to complete
+// and returns an error if any of them failed.
+func waitDependencies(entry *checkEntry, runs map[string]*checkRun) error {
+	for _, name := range entry.config.dependencies {
+		run, ok := runs[name]
+		if !ok {
+			continue
+		}
+
+		<-run.done
+		if checkFailed(run.result.Err) {
+			return fmt.Errorf("%w: dependency %q failed", ErrSkipped, name)
+		}
+	}
+	return nil
+}
+
+// dependencyCycle reports whether adding a check with the given name and
+// dependencies creates a cycle. The caller must hold checksMutex.
+func (s *basicHandler) dependencyCycle(name string, dependencies []string) bool {
+	visited := make(map[string]bool)
+
+	var reaches func(from string) bool
+	reaches = func(from string) bool {
+		if from == name {
+			return true
+		}
+		if visited[from] {
+			return false
+		}
+		visited[from] = true
+
+		for _, checks := range []map[string]*checkEntry{s.livenessChecks, s.readinessChecks} {
+			if entry, ok := checks[from]; ok {
+				for _, dep := range entry.config.dependencies {
+					if reaches(dep) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	for _, dep := range dependencies {
+		if reaches(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByDependencies returns the checks ordered so that every check comes
+// after its dependencies. Cycles are rejected by addCheck.
+func sortByDependencies(checks []*checkEntry) []*checkEntry {
+	var (
+		byName  = make(map[string]*checkEntry, len(checks))
+		visited = make(map[string]bool, len(checks))
+		sorted  = make([]*checkEntry, 0, len(checks))
+	)
+	for _, entry := range checks {
+		byName[entry.name] = entry
+	}
+
+	var visit func(entry *checkEntry)
+	visit = func(entry *checkEntry) {
+		if visited[entry.name] {
+			return
+		}
+		visited[entry.name] = true
+
+		for _, dep := range entry.config.dependencies {
+			if depEntry, ok := byName[dep]; ok {
+				visit(depEntry)
+			}
+		}
+		sorted = append(sorted, entry)
+	}
+
+	for _, entry := range checks {
+		visit(entry)
+	}
+	return sorted
+}
@@ -0,0 +1,63 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDependsOn(t *testing.T) {
+	var schemaCalls atomic.Int32
+
+	h := NewHandler()
+	h.AddLivenessCheck("db_ping", func() error { return errors.New("connection refused") })
+	h.AddReadinessCheck("db_schema", func() error {
+		schemaCalls.Add(1)
+		return nil
+	}, DependsOn("db_ping"))
+	h.AddReadinessCheck("db_migrations", func() error { return nil }, DependsOn("db_schema"))
+	h.AddReadinessCheck("cache", func() error { return nil })
+
+	req, err := http.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1", nil)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wrong code\n"+
+			"expected: %v\n"+
+			"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+	}
+	if schemaCalls.Load() != 0 {
+		t.Errorf("Dependent check must not be executed when its dependency fails")
+	}
+
+	expectBody := "{\n" +
+		"    \"cache\": \"OK\",\n" +
+		"    \"db_migrations\": \"skipped: dependency \\\"db_schema\\\" failed\",\n" +
+		"    \"db_ping\": \"connection refused\",\n" +
+		"    \"db_schema\": \"skipped: dependency \\\"db_ping\\\" failed\"\n" +
+		"}\n"
+	if rr.Body.String() != expectBody {
+		t.Errorf("Wrong body\n"+
+			"expected: %v"+
+			"actual  : %v", expectBody, rr.Body.String())
+	}
+}
+
+func TestDependsOnCycle(t *testing.T) {
+	h := NewHandler()
+	h.AddReadinessCheck("a", func() error { return nil }, DependsOn("b"))
+	h.AddReadinessCheck("b", func() error { return nil }, DependsOn("c"))
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Adding a dependency cycle must panic")
+		}
+	}()
+	h.AddLivenessCheck("c", func() error { return nil }, DependsOn("a"))
+}
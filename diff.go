@@ -0,0 +1,138 @@
+package healthcheck
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiffHandlerPath path to the changes of the checks since a given time.
+const DiffHandlerPath = "/health/diff"
+
+// CheckChange is a check which started failing or recovered.
+type CheckChange struct {
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Error is the error of the failing check, empty if it recovered.
+	Error string `json:"error,omitempty"`
+	// Time is the time of the first evaluation observing the change.
+	Time time.Time `json:"time"`
+}
+
+// Diff is the changes of the checks since the time requested by the client.
+type Diff struct {
+	// Since is the time requested with ?since=, zero if none was.
+	Since time.Time `json:"since"`
+	// Time is the time of the evaluation of the request, to be passed
+	// as ?since= by the next request.
+	Time time.Time `json:"time"`
+	// Failing are the checks which started failing since then,
+	// all the failing checks without ?since=.
+	Failing []CheckChange `json:"failing"`
+	// Recovered are the checks which recovered since then.
+	Recovered []CheckChange `json:"recovered"`
+}
+
+// checkTransition is the state of a check and the time it was first observed.
+type checkTransition struct {
+	failing bool
+	since   time.Time
+	// initial is set until the state of the check changes:
+	// the first state observed isn't a recovery.
+	initial bool
+}
+
+// changeTracker tracks the transitions of the checks over the evaluations.
+type changeTracker struct {
+	mu      sync.Mutex
+	current map[string]checkTransition
+}
+
+// observe records the transitions of the evaluated checks.
+func (t *changeTracker) observe(results map[string]Result, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.observeLocked(results, now)
+}
+
+// observeLocked is observe with t.mu held.
+func (t *changeTracker) observeLocked(results map[string]Result, now time.Time) {
+	if t.current == nil {
+		t.current = make(map[string]checkTransition)
+	}
+	for name, res := range results {
+		failing := checkFailed(res.Err)
+		prev, ok := t.current[name]
+		if !ok {
+			t.current[name] = checkTransition{failing: failing, since: now, initial: true}
+		} else if prev.failing != failing {
+			t.current[name] = checkTransition{failing: failing, since: now}
+		}
+	}
+}
+
+// diff observes the evaluated checks and returns the changes of the checks
+// observed after since, all the failing checks if since is zero. Both are done
+// under the lock, so the transitions match the results: a concurrent probe
+// can't mark a check of the results as failing while it passed here.
+func (t *changeTracker) diff(results map[string]Result, since, now time.Time) Diff {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.observeLocked(results, now)
+
+	d := Diff{Since: since, Time: now, Failing: []CheckChange{}, Recovered: []CheckChange{}}
+	for name, res := range results {
+		transition := t.current[name]
+		if !transition.since.After(since) {
+			continue
+		}
+		switch {
+		case transition.failing:
+			d.Failing = append(d.Failing, CheckChange{Check: name, Error: res.Err.Error(), Time: transition.since})
+		case !transition.initial:
+			d.Recovered = append(d.Recovered, CheckChange{Check: name, Time: transition.since})
+		}
+	}
+
+	sort.Slice(d.Failing, func(i, j int) bool { return d.Failing[i].Check < d.Failing[j].Check })
+	sort.Slice(d.Recovered, func(i, j int) bool { return d.Recovered[i].Check < d.Recovered[j].Check })
+	return d
+}
+
+// DiffEndpoint is an HTTP handler evaluating all the checks and returning
+// the ones which started failing or recovered after the RFC 3339 time of
+// ?since= as JSON, e.g. for chat bots posting the changes: each client passes
+// the time of its previous response, so clients don't interfere with each
+// other. The time of a change is the time of the first evaluation (by any
+// probe or endpoint) observing it. Without ?since=, the failing checks are returned.
+func (s *basicHandler) DiffEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) {
+		return
+	}
+
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+
+	results := s.collectChecks(s.entries(s.readinessChecks, s.livenessChecks))
+	writeJSON(w, http.StatusOK, s.changes.diff(results, since, s.clock.Now()))
+}
+
+// diffOperation describes the diff endpoint.
+func diffOperation() openAPIOperation {
+	op := jsonOperation("Changes of the checks since a given time", "Diff")
+	op.Parameters = []openAPIParameter{{
+		Name:        "since",
+		In:          "query",
+		Description: "RFC 3339 time of the previous response, the failing checks are returned without it.",
+		Schema:      openAPISchema{"type": "string", "format": "date-time"},
+	}}
+	op.Responses["400"] = openAPIResponse{Description: "Invalid since."}
+	return op
+}
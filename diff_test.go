@@ -0,0 +1,115 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiffEndpoint(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	h := NewHandler(WithClock(clock))
+
+	var cacheFailing, databaseFailing atomic.Bool
+	cacheFailing.Store(true)
+	h.AddReadinessCheck("cache", func() error {
+		if cacheFailing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	h.AddReadinessCheck("database", func() error {
+		if databaseFailing.Load() {
+			return errors.New("i/o timeout")
+		}
+		return nil
+	})
+
+	diff := func(since time.Time) Diff {
+		target := DiffHandlerPath
+		if !since.IsZero() {
+			target += "?since=" + since.Format(time.RFC3339Nano)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+
+		var d Diff
+		if err := json.Unmarshal(rr.Body.Bytes(), &d); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		return d
+	}
+
+	expect := Diff{
+		Time:      start,
+		Failing:   []CheckChange{{Check: "cache", Error: "connection refused", Time: start}},
+		Recovered: []CheckChange{},
+	}
+	if d := diff(time.Time{}); !reflect.DeepEqual(d, expect) {
+		t.Errorf("Wrong first diff\n"+"expected: %+v\n"+"actual  : %+v", expect, d)
+	}
+
+	// the change is timestamped by the probe observing it
+	clock.Advance(time.Minute)
+	cacheFailing.Store(false)
+	databaseFailing.Store(true)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	clock.Advance(time.Minute)
+
+	expect = Diff{
+		Since:     start,
+		Time:      start.Add(2 * time.Minute),
+		Failing:   []CheckChange{{Check: "database", Error: "i/o timeout", Time: start.Add(time.Minute)}},
+		Recovered: []CheckChange{{Check: "cache", Time: start.Add(time.Minute)}},
+	}
+	if d := diff(start); !reflect.DeepEqual(d, expect) {
+		t.Errorf("Wrong second diff\n"+"expected: %+v\n"+"actual  : %+v", expect, d)
+	}
+
+	expect = Diff{Since: start.Add(2 * time.Minute), Time: start.Add(2 * time.Minute), Failing: []CheckChange{}, Recovered: []CheckChange{}}
+	if d := diff(start.Add(2 * time.Minute)); !reflect.DeepEqual(d, expect) {
+		t.Errorf("Wrong unchanged diff\n"+"expected: %+v\n"+"actual  : %+v", expect, d)
+	}
+
+	// another client gets the changes since its own previous request
+	expect = Diff{
+		Since:     start,
+		Time:      start.Add(2 * time.Minute),
+		Failing:   []CheckChange{{Check: "database", Error: "i/o timeout", Time: start.Add(time.Minute)}},
+		Recovered: []CheckChange{{Check: "cache", Time: start.Add(time.Minute)}},
+	}
+	if d := diff(start); !reflect.DeepEqual(d, expect) {
+		t.Errorf("Wrong diff of another client\n"+"expected: %+v\n"+"actual  : %+v", expect, d)
+	}
+}
+
+func TestDiffEndpointInvalidSince(t *testing.T) {
+	h := NewHandler()
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, DiffHandlerPath+"?since=yesterday", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestChangeTrackerConcurrentProbe(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var tracker changeTracker
+	tracker.observe(map[string]Result{"cache": {}}, start)
+	// a concurrent probe observes a failure the request didn't
+	tracker.observe(map[string]Result{"cache": {Err: errors.New("timeout")}}, start.Add(time.Second))
+
+	d := tracker.diff(map[string]Result{"cache": {}}, start, start.Add(2*time.Second))
+	expect := []CheckChange{{Check: "cache", Time: start.Add(2 * time.Second)}}
+	if len(d.Failing) != 0 || !reflect.DeepEqual(d.Recovered, expect) {
+		t.Errorf("Wrong diff\n"+"expected: %+v\n"+"actual  : %+v", expect, d)
+	}
+}
@@ -0,0 +1,85 @@
+package healthcheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+)
+
+// goroutineDumpErrorName is the name passed to the error handler
+// when a goroutine dump can't be delivered to its sink.
+const goroutineDumpErrorName = "goroutine_dump"
+
+// GoroutineDumpSink receives a goroutine profile (in the same format
+// as a panic stack trace) captured when liveness starts failing.
+type GoroutineDumpSink func(dump []byte) error
+
+// WithGoroutineDump captures a goroutine profile and passes it to the sink
+// every time liveness transitions from passing to failing, so post-mortem data
+// isn't lost when the orchestrator restarts the instance.
+// Sink errors are reported to the check error handler.
+func WithGoroutineDump(sink GoroutineDumpSink) Option {
+	return func(h *basicHandler) {
+		h.goroutineDumpSink = sink
+	}
+}
+
+// GoroutineDumpToFile returns a sink writing every dump to
+// a new "goroutines-<timestamp>.txt" file in dir.
+func GoroutineDumpToFile(dir string) GoroutineDumpSink {
+	return func(dump []byte) error {
+		name := fmt.Sprintf("goroutines-%s.txt", time.Now().UTC().Format("20060102T150405.000000000"))
+		return os.WriteFile(filepath.Join(dir, name), dump, 0o644)
+	}
+}
+
+// GoroutineDumpToWriter returns a sink writing every dump to w.
+func GoroutineDumpToWriter(w io.Writer) GoroutineDumpSink {
+	return func(dump []byte) error {
+		_, err := w.Write(dump)
+		return err
+	}
+}
+
+// GoroutineDumpToLogger returns a sink logging every dump as an error
+// with the "goroutines" attribute.
+func GoroutineDumpToLogger(logger *slog.Logger) GoroutineDumpSink {
+	return func(dump []byte) error {
+		logger.Error("liveness check failed, goroutine dump captured", slog.String("goroutines", string(dump)))
+		return nil
+	}
+}
+
+// livenessEvaluated tracks liveness transitions and captures a goroutine dump
+// when liveness starts failing.
+func (s *basicHandler) livenessEvaluated(status int) {
+	if s.goroutineDumpSink == nil {
+		return
+	}
+
+	s.livenessMutex.Lock()
+	defer s.livenessMutex.Unlock()
+
+	failing := status != http.StatusOK
+	if !failing || s.livenessFailing {
+		s.livenessFailing = failing
+		return
+	}
+	s.livenessFailing = true
+
+	var buf bytes.Buffer
+	// debug=2 prints the stacks in the same format as an unrecovered panic
+	err := pprof.Lookup("goroutine").WriteTo(&buf, 2)
+	if err == nil {
+		err = s.goroutineDumpSink(buf.Bytes())
+	}
+	if err != nil && s.errorHandler != nil {
+		safeCall(func() { s.errorHandler(goroutineDumpErrorName, err) })
+	}
+}
@@ -0,0 +1,74 @@
+package healthcheck
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGoroutineDump(t *testing.T) {
+	var (
+		dumps   int
+		failing atomic.Bool
+	)
+
+	h := NewHandler(WithGoroutineDump(func(dump []byte) error {
+		dumps++
+		if !bytes.Contains(dump, []byte("goroutine")) {
+			t.Errorf("Dump doesn't look like a goroutine profile:\n%s", dump)
+		}
+		return nil
+	}))
+	h.AddLivenessCheck("live", func() error {
+		if failing.Load() {
+			return errors.New("failed liveness check")
+		}
+		return nil
+	})
+
+	probe := func() {
+		req, err := http.NewRequest(http.MethodGet, LivenessHandlerPath, nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	steps := []struct {
+		failing bool
+		dumps   int
+	}{
+		{failing: false, dumps: 0},
+		{failing: true, dumps: 1},
+		{failing: true, dumps: 1},
+		{failing: false, dumps: 1},
+		{failing: true, dumps: 2},
+	}
+
+	for i, step := range steps {
+		failing.Store(step.failing)
+		probe()
+
+		if dumps != step.dumps {
+			t.Errorf("Wrong number of dumps after step %d\n"+
+				"expected: %v\n"+
+				"actual  : %v", i, step.dumps, dumps)
+		}
+	}
+}
+
+func TestGoroutineDumpErrorHandlerPanic(t *testing.T) {
+	h := NewHandler(WithGoroutineDump(func([]byte) error { return errors.New("disk full") }))
+	h.AddCheckErrorHandler(func(string, error) { panic("error handler panicked") })
+	h.AddLivenessCheck("live", func() error { return errors.New("failed liveness check") })
+
+	// the panic of the error handler doesn't escape the probe
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, LivenessHandlerPath, nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wrong status code\n"+"expected: %v\n"+"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package healthcheck
+
+import "net/http"
+
+const (
+	// EnvoyFailHandlerPath path failing readiness until EnvoyOKHandlerPath is called.
+	EnvoyFailHandlerPath = "/healthcheck/fail"
+	// EnvoyOKHandlerPath path reverting EnvoyFailHandlerPath.
+	EnvoyOKHandlerPath = "/healthcheck/ok"
+)
+
+// WithEnvoyEndpoints registers endpoints mirroring the Envoy admin semantics,
+// so drain tooling speaking this protocol can be used as is:
+//
+//	POST /healthcheck/fail  fail readiness regardless of the checks
+//	POST /healthcheck/ok    revert /healthcheck/fail
+//
+// Both are the same persistent override as OverrideReadiness(false) and
+// ResetReadinessOverride, and are protected by WithAdminAuth: without it,
+// every request is rejected with 403 Forbidden.
+func WithEnvoyEndpoints() Option {
+	return func(h *basicHandler) {
+		h.envoyEndpoints = true
+	}
+}
+
+// registerEnvoyEndpoints registers the Envoy endpoints on the mux if they're enabled.
+func (s *basicHandler) registerEnvoyEndpoints() {
+	if !s.envoyEndpoints {
+		return
+	}
+
+	s.route("POST "+EnvoyFailHandlerPath,
+		adminOperation(textOperation("Fail the Envoy health checks", "text/plain")),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.OverrideReadiness(false)
+			_, _ = w.Write([]byte("OK\n"))
+		}))
+	s.route("POST "+EnvoyOKHandlerPath,
+		adminOperation(textOperation("Pass the Envoy health checks", "text/plain")),
+		s.adminOnly(func(w http.ResponseWriter, _ *http.Request) {
+			s.ResetReadinessOverride()
+			_, _ = w.Write([]byte("OK\n"))
+		}))
+}
@@ -0,0 +1,41 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvoyEndpoints(t *testing.T) {
+	h := NewHandler(WithEnvoyEndpoints(), WithAdminAuth(AdminAllowAll))
+	h.AddReadinessCheck("ready", func() error { return nil })
+
+	steps := []struct {
+		method string
+		path   string
+		expect int
+	}{
+		{method: http.MethodGet, path: ReadinessHandlerPath, expect: http.StatusOK},
+		{method: http.MethodGet, path: EnvoyFailHandlerPath, expect: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: EnvoyFailHandlerPath, expect: http.StatusOK},
+		{method: http.MethodGet, path: ReadinessHandlerPath, expect: http.StatusServiceUnavailable},
+		{method: http.MethodGet, path: LivenessHandlerPath, expect: http.StatusOK},
+		{method: http.MethodPost, path: EnvoyOKHandlerPath, expect: http.StatusOK},
+		{method: http.MethodGet, path: ReadinessHandlerPath, expect: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		req, err := http.NewRequest(step.method, step.path, nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != step.expect {
+			t.Errorf("Wrong code for %s %s\n"+
+				"expected: %v\n"+
+				"actual  : %v", step.method, step.path, step.expect, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// escalationCheckName is the name of the result added to the liveness
+// probe when it fails because of WithReadinessEscalation.
+const escalationCheckName = "readiness_escalation"
+
+// ErrReadinessEscalated is the error failing the liveness probe when
+// readiness has been failing for longer than set by WithReadinessEscalation.
+var ErrReadinessEscalated = errors.New("readiness failing for too long")
+
+// WithReadinessEscalation fails the liveness probe once the readiness probe has
+// been failing continuously for longer than after, so a wedged instance gets
+// restarted instead of staying unready forever. The liveness failure is reported
+// as the "readiness_escalation" result. Readiness is tracked on its evaluations,
+// so it must be probed more often than after.
+func WithReadinessEscalation(after time.Duration) Option {
+	return func(h *basicHandler) {
+		h.escalation = &escalation{after: after}
+	}
+}
+
+// escalation tracks how long the readiness probe has been failing.
+type escalation struct {
+	after time.Duration
+
+	mu      sync.Mutex
+	failing bool
+	since   time.Time
+}
+
+// observe records the outcome of an evaluation of the readiness probe.
+func (e *escalation) observe(state probeState, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	failing := state == probeFail
+	if failing && !e.failing {
+		e.since = now
+	}
+	e.failing = failing
+}
+
+// exceeded returns an error if readiness has been failing for too long at now.
+func (e *escalation) exceeded(now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.failing {
+		return nil
+	}
+	if failing := now.Sub(e.since); failing > e.after {
+		return fmt.Errorf("%w: failing for %v (max %v)", ErrReadinessEscalated, failing.Round(time.Second), e.after)
+	}
+	return nil
+}
+
+// escalate fails the liveness probe if readiness has been failing for too long.
+func (s *basicHandler) escalate(results map[string]Result, state probeState) probeState {
+	if err := s.escalation.exceeded(s.clock.Now()); err != nil {
+		results[escalationCheckName] = Result{Err: err}
+		return probeFail
+	}
+	return state
+}
@@ -0,0 +1,59 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithReadinessEscalation(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock), WithReadinessEscalation(5*time.Minute))
+
+	var failing atomic.Bool
+	h.AddReadinessCheck("database", func() error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	h.AddLivenessCheck("ping", func() error { return nil })
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		failing bool
+		code    int
+		body    string
+	}{
+		{name: "healthy", code: http.StatusOK, body: `{"ping":"OK"}`},
+		{name: "readiness failing", failing: true, code: http.StatusOK, body: `{"ping":"OK"}`},
+		{name: "within the limit", advance: 5 * time.Minute, failing: true, code: http.StatusOK, body: `{"ping":"OK"}`},
+		{
+			name:    "escalated",
+			advance: time.Minute,
+			failing: true,
+			code:    http.StatusServiceUnavailable,
+			body:    `{"ping":"OK","readiness_escalation":"readiness failing for too long: failing for 6m0s (max 5m0s)"}`,
+		},
+		{name: "readiness recovered", advance: time.Minute, code: http.StatusOK, body: `{"ping":"OK"}`},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		failing.Store(step.failing)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, LivenessHandlerPath+"?full=1&compact=1", nil))
+
+		if rr.Code != step.code {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.code, rr.Code)
+		}
+		if body := rr.Body.String(); body != step.body+"\n" {
+			t.Errorf("Wrong body for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.body, body)
+		}
+	}
+}
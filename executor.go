@@ -0,0 +1,70 @@
+package healthcheck
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// minWorkers is the minimum default number of workers, checks are mostly
+// waiting for I/O so they shouldn't be limited to the number of CPUs.
+const minWorkers = 16
+
+// WithWorkers sets the number of long-lived workers executing the checks,
+// max(16, GOMAXPROCS) by default. The evaluations queue up while all
+// the workers are busy, which bounds the number of concurrently executed
+// checks when many probes arrive while checks are slow.
+func WithWorkers(n int) Option {
+	return func(h *basicHandler) {
+		h.executor.workers = n
+	}
+}
+
+// executor executes tasks on long-lived workers fed by a queue instead of
+// spawning goroutines for every evaluation. The workers are started on first
+// use and stop with the handler context, after which the tasks are executed
+// on their own goroutines.
+type executor struct {
+	once    sync.Once
+	workers int
+	tasks   chan func()
+	done    <-chan struct{}
+}
+
+func (e *executor) start(ctx context.Context) {
+	e.once.Do(func() {
+		if e.workers <= 0 {
+			e.workers = max(minWorkers, runtime.GOMAXPROCS(0))
+		}
+		// unbuffered: a task is only handed over to an idle worker,
+		// the blocked submitters are the queue
+		e.tasks = make(chan func())
+		e.done = ctx.Done()
+
+		for i := 0; i < e.workers; i++ {
+			go e.work()
+		}
+	})
+}
+
+func (e *executor) work() {
+	for {
+		select {
+		case task := <-e.tasks:
+			task()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// submit blocks until the task is handed over to a worker.
+func (e *executor) submit(ctx context.Context, task func()) {
+	e.start(ctx)
+
+	select {
+	case e.tasks <- task:
+	case <-e.done:
+		go task()
+	}
+}
@@ -0,0 +1,69 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutorBoundsConcurrency(t *testing.T) {
+	const workers = 2
+
+	var (
+		running, peak atomic.Int32
+		mu            sync.Mutex
+	)
+	h := NewHandler(WithWorkers(workers))
+	for i := 0; i < 10; i++ {
+		h.AddReadinessCheck(fmt.Sprintf("check-%d", i), func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			mu.Lock()
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			if rr.Code != http.StatusOK {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := peak.Load(); n > workers {
+		t.Errorf("Too many concurrent checks\n"+"expected: %v\n"+"actual  : %v", workers, n)
+	}
+}
+
+func TestExecutorDependencies(t *testing.T) {
+	// a single worker must not deadlock waiting for a dependency
+	h := NewHandler(WithWorkers(1))
+	h.AddReadinessCheck("schema", func() error { return nil }, DependsOn("migrations"))
+	h.AddReadinessCheck("migrations", func() error { return nil }, DependsOn("ping"))
+	h.AddReadinessCheck("ping", func() error { return nil })
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output formats of the probes, selected by the Accept header or the format query parameter.
+const (
+	formatJSON        = "json"
+	formatHealthJSON  = "health+json"
+	formatText        = "text"
+	formatOpenMetrics = "openmetrics"
+	formatHTML        = "html"
+)
+
+// formatMediaTypes are the media types of the output formats, in order of
+// preference when the Accept header weighs several of them equally.
+var formatMediaTypes = []struct {
+	format    string
+	mediaType string
+}{
+	{formatJSON, "application/json"},
+	{formatHealthJSON, "application/health+json"},
+	{formatText, "text/plain"},
+	{formatOpenMetrics, "application/openmetrics-text"},
+	{formatHTML, "text/html"},
+}
+
+// negotiateFormat returns the output format of the response: the one set by
+// ?format= if any, otherwise the one preferred by the Accept header. JSON is
+// served when nothing is requested or nothing requested is supported, so a probe
+// never fails because of its headers. ok is false for an unknown ?format=.
+func negotiateFormat(r *http.Request) (format string, ok bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		for _, f := range formatMediaTypes {
+			if f.format == format {
+				return format, true
+			}
+		}
+		return "", false
+	}
+
+	best, bestQ := formatJSON, 0.0
+	for _, f := range formatMediaTypes {
+		if q := acceptQuality(r.Header.Values("Accept"), f.mediaType); q > bestQ {
+			best, bestQ = f.format, q
+		}
+	}
+	return best, true
+}
+
+// acceptQuality returns the quality the Accept headers give to the media type,
+// taking the most specific matching range, or 0 if it's not acceptable.
+func acceptQuality(accept []string, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			accepted, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			var s int
+			switch accepted {
+			case mediaType:
+				s = 2
+			case typ + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			default:
+				continue
+			}
+			if s <= specificity {
+				continue
+			}
+
+			specificity, q = s, 1
+			if value, ok := params["q"]; ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+	}
+	return q
+}
+
+// writeHealthJSON writes the results in the application/health+json format,
+// with the checks only if the full output is requested.
+func (s *basicHandler) writeHealthJSON(
+	w http.ResponseWriter, r *http.Request, status int, results map[string]Result, elapsed time.Duration,
+) {
+	health := HealthJSON{Status: StatusPass}
+	if status >= http.StatusBadRequest {
+		health.Status = StatusFail
+	}
+
+	var failed []string
+	for name, res := range results {
+		if checkFailed(res.Err) {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	if len(failed) > 0 {
+		health.Output = "failed checks: " + strings.Join(failed, ", ")
+		if health.Status == StatusPass {
+			health.Status = StatusWarn
+		}
+	}
+
+	if s.fullOutput(r) {
+		health.Checks = make(map[string][]HealthJSONCheck, len(results))
+		now := s.clock.Now()
+		for name, res := range results {
+			check := HealthJSONCheck{Status: StatusPass, Time: now}
+			if checkFailed(res.Err) {
+				check.Status, check.Output = StatusFail, res.Err.Error()
+			}
+			if metrics := res.Metrics(); len(metrics) == 1 {
+				for key, value := range metrics {
+					check.ObservedValue = value
+					if observed, ok := res.Details[key].(observation); ok {
+						check.ObservedUnit = observed.unit()
+					}
+				}
+			}
+			health.Checks[name] = []HealthJSONCheck{check}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/health+json")
+	w.Header().Set(DurationHeader, strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(health)
+}
+
+// writeProbeMetrics writes the results of a probe in the OpenMetrics text format.
+func (s *basicHandler) writeProbeMetrics(
+	w http.ResponseWriter, probe string, status int, results map[string]Result, elapsed time.Duration,
+) {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	writeMetricFamily(&b, "healthcheck_check_up", "Whether the check passes (1) or fails (0).")
+	for _, name := range names {
+		fmt.Fprintf(&b, "healthcheck_check_up{check=\"%s\",probe=\"%s\"} %d\n",
+			escapeLabel(name), probe, boolGauge(!checkFailed(results[name].Err)))
+	}
+	writeMetricFamily(&b, "healthcheck_probe_up", "Whether the probe passes (1) or fails (0).")
+	fmt.Fprintf(&b, "healthcheck_probe_up{probe=\"%s\"} %d\n", probe, boolGauge(status < http.StatusBadRequest))
+	b.WriteString("# EOF\n")
+
+	w.Header().Set("Content-Type", openMetricsContentType)
+	w.Header().Set(DurationHeader, strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(b.String()))
+}
@@ -0,0 +1,152 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		format string
+		ok     bool
+	}{
+		{name: "no accept", target: "/", format: formatJSON, ok: true},
+		{name: "any", target: "/", accept: "*/*", format: formatJSON, ok: true},
+		{name: "health json", target: "/", accept: "application/health+json, application/json;q=0.9", format: formatHealthJSON, ok: true},
+		{name: "quality", target: "/", accept: "text/html;q=0.5, text/plain", format: formatText, ok: true},
+		{name: "type range", target: "/", accept: "text/*", format: formatText, ok: true},
+		{name: "specific range wins", target: "/", accept: "text/*;q=0.1, text/html", format: formatHTML, ok: true},
+		{name: "openmetrics", target: "/", accept: "application/openmetrics-text; version=1.0.0", format: formatOpenMetrics, ok: true},
+		{name: "unsupported", target: "/", accept: "image/png", format: formatJSON, ok: true},
+		{name: "override", target: "/?format=html", accept: "application/json", format: formatHTML, ok: true},
+		{name: "unknown override", target: "/?format=xml", ok: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			format, ok := negotiateFormat(r)
+			if format != tt.format || ok != tt.ok {
+				t.Errorf("Wrong format\n"+"expected: %v %v\n"+"actual  : %v %v", tt.format, tt.ok, format, ok)
+			}
+		})
+	}
+}
+
+func TestHandlerFormats(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler()
+	h.AddReadinessCheck("cache", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("refused") })
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{
+			name:        "json",
+			target:      ReadinessHandlerPath + "?full=1",
+			status:      http.StatusServiceUnavailable,
+			contentType: "application/json; charset=utf-8",
+			contains:    `"database": "refused"`,
+		},
+		{
+			name:        "text",
+			target:      ReadinessHandlerPath,
+			accept:      "text/plain",
+			status:      http.StatusServiceUnavailable,
+			contentType: "text/plain; charset=utf-8",
+			contains:    "readiness: fail",
+		},
+		{
+			name:        "openmetrics",
+			target:      ReadinessHandlerPath,
+			accept:      "application/openmetrics-text",
+			status:      http.StatusServiceUnavailable,
+			contentType: openMetricsContentType,
+			contains:    `healthcheck_check_up{check="database",probe="readiness"} 0`,
+		},
+		{
+			name:   "unknown format",
+			target: ReadinessHandlerPath + "?format=xml",
+			status: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			if rr.Code != tt.status {
+				t.Errorf("Wrong status code\n"+"expected: %v\n"+"actual  : %v", tt.status, rr.Code)
+			}
+			if tt.contentType != "" && rr.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Wrong content type\n"+"expected: %v\n"+"actual  : %v", tt.contentType, rr.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(rr.Body.String(), tt.contains) {
+				t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", tt.contains, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerHealthJSON(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler()
+	h.AddReadinessCheck("cache", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("refused") })
+
+	r := httptest.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1", nil)
+	r.Header.Set("Accept", "application/health+json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	var health HealthJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &health); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if health.Status != StatusFail || health.Output != "failed checks: database" {
+		t.Errorf("Wrong status\n"+"expected: %v %v\n"+"actual  : %v %v", StatusFail, "failed checks: database", health.Status, health.Output)
+	}
+	if actual := health.Checks["database"][0]; actual.Status != StatusFail || actual.Output != "refused" {
+		t.Errorf("Wrong database check\n"+"expected: %v %v\n"+"actual  : %v %v", StatusFail, "refused", actual.Status, actual.Output)
+	}
+	if actual := health.Checks["cache"][0].Status; actual != StatusPass {
+		t.Errorf("Wrong cache check\n"+"expected: %v\n"+"actual  : %v", StatusPass, actual)
+	}
+	if vary := rr.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("Wrong Vary header\n"+"expected: %v\n"+"actual  : %v", "Accept", vary)
+	}
+}
@@ -0,0 +1,131 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// GraphHandlerPath path to the check dependency graph.
+const GraphHandlerPath = "/health/graph"
+
+// WithTags attaches tags to the check (e.g. "db", "external"),
+// they're exposed along with the check in the dependency graph.
+func WithTags(tags ...string) CheckOption {
+	return func(c *checkConfig) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// Graph is the declared check dependency graph.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a check in the dependency graph.
+type GraphNode struct {
+	Name     string   `json:"name"`
+	Probes   []string `json:"probes"`
+	Tags     []string `json:"tags,omitempty"`
+	Critical bool     `json:"critical"`
+}
+
+// GraphEdge is a dependency of the From check on the To check.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// graphOperation describes the graph endpoint in the OpenAPI document.
+func graphOperation() openAPIOperation {
+	op := jsonOperation("Check dependency graph", "Graph")
+	op.Responses["200"].Content["text/vnd.graphviz"] = openAPIMediaType{Schema: openAPISchema{"type": "string"}}
+	op.Parameters = append(op.Parameters, openAPIParameter{
+		Name:        "format",
+		In:          "query",
+		Description: "Set to dot to get the graph in the Graphviz DOT format.",
+		Schema:      openAPISchema{"type": "string", "enum": []string{"dot"}},
+	})
+	return op
+}
+
+// graph builds the dependency graph of the registered checks.
+func (s *basicHandler) graph() Graph {
+	s.checksMutex.RLock()
+	defer s.checksMutex.RUnlock()
+
+	var (
+		g     = Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+		nodes = make(map[string]int)
+	)
+	for _, probe := range []struct {
+		name   string
+		checks map[string]*checkEntry
+	}{
+		{name: "liveness", checks: s.livenessChecks},
+		{name: "readiness", checks: s.readinessChecks},
+	} {
+		for name, entry := range probe.checks {
+			if i, ok := nodes[name]; ok {
+				g.Nodes[i].Probes = append(g.Nodes[i].Probes, probe.name)
+				continue
+			}
+
+			nodes[name] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, GraphNode{
+				Name:     name,
+				Probes:   []string{probe.name},
+				Tags:     entry.config.tags,
+				Critical: entry.config.critical(),
+			})
+			for _, dep := range entry.config.dependencies {
+				g.Edges = append(g.Edges, GraphEdge{From: name, To: dep})
+			}
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Name < g.Nodes[j].Name })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	return g
+}
+
+// GraphEndpoint is an HTTP handler exposing the check dependency graph as JSON,
+// or in the Graphviz DOT format with ?format=dot.
+func (s *basicHandler) GraphEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) {
+		return
+	}
+
+	g := s.graph()
+
+	if r.URL.Query().Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_, _ = w.Write([]byte(g.DOT()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, g)
+}
+
+// DOT renders the graph in the Graphviz DOT format.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph healthcheck {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "    %q [probes=%q, tags=%q, critical=%t];\n",
+			node.Name, strings.Join(node.Probes, ","), strings.Join(node.Tags, ","), node.Critical)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "    %q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGraphEndpoint(t *testing.T) {
+	h := NewHandler()
+	h.AddLivenessCheck("db_ping", func() error { return nil }, WithTags("db"))
+	h.AddReadinessCheck("db_schema", func() error { return nil }, WithTags("db"), DependsOn("db_ping"))
+
+	tests := []struct {
+		name       string
+		path       string
+		expectBody string
+	}{
+		{
+			name: "json",
+			path: GraphHandlerPath,
+			expectBody: `{
+    "nodes": [
+        {
+            "name": "db_ping",
+            "probes": [
+                "liveness"
+            ],
+            "tags": [
+                "db"
+            ],
+            "critical": true
+        },
+        {
+            "name": "db_schema",
+            "probes": [
+                "readiness"
+            ],
+            "tags": [
+                "db"
+            ],
+            "critical": true
+        }
+    ],
+    "edges": [
+        {
+            "from": "db_schema",
+            "to": "db_ping"
+        }
+    ]
+}
+`,
+		},
+		{
+			name: "dot",
+			path: GraphHandlerPath + "?format=dot",
+			expectBody: `digraph healthcheck {
+    "db_ping" [probes="liveness", tags="db", critical=true];
+    "db_schema" [probes="readiness", tags="db", critical=true];
+    "db_schema" -> "db_ping";
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Errorf("Wrong code\n"+
+					"expected: %v\n"+
+					"actual  : %v", http.StatusOK, rr.Code)
+			}
+			if rr.Body.String() != tt.expectBody {
+				t.Errorf("Wrong body\n"+
+					"expected: %v"+
+					"actual  : %v", tt.expectBody, rr.Body.String())
+			}
+		})
+	}
+}
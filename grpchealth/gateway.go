@@ -0,0 +1,58 @@
+package grpchealth
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// GatewayPath is the REST path of the Check method for the empty service name.
+	GatewayPath = "/v1/health"
+	// GatewayServicePath is the REST path of the Check method for a named service.
+	GatewayServicePath = "/v1/health/{service}"
+)
+
+// RegisterGatewayHandler registers the REST mapping of the Check method on the
+// grpc-gateway mux, calling the server directly (without a gRPC round trip).
+// The upstream health proto has no HTTP annotations, the mapping is the one
+// they would declare:
+//
+//	rpc Check(HealthCheckRequest) returns (HealthCheckResponse) {
+//	  option (google.api.http) = {
+//	    get: "/v1/health"
+//	    additional_bindings { get: "/v1/health/{service}" }
+//	  };
+//	}
+//
+// The response is {"status": "SERVING"}, NOT_SERVING is served with
+// 503 Service Unavailable so it can be used as an HTTP probe as is.
+func RegisterGatewayHandler(mux *runtime.ServeMux, server healthpb.HealthServer) error {
+	handler := func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		resp, err := server.Check(r.Context(), &healthpb.HealthCheckRequest{Service: params["service"]})
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		data, err := outbound.Marshal(resp)
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", outbound.ContentType(resp))
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write(data)
+	}
+
+	if err := mux.HandlePath(http.MethodGet, GatewayPath, handler); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, GatewayServicePath, handler)
+}
@@ -0,0 +1,61 @@
+package grpchealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestGateway(t *testing.T) {
+	h := healthcheck.NewHandler()
+	h.AddLivenessCheck("live", func() error { return nil })
+	h.AddReadinessCheck("database", func() error { return errors.New("failed") })
+
+	mux := runtime.NewServeMux()
+	if err := RegisterGatewayHandler(mux, NewServer(h)); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		expect     int
+		expectBody string
+	}{
+		{
+			name:       "overall readiness",
+			target:     "/v1/health",
+			expect:     http.StatusServiceUnavailable,
+			expectBody: `{"status":"NOT_SERVING"}`,
+		},
+		{
+			name:       "passing check",
+			target:     "/v1/health/live",
+			expect:     http.StatusOK,
+			expectBody: `{"status":"SERVING"}`,
+		},
+		{
+			name:   "unknown check",
+			target: "/v1/health/unknown",
+			expect: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, rr.Code)
+			}
+			if tt.expectBody != "" && rr.Body.String() != tt.expectBody {
+				t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", tt.expectBody, rr.Body.String())
+			}
+		})
+	}
+}
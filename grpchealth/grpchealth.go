@@ -0,0 +1,110 @@
+// Package grpchealth implements the gRPC health checking protocol
+// (grpc.health.v1.Health) for grpc-go servers, backed by a healthcheck.Handler,
+// along with its grpc-gateway REST mapping.
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/healthsvc"
+)
+
+// DefaultWatchInterval is the default interval at which Watch re-evaluates the status.
+const DefaultWatchInterval = 5 * time.Second
+
+// Option configures a Server.
+type Option func(s *Server)
+
+// WithWatchInterval sets the interval at which Watch re-evaluates the status,
+// DefaultWatchInterval by default.
+func WithWatchInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.watchInterval = interval
+	}
+}
+
+// Server is the health service. The status of the empty service name is the
+// readiness of the handler, the status of any other service name is the result
+// of the check with this name; unknown names are reported as NotFound.
+//
+//	healthpb.RegisterHealthServer(grpcServer, grpchealth.NewServer(handler))
+type Server struct {
+	healthpb.UnimplementedHealthServer
+
+	handler       healthcheck.Handler
+	watchInterval time.Duration
+}
+
+// NewServer creates the health service of the handler.
+func NewServer(h healthcheck.Handler, opts ...Option) *Server {
+	s := &Server{
+		handler:       h,
+		watchInterval: DefaultWatchInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check returns the serving status of the service.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, err := s.status(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch streams the serving status of the service on every change.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(healthsvc.Unknown)
+	for {
+		st, err := s.status(ctx, req.GetService())
+		if status.Code(err) == codes.NotFound {
+			// unknown services are watched until they're known, as required by the protocol
+			st, err = healthpb.HealthCheckResponse_SERVICE_UNKNOWN, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// status evaluates the status of the service, with the gRPC error codes.
+func (s *Server) status(ctx context.Context, name string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	serving, err := healthsvc.Status(ctx, s.handler, name)
+	st := healthpb.HealthCheckResponse_ServingStatus(serving)
+	switch {
+	case errors.Is(err, healthcheck.ErrCheckNotFound):
+		return st, status.Errorf(codes.NotFound, "unknown service %q", name)
+	case err != nil:
+		return st, status.FromContextError(err).Err()
+	}
+	return st, nil
+}
@@ -1,10 +1,14 @@
 package healthcheck
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	htmltemplate "html/template"
 	"net/http"
+	"strconv"
 	"sync"
+	texttemplate "text/template"
+	"time"
 )
 
 const (
@@ -12,28 +16,42 @@ const (
 	LivenessHandlerPath = "/live"
 	// ReadinessHandlerPath path to process readiness probe.
 	ReadinessHandlerPath = "/ready"
+	// DurationHeader header of the probe responses holding the time it took
+	// to evaluate the probe in seconds, to tune the probe timeout on.
+	DurationHeader = "X-Healthcheck-Duration"
 
 	successCheckerResultString = "OK"
 )
 
-// Handler is a wrapper over http.Handler,
-// allowing you to add liveness and readiness checks
-type Handler interface {
-	// Handler is http.Handler, so it can be exposed directly and processed
-	// /live and /ready endpoints.
-	http.Handler
-
+// CheckRegistrar registers checks, it's the part of Handler the libraries
+// contributing their own checks depend on.
+type CheckRegistrar interface {
 	// AddLivenessCheck adds a check indicating that this instance
 	// of the application should be destroyed or restarted. A failed liveness check
 	// indicates that this instance is not running.
-	// Each liveness check is also included as a readiness check.
-	AddLivenessCheck(name string, check Check)
+	// Each liveness check is also included as a readiness check,
+	// unless the handler is created with WithIndependentProbes.
+	AddLivenessCheck(name string, check Check, opts ...CheckOption)
 
 	// AddReadinessCheck adds a check indicating that this
 	// application instance is currently unable to serve requests due to an external
 	// dependency or some kind of temporary failure. If the readiness check fails, this instance
 	// should no longer receive requests, but it should not be restarted or destroyed.
-	AddReadinessCheck(name string, check Check)
+	AddReadinessCheck(name string, check Check, opts ...CheckOption)
+
+	// AddLivenessChecker is AddLivenessCheck for a Checker.
+	AddLivenessChecker(checker Checker, opts ...CheckOption)
+
+	// AddReadinessChecker is AddReadinessCheck for a Checker.
+	AddReadinessChecker(checker Checker, opts ...CheckOption)
+}
+
+// Prober evaluates the probes, it's the part of Handler the code
+// exposing or reporting the health of the application depends on.
+type Prober interface {
+	// Prober is http.Handler, so it can be exposed directly and processed
+	// /live and /ready endpoints.
+	http.Handler
 
 	// LiveEndpoint is an HTTP handler for the /live endpoint only, which
 	// is useful if you need to add it to your own HTTP handler tree.
@@ -43,8 +61,58 @@ type Handler interface {
 	// is useful if you need to add it to your own HTTP handler tree.
 	ReadyEndpoint(http.ResponseWriter, *http.Request)
 
+	// Check evaluates the readiness probe (readiness and liveness checks) without
+	// an HTTP round trip, e.g. for shutdown logic or admission of new work.
+	// It returns ctx.Err() if ctx is done before the evaluation completes.
+	Check(ctx context.Context) (Status, map[string]Result, error)
+
+	// CheckLiveness is Check for the liveness probe.
+	CheckLiveness(ctx context.Context) (Status, map[string]Result, error)
+
+	// RunCheck executes the named check now, regardless of its schedule and
+	// dependencies, and returns its result. It returns ErrCheckNotFound if there's
+	// no check with the given name and ctx.Err() if ctx is done before it completes.
+	RunCheck(ctx context.Context, name string) (Result, error)
+}
+
+// Handler is a wrapper over http.Handler,
+// allowing you to add liveness and readiness checks
+type Handler interface {
+	CheckRegistrar
+	Prober
+
 	// AddCheckErrorHandler adds a callback to process a failed check (in order to log errors, etc.).
 	AddCheckErrorHandler(handler ErrorHandler)
+
+	// AddCheckResultHandler adds a callback that is notified about every executed check,
+	// failed or not (in order to export metrics, etc.). Several handlers can be added.
+	AddCheckResultHandler(handler ResultHandler)
+
+	// EnableCheck enables back a check disabled by DisableCheck, or by
+	// WithPanicLimit: the panic count of the check is reset.
+	// It returns ErrCheckNotFound if there's no check with the given name.
+	EnableCheck(name string) error
+
+	// DisableCheck temporarily disables a check (both liveness and readiness):
+	// it isn't executed anymore, doesn't affect the probes and is reported
+	// as "disabled" until it's enabled back.
+	// It returns ErrCheckNotFound if there's no check with the given name.
+	DisableCheck(name string) error
+
+	// OverrideReadiness forces the readiness probe to pass (ready is true) or to fail
+	// regardless of the checks, e.g. to drain the instance before shutdown.
+	OverrideReadiness(ready bool)
+
+	// ResetReadinessOverride removes the override set by OverrideReadiness.
+	ResetReadinessOverride()
+
+	// RegisterRoutes registers the health endpoints on r, so the handler can be
+	// composed with a router (chi, gorilla/mux...) instead of being exposed as is.
+	RegisterRoutes(r Router)
+
+	// Routes returns the endpoints of the handler, for the code
+	// which needs the raw endpoint funcs.
+	Routes() []Route
 }
 
 // Check signature of check proccess function
@@ -53,140 +121,564 @@ type Check func() error
 // ErrorHandler error handler's signature for failed checks.
 type ErrorHandler func(name string, err error)
 
+// Result is the outcome of a single check execution.
+type Result struct {
+	// Err is the error returned by the check, nil if the check passed.
+	Err error
+	// Duration is the time it took to execute the check.
+	Duration time.Duration
+	// Details are the values observed by a Checker (latency, version, free space...),
+	// they're rendered in the full output and numeric ones in the metrics.
+	Details map[string]any
+	// Code is the machine-readable code of the failure, set from a CodedError
+	// or by WithErrorCode. It's empty if the check passed.
+	Code string
+	// Labels are the static labels of the check set by WithLabels.
+	Labels map[string]string
+	// Stale is true if the result was restored by WithStatePersistence
+	// and the check hasn't been executed yet since startup.
+	Stale bool
+}
+
+// ResultHandler result handler's signature for executed checks.
+type ResultHandler func(name string, result Result)
+
 // NewHandler creates a new basic Handler
-func NewHandler() Handler {
+func NewHandler(opts ...Option) Handler {
 	h := &basicHandler{
-		livenessChecks:  make(map[string]Check),
-		readinessChecks: make(map[string]Check),
+		mux:             http.NewServeMux(),
+		ctx:             context.Background(),
+		clock:           SystemClock,
+		successString:   successCheckerResultString,
+		degradedStatus:  http.StatusOK,
+		livenessChecks:  make(map[string]*checkEntry),
+		readinessChecks: make(map[string]*checkEntry),
+		disabledChecks:  make(map[string]bool),
+		panics:          make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.readinessProbe = []map[string]*checkEntry{h.readinessChecks}
+	if !h.independentProbes {
+		h.readinessProbe = append(h.readinessProbe, h.livenessChecks)
+	}
+	h.loadState()
+	h.route("/live", probeOperation("Liveness probe"), h.LiveEndpoint)
+	h.route("/ready", probeOperation("Readiness probe"), h.ReadyEndpoint)
+	h.route(GraphHandlerPath, graphOperation(), h.GraphEndpoint)
+	h.route(ScoreHandlerPath, jsonOperation("Weighted health score", "Score", http.StatusServiceUnavailable), h.ScoreEndpoint)
+	h.route(StatsHandlerPath, jsonOperation("Availability and history of the checks", "Stats"), h.StatsEndpoint)
+	h.route(CallersHandlerPath, jsonOperation("Number of probe requests by caller", "Callers"), h.CallersEndpoint)
+	h.route(DiffHandlerPath, diffOperation(), h.DiffEndpoint)
+	h.route(SchemaHandlerPath, jsonOperation("JSON Schemas of the response formats", "Schemas"), h.SchemaEndpoint)
+	h.registerAdminEndpoints()
+	h.registerEnvoyEndpoints()
+	h.registerLoadBalancerPaths()
+	h.registerOpenMetrics()
+
+	if h.serveMuxMethods {
+		return serveMuxHandler{h}
 	}
-	h.Handle("/live", http.HandlerFunc(h.LiveEndpoint))
-	h.Handle("/ready", http.HandlerFunc(h.ReadyEndpoint))
 	return h
 }
 
 // basicHandler implementation of Handler.
 type basicHandler struct {
-	http.ServeMux
+	mux             *http.ServeMux
+	ctx             context.Context
+	clock           Clock
 	checksMutex     sync.RWMutex
-	livenessChecks  map[string]Check
-	readinessChecks map[string]Check
-	errorHandler    ErrorHandler
+	livenessChecks  map[string]*checkEntry
+	readinessChecks map[string]*checkEntry
+	// readinessProbe are the checks evaluated by the readiness probe:
+	// the readiness checks, followed by the liveness ones by default.
+	readinessProbe    []map[string]*checkEntry
+	independentProbes bool
+	disabledChecks    map[string]bool
+	errorHandler      ErrorHandler
+	resultHandlers    []ResultHandler
+
+	adminEndpoints bool
+	adminAuth      AdminAuthFunc
+	envoyEndpoints bool
+
+	loadBalancerProbes bool
+	loadBalancerPaths  []string
+	readinessOverride  readinessOverride
+	scoreThreshold     float64
+	partialReadiness   partialReadiness
+	degradedStatus     int
+	retryOnFailure     bool
+	retryDelay         time.Duration
+	refreshParameter   bool
+	refreshAuth        AdminAuthFunc
+	openMetricsPath    string
+	state              *persistedState
+	statsStore         StatsStore
+	chaosRules         map[string]ChaosRule
+	executor           executor
+	routes             []Route
+	successString      string
+	messageMapper      MessageMapper
+	serveMuxMethods    bool
+
+	panicLimit  int
+	panicsMutex sync.Mutex
+	panics      map[string]int
+
+	goroutineDumpSink GoroutineDumpSink
+	probeAuditSink    ProbeAuditSink
+	callers           callerCounter
+	fullOutputCallers map[Caller]bool
+	pendingPassing    bool
+	changes           changeTracker
+	budget            *probeBudget
+	holdDown          *holdDown
+	escalation        *escalation
+	htmlTemplate      *htmltemplate.Template
+	textTemplate      *texttemplate.Template
+	livenessMutex     sync.Mutex
+	livenessFailing   bool
 }
 
 func (s *basicHandler) LiveEndpoint(w http.ResponseWriter, r *http.Request) {
-	s.handle(w, r, s.livenessChecks)
+	if !s.allowMethod(w, r) || !s.refresh(w, r, s.livenessChecks) {
+		return
+	}
+
+	results := acquireResults()
+	defer releaseResults(results)
+
+	start := s.clock.Now()
+	status := s.evaluateInto(results, false, s.livenessChecks)
+	elapsed := s.clock.Now().Sub(start)
+	s.livenessEvaluated(status)
+	s.writeResponse(w, r, "liveness", status, results, elapsed)
+	s.callers.add("liveness", ClassifyCaller(r))
+	s.auditProbe(r, "liveness", start, elapsed, status)
 }
 
 func (s *basicHandler) ReadyEndpoint(w http.ResponseWriter, r *http.Request) {
-	s.handle(w, r, s.readinessChecks, s.livenessChecks)
+	if !s.allowMethod(w, r) || !s.refresh(w, r, s.readinessProbe...) {
+		return
+	}
+
+	results := acquireResults()
+	defer releaseResults(results)
+
+	start := s.clock.Now()
+	status := s.readinessOverride.apply(s.evaluateInto(results, true, s.readinessProbe...))
+	elapsed := s.clock.Now().Sub(start)
+	s.writeResponse(w, r, "readiness", status, results, elapsed)
+	s.callers.add("readiness", ClassifyCaller(r))
+	s.auditProbe(r, "readiness", start, elapsed, status)
 }
 
-func (s *basicHandler) AddLivenessCheck(name string, check Check) {
-	s.checksMutex.Lock()
-	defer s.checksMutex.Unlock()
-	s.livenessChecks[name] = check
+func (s *basicHandler) AddLivenessCheck(name string, check Check, opts ...CheckOption) {
+	s.addCheck(s.livenessChecks, checkFunc{name: name, check: check}, opts)
+}
+
+func (s *basicHandler) AddReadinessCheck(name string, check Check, opts ...CheckOption) {
+	s.addCheck(s.readinessChecks, checkFunc{name: name, check: check}, opts)
+}
+
+func (s *basicHandler) AddLivenessChecker(checker Checker, opts ...CheckOption) {
+	s.addCheck(s.livenessChecks, checker, opts)
+}
+
+func (s *basicHandler) AddReadinessChecker(checker Checker, opts ...CheckOption) {
+	s.addCheck(s.readinessChecks, checker, opts)
 }
 
-func (s *basicHandler) AddReadinessCheck(name string, check Check) {
+func (s *basicHandler) addCheck(checks map[string]*checkEntry, checker Checker, opts []CheckOption) {
+	name := checker.Name()
+	entry := &checkEntry{
+		name:  name,
+		check: checker,
+	}
+	for _, opt := range opts {
+		opt(&entry.config)
+	}
+
 	s.checksMutex.Lock()
 	defer s.checksMutex.Unlock()
-	s.readinessChecks[name] = check
+
+	if s.dependencyCycle(name, entry.config.dependencies) {
+		panic(fmt.Sprintf("healthcheck: dependency cycle for check %q", name))
+	}
+	if old, ok := checks[name]; ok {
+		old.stop()
+	}
+	if entry.config.slo != nil && s.statsStore == nil {
+		entry.slo = &sloTracker{config: *entry.config.slo}
+	}
+	if entry.config.historySize > 0 && s.statsStore == nil {
+		entry.history = newObservationHistory(entry.config.historySize)
+	}
+	if entry.config.schedule != nil {
+		s.startBackground(entry)
+	}
+	checks[name] = entry
 }
 
 func (s *basicHandler) AddCheckErrorHandler(handler ErrorHandler) {
 	s.errorHandler = handler
+	s.reportLoadError()
 }
 
-type result struct {
-	name   string
-	result string
+func (s *basicHandler) AddCheckResultHandler(handler ResultHandler) {
+	s.checksMutex.Lock()
+	defer s.checksMutex.Unlock()
+	s.resultHandlers = append(s.resultHandlers, handler)
 }
 
-func (s *basicHandler) collectChecks(checks map[string]Check, resultsOut map[string]string) (status int) {
-	s.checksMutex.RLock()
-	defer s.checksMutex.RUnlock()
+func (s *basicHandler) notifyResult(name string, result Result) {
+	for _, handler := range s.resultHandlers {
+		safeCall(func() { handler(name, result) })
+	}
+}
+
+// safeCall calls the user provided callback, a panic in an error or result
+// handler must not take down the worker executing the check.
+func safeCall(callback func()) {
+	defer func() {
+		_ = recover()
+	}()
+	callback()
+}
+
+// runCheck executes the check, recovering its panics and notifying
+// the error and result handlers.
+func (s *basicHandler) runCheck(entry *checkEntry) (res Result) {
+	name := entry.name
+
+	if err := s.disabledByPanics(name); err != nil {
+		return Result{Err: err}
+	}
+
+	start := s.clock.Now()
+
+	defer func() {
+		// check panic error
+		if r := recover(); r != nil {
+			res.Err = fmt.Errorf("%w: %v", ErrPanicked, r)
+			s.recordPanic(name)
+		}
+		res.Duration = s.clock.Now().Sub(start)
+		res.Labels = entry.config.labels
+		if res.Err != nil && res.Code == "" {
+			res.Code = ErrorCode(res.Err)
+			if res.Code == "" {
+				res.Code = entry.config.errorCode
+			}
+		}
+
+		s.recordStats(entry, res)
+		s.notifyResult(name, res)
+
+		if res.Err != nil && s.errorHandler != nil {
+			safeCall(func() { s.errorHandler(name, res.Err) })
+		}
+	}()
+
+	if err := s.injectChaos(name); err != nil {
+		return Result{Err: err}
+	}
+	return entry.check.Check(s.ctx)
+}
 
-	status = http.StatusOK
+// checkResult returns the current result of the check: the last one
+// for background checks, a fresh one otherwise.
+func (s *basicHandler) checkResult(entry *checkEntry) Result {
+	if s.checkDisabled(entry.name) {
+		return Result{Err: ErrCheckDisabled}
+	}
+	if entry.config.wrongRole() {
+		return Result{Err: ErrWrongRole}
+	}
+	if entry.background != nil {
+		return s.suppressMaintenance(entry, s.backgroundResult(entry))
+	}
 
+	res := s.runCheck(entry)
+	if s.retryOnFailure && retryable(res.Err) {
+		res = s.retryCheck(entry, res)
+	}
+	return s.suppressMaintenance(entry, res)
+}
+
+// collectChecks executes the checks and returns their results by name.
+func (s *basicHandler) collectChecks(checks []*checkEntry) map[string]Result {
+	resultsOut := make(map[string]Result, len(checks))
+	s.collectChecksInto(checks, resultsOut)
+	return resultsOut
+}
+
+// collectChecksInto executes the checks and stores their results by name in resultsOut.
+func (s *basicHandler) collectChecksInto(checks []*checkEntry, resultsOut map[string]Result) {
 	if len(checks) == 0 {
 		return
 	}
 
+	// every check stores its result in its own slot, so nothing a check
+	// or a handler does can block the others from completing
 	var (
-		wg      = sync.WaitGroup{}
-		results = make(chan result)
+		wg    = sync.WaitGroup{}
+		slots = make([]checkRun, len(checks))
+		runs  map[string]*checkRun
 	)
 
-	for name, check := range checks {
+	// the runs are only looked up by name to wait for dependencies,
+	// don't pay for the index and the channels without any
+	for _, entry := range checks {
+		if len(entry.config.dependencies) > 0 {
+			runs = make(map[string]*checkRun, len(checks))
+			break
+		}
+	}
+	if runs != nil {
+		// the dependencies are submitted to the executor first, so they're
+		// picked up by a worker before the checks waiting for them
+		checks = sortByDependencies(checks)
+		for i, entry := range checks {
+			slots[i].done = make(chan struct{})
+			runs[entry.name] = &slots[i]
+		}
+	}
+
+	for i, entry := range checks {
 		wg.Add(1)
 
-		go func(name string, check Check) {
-			defer func() {
-				wg.Done()
+		entry, run := entry, &slots[i]
+		s.executor.submit(s.ctx, func() {
+			defer wg.Done()
 
-				// check panic error
-				if r := recover(); r != nil {
-					results <- result{
-						name:   name,
-						result: fmt.Sprintf("checker panic recovered: %v", r),
-					}
+			run.result = Result{Err: waitDependencies(entry, runs)}
+			if run.result.Err == nil {
+				run.result = s.checkResult(entry)
+			}
+			if run.done != nil {
+				close(run.done)
+			}
+		})
+	}
 
-					if s.errorHandler != nil {
-						s.errorHandler(name, fmt.Errorf("checker panic recovered: %v", r))
-					}
-				}
-			}()
+	wg.Wait()
 
-			var val = successCheckerResultString
-			if err := check(); err != nil {
-				val = err.Error()
+	for i, entry := range checks {
+		resultsOut[entry.name] = slots[i].result
+	}
+}
 
-				if s.errorHandler != nil {
-					s.errorHandler(name, err)
-				}
+// probeStatus returns the HTTP status of a probe evaluating the checks.
+func (s *basicHandler) probeStatus(checks []*checkEntry, results map[string]Result, readiness bool) int {
+	return s.statusCode(s.probeState(checks, results, readiness))
+}
+
+// probeState returns the outcome of a probe evaluating the checks. For readiness,
+// failures of non-critical checks are tolerated up to the threshold, failures
+// of Important checks degrade the probe and Informational ones are ignored.
+func (s *basicHandler) probeState(checks []*checkEntry, results map[string]Result, readiness bool) probeState {
+	var (
+		nonCritical, nonCriticalFailed int
+		degraded                       bool
+	)
+	for _, entry := range checks {
+		if entry.config.reportOnly {
+			continue
+		}
+
+		failed := s.pendingFailed(results[entry.name].Err)
+
+		if readiness && entry.config.nonCritical {
+			nonCritical++
+			if failed {
+				nonCriticalFailed++
 			}
+			continue
+		}
 
-			results <- result{
-				name:   name,
-				result: val,
+		if failed {
+			switch {
+			case !readiness || entry.config.criticality == Critical:
+				return probeFail
+			case entry.config.criticality == Important:
+				degraded = true
 			}
-		}(name, check)
+		}
 	}
 
-	// wait for all checks to be made
-	// then close the results channel
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	if s.partialReadiness.exceeded(nonCriticalFailed, nonCritical) {
+		return probeFail
+	}
+	if degraded {
+		return probeDegraded
+	}
+	return probePass
+}
+
+// allowMethod replies with 405 Method Not Allowed and returns false
+// if the request can't be processed by a probe endpoint.
+func (s *basicHandler) allowMethod(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && (r.Method != http.MethodHead || !s.loadBalancerProbes) {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
 
-	for res := range results {
-		resultsOut[res.name] = res.result
+// evaluate runs all the given checks and returns the resulting HTTP status
+// of the liveness or readiness probe along with the per check results.
+func (s *basicHandler) evaluate(readiness bool, checks ...map[string]*checkEntry) (int, map[string]Result) {
+	results := make(map[string]Result)
+	return s.evaluateInto(results, readiness, checks...), results
+}
+
+// evaluateInto is evaluate storing the per check results in results.
+func (s *basicHandler) evaluateInto(results map[string]Result, readiness bool, checks ...map[string]*checkEntry) int {
+	return s.statusCode(s.evaluateState(results, readiness, checks...))
+}
 
-		if res.result != successCheckerResultString {
-			status = http.StatusServiceUnavailable
+// evaluateState is evaluateInto returning the outcome of the probe.
+func (s *basicHandler) evaluateState(results map[string]Result, readiness bool, checks ...map[string]*checkEntry) probeState {
+	state := s.evaluateChecks(results, readiness, checks...)
+	if s.escalation != nil {
+		if readiness {
+			s.escalation.observe(state, s.clock.Now())
+		} else {
+			state = s.escalate(results, state)
 		}
 	}
+	if readiness && s.holdDown != nil {
+		state = s.holdDown.apply(state, s.clock.Now())
+	}
+	return state
+}
 
-	return status
+// evaluateChecks executes the checks of the probe and returns its outcome.
+func (s *basicHandler) evaluateChecks(results map[string]Result, readiness bool, checks ...map[string]*checkEntry) probeState {
+	entries := acquireEntries()
+	defer releaseEntries(entries)
+
+	*entries = s.appendEntries(*entries, checks...)
+
+	var calls int
+	if s.budget != nil {
+		calls = probeCalls(*entries)
+		if snapshot := s.budget.acquire(readiness, calls, s.clock.Now()); snapshot != nil {
+			for name, res := range snapshot.results {
+				results[name] = res
+			}
+			return snapshot.state
+		}
+	}
+
+	s.collectChecksInto(*entries, results)
+	s.saveState(results)
+	s.changes.observe(results, s.clock.Now())
+
+	state := s.probeState(*entries, results, readiness)
+	if s.budget != nil {
+		s.budget.release(readiness, calls, state, results)
+	}
+	return state
 }
 
-func (s *basicHandler) handle(w http.ResponseWriter, r *http.Request, checks ...map[string]Check) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+// resultOutputs converts the check results to their representation in the full output:
+// a string, or an object if the check reported details.
+func (s *basicHandler) resultOutputs(results map[string]Result) map[string]any {
+	checkResults := make(map[string]any, len(results))
+	for name, res := range results {
+		checkResults[name] = s.resultOutput(name, res, false)
+	}
+	return checkResults
+}
+
+// resultOutput converts a check result to its representation in the full output,
+// including the duration of the check if timing is set.
+func (s *basicHandler) resultOutput(name string, res Result, timing bool) any {
+	status := s.successString
+	if res.Err != nil {
+		status = res.Err.Error()
+		if s.messageMapper != nil {
+			status = s.messageMapper(name, res.Err)
+		}
+	}
+	if res.Stale {
+		status += staleSuffix
+	}
+
+	if timing {
+		seconds := res.Duration.Seconds()
+		return detailedOutput{Status: status, Kind: res.Kind(), Code: res.Code, Duration: &seconds, Details: res.Details}
 	}
+	if len(res.Details) > 0 || res.Code != "" {
+		return detailedOutput{Status: status, Kind: res.Kind(), Code: res.Code, Details: res.Details}
+	}
+	return status
+}
+
+// detailedOutput is the full output of a check which reported details,
+// a code or was requested with its timing.
+type detailedOutput struct {
+	Status   string         `json:"status"`
+	Kind     ResultKind     `json:"kind"`
+	Code     string         `json:"code,omitempty"`
+	Duration *float64       `json:"duration_seconds,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+}
+
+// entries returns the checks of all the given sets, a check
+// present in several sets is returned once.
+func (s *basicHandler) entries(checks ...map[string]*checkEntry) []*checkEntry {
+	return s.appendEntries(nil, checks...)
+}
+
+// appendEntries is entries appending the checks to dst.
+func (s *basicHandler) appendEntries(dst []*checkEntry, checks ...map[string]*checkEntry) []*checkEntry {
+	s.checksMutex.RLock()
+	defer s.checksMutex.RUnlock()
 
-	checkResults := make(map[string]string)
-	status := http.StatusOK
-	for _, m := range checks {
-		if s := s.collectChecks(m, checkResults); s != http.StatusOK {
-			status = s
+	for i, m := range checks {
+	entries:
+		for name, entry := range m {
+			// the sets are indexed by name, look the check up
+			// in the previous ones rather than tracking the seen ones
+			for _, prev := range checks[:i] {
+				if _, ok := prev[name]; ok {
+					continue entries
+				}
+			}
+			dst = append(dst, entry)
 		}
 	}
+	return dst
+}
+
+func (s *basicHandler) writeResponse(
+	w http.ResponseWriter, r *http.Request, probe string, status int, results map[string]Result, elapsed time.Duration,
+) {
+	// The format is negotiated once here for all the representations of a probe.
+	w.Header().Add("Vary", "Accept")
+	format, ok := negotiateFormat(r)
+	switch {
+	case !ok:
+		http.Error(w, "unknown format", http.StatusNotAcceptable)
+		return
+	case format == formatHealthJSON:
+		s.writeHealthJSON(w, r, status, results, elapsed)
+		return
+	case format == formatOpenMetrics:
+		s.writeProbeMetrics(w, probe, status, results, elapsed)
+		return
+	case s.writeTemplate(w, format, probe, status, results, elapsed):
+		return
+	}
 
 	// Set response code and content header
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set(DurationHeader, strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64))
 	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Set("Pragma", "no-cache")
 	w.Header().Set("Expires", "0")
@@ -195,14 +687,17 @@ func (s *basicHandler) handle(w http.ResponseWriter, r *http.Request, checks ...
 
 	// If not ?full=1, we return an empty body. Kubernetes only cares about
 	// HTTP status codes, so we won't waste bytes on the full request body.
-	if r.URL.Query().Get("full") != "1" {
-		_, _ = w.Write([]byte("{}\n"))
+	if !s.fullOutput(r) {
+		_, _ = w.Write(emptyBody)
 		return
 	}
 
-	// Write the JSON body, ignoring any encoding errors (which
-	// are actually not possible because we encode map[string]string).
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "    ")
-	_ = encoder.Encode(checkResults)
+	bw := acquireWriter(w)
+	defer releaseWriter(bw)
+
+	// Write the JSON body, ignoring any write errors (the client is gone)
+	// and encoding errors (which are actually not possible unless
+	// a Checker reports details which can't be encoded).
+	_ = s.writeResults(bw, results, r.URL.Query().Get("compact") == "1", r.URL.Query().Get("timing") == "1")
+	_ = bw.Flush()
 }
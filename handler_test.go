@@ -1,10 +1,16 @@
 package healthcheck
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/catalystgo/healthcheck/mock"
 	"github.com/golang/mock/gomock"
@@ -185,3 +191,213 @@ func TestHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerResultHandler(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		results = make(map[string]Result)
+		failErr = errors.New("failed check")
+	)
+
+	h := NewHandler()
+	h.AddCheckResultHandler(func(name string, result Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[name] = result
+	})
+	h.AddLivenessCheck("pass", func() error { return nil })
+	h.AddReadinessCheck("fail", func() error { return failErr })
+
+	req, err := http.NewRequest(http.MethodGet, ReadinessHandlerPath, nil)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(results) != 2 {
+		t.Fatalf("Wrong number of results\n"+
+			"expected: %v\n"+
+			"actual  : %v", 2, len(results))
+	}
+	if results["pass"].Err != nil {
+		t.Errorf("Unexpected error for %q: %v", "pass", results["pass"].Err)
+	}
+	if !errors.Is(results["fail"].Err, failErr) {
+		t.Errorf("Wrong error for %q\n"+
+			"expected: %v\n"+
+			"actual  : %v", "fail", failErr, results["fail"].Err)
+	}
+}
+
+func TestHandlerReportOnly(t *testing.T) {
+	h := NewHandler()
+	h.AddLivenessCheck("canary", func() error { return errors.New("failed canary check") }, ReportOnly())
+	h.AddReadinessCheck("stable", func() error { return nil })
+
+	for _, path := range []string{LivenessHandlerPath, ReadinessHandlerPath} {
+		req, err := http.NewRequest(http.MethodGet, path+"?full=1", nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Wrong code for %q\n"+
+				"expected: %v\n"+
+				"actual  : %v", path, http.StatusOK, rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), "failed canary check") {
+			t.Errorf("Report-only check is missing in the output of %q:\n%s", path, rr.Body.String())
+		}
+	}
+}
+
+func TestHandlerIndependentProbes(t *testing.T) {
+	h := NewHandler(WithIndependentProbes())
+	h.AddLivenessCheck("deadlock", func() error { return errors.New("failed deadlock check") })
+	h.AddReadinessCheck("database", func() error { return nil })
+
+	tests := []struct {
+		path   string
+		expect int
+	}{
+		{path: LivenessHandlerPath, expect: http.StatusServiceUnavailable},
+		{path: ReadinessHandlerPath, expect: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path+"?full=1", nil))
+		if rr.Code != tt.expect {
+			t.Errorf("Wrong code for %q\n"+
+				"expected: %v\n"+
+				"actual  : %v", tt.path, tt.expect, rr.Code)
+		}
+	}
+
+	if status, results, _ := h.Check(context.Background()); status != StatusPass || len(results) != 1 {
+		t.Errorf("Wrong readiness\n"+"expected: %v %v\n"+"actual  : %v %v", StatusPass, 1, status, len(results))
+	}
+}
+
+func benchmarkHandler(b *testing.B, target string) {
+	h := NewHandler()
+	for i := 0; i < 10; i++ {
+		h.AddLivenessCheck(fmt.Sprintf("live-%d", i), func() error { return nil })
+		h.AddReadinessCheck(fmt.Sprintf("ready-%d", i), func() error { return nil })
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		b.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkLiveEndpoint(b *testing.B) {
+	benchmarkHandler(b, "/live")
+}
+
+func BenchmarkReadyEndpoint(b *testing.B) {
+	benchmarkHandler(b, "/ready")
+}
+
+func BenchmarkReadyEndpointFull(b *testing.B) {
+	benchmarkHandler(b, "/ready?full=1")
+}
+
+func TestHandlerCallbackPanic(t *testing.T) {
+	h := NewHandler(WithWorkers(2))
+	h.AddCheckErrorHandler(func(string, error) { panic("error handler") })
+	h.AddCheckResultHandler(func(string, Result) { panic("result handler") })
+	for i := 0; i < 5; i++ {
+		h.AddReadinessCheck(fmt.Sprintf("check-%d", i), func() error { return errors.New("failed") })
+	}
+
+	serve := func() {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+		}
+	}
+
+	// start the workers before counting the goroutines
+	serve()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		serve()
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Leaked goroutines\n"+"expected: %v\n"+"actual  : %v", before, after)
+	}
+}
+
+func TestHandlerOutputFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		expectBody string
+	}{
+		{
+			name:       "indented",
+			target:     "/ready?full=1",
+			expectBody: "{\n    \"cache\": \"failed\",\n    \"database\": \"OK\"\n}\n",
+		},
+		{
+			name:       "compact",
+			target:     "/ready?full=1&compact=1",
+			expectBody: "{\"cache\":\"failed\",\"database\":\"OK\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler()
+			h.AddReadinessCheck("database", func() error { return nil })
+			h.AddReadinessCheck("cache", func() error { return errors.New("failed") })
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Body.String() != tt.expectBody {
+				t.Errorf("Wrong body\n"+"expected: %q\n"+"actual  : %q", tt.expectBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerTiming(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock))
+	h.AddReadinessCheck("database", func() error {
+		clock.Advance(250 * time.Millisecond)
+		return nil
+	})
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready?full=1&compact=1&timing=1", nil))
+
+	expect := `{"database":{"status":"OK","kind":"ok","duration_seconds":0.25}}` + "\n"
+	if rr.Body.String() != expect {
+		t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", expect, rr.Body.String())
+	}
+	if duration := rr.Header().Get(DurationHeader); duration != "0.250000" {
+		t.Errorf("Wrong duration\n"+"expected: %v\n"+"actual  : %v", "0.250000", duration)
+	}
+}
@@ -0,0 +1,131 @@
+package healthchecktest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ErrFake is the error returned by the failing fake checks without an explicit error.
+var ErrFake = errors.New("fake check failed")
+
+// AlwaysHealthy returns a check which always passes.
+func AlwaysHealthy() healthcheck.Check {
+	return func() error {
+		return nil
+	}
+}
+
+// AlwaysFailing returns a check which always fails with err (ErrFake if nil).
+func AlwaysFailing(err error) healthcheck.Check {
+	if err == nil {
+		err = ErrFake
+	}
+	return func() error {
+		return err
+	}
+}
+
+// FailFirstN returns a check which fails its first n executions, then passes.
+func FailFirstN(n int) healthcheck.Check {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+		if calls <= n {
+			return fmt.Errorf("%w: execution %d of %d failing", ErrFake, calls, n)
+		}
+		return nil
+	}
+}
+
+// Flaky returns a check failing the given share (0 to 1) of its executions.
+// Failures are spread evenly rather than randomly, so tests are deterministic:
+// with a 0.25 rate, every fourth execution fails.
+func Flaky(rate float64) healthcheck.Check {
+	// the failures are counted with integers, as summing the float rate
+	// drifts: three executions at 1/3 must fail exactly once
+	failures, every := fraction(rate)
+	var (
+		mu  sync.Mutex
+		acc int
+	)
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		acc += failures
+		if acc >= every {
+			acc -= every
+			return ErrFake
+		}
+		return nil
+	}
+}
+
+// maxFlakyDenominator is the largest number of executions
+// the failures of a Flaky check are spread over.
+const maxFlakyDenominator = 1000
+
+// fraction returns the fraction closest to the rate, clamped to 0 and 1,
+// with a denominator up to maxFlakyDenominator.
+func fraction(rate float64) (num, den int) {
+	switch {
+	case rate <= 0:
+		return 0, 1
+	case rate >= 1:
+		return 1, 1
+	}
+
+	num, den = 0, 1
+	for q := 1; q <= maxFlakyDenominator; q++ {
+		p := int(math.Round(rate * float64(q)))
+		if math.Abs(rate-float64(p)/float64(q)) < math.Abs(rate-float64(num)/float64(den)) {
+			num, den = p, q
+		}
+	}
+	return num, den
+}
+
+// ManualCheck is a check whose result is set from the test code.
+type ManualCheck struct {
+	mu  sync.RWMutex
+	err error
+}
+
+// NewManualCheck creates a passing ManualCheck.
+func NewManualCheck() *ManualCheck {
+	return &ManualCheck{}
+}
+
+// SetHealthy makes the check pass.
+func (c *ManualCheck) SetHealthy() {
+	c.SetError(nil)
+}
+
+// SetFailing makes the check fail with ErrFake.
+func (c *ManualCheck) SetFailing() {
+	c.SetError(ErrFake)
+}
+
+// SetError makes the check return err.
+func (c *ManualCheck) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Check is the healthcheck.Check returning the current result.
+func (c *ManualCheck) Check() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
@@ -0,0 +1,126 @@
+// Package healthchecktest provides helpers to test the health wiring of a service:
+// asserting the state of a healthcheck.Handler, running a single check and
+// capturing the executed checks results.
+package healthchecktest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// RequireReady fails the test immediately if the readiness probe of the handler fails.
+func RequireReady(t testing.TB, h healthcheck.Prober) {
+	t.Helper()
+	requireStatus(t, "readiness", healthcheck.StatusPass, h.Check)
+}
+
+// RequireNotReady fails the test immediately if the readiness probe of the handler passes.
+func RequireNotReady(t testing.TB, h healthcheck.Prober) {
+	t.Helper()
+	requireStatus(t, "readiness", healthcheck.StatusFail, h.Check)
+}
+
+// RequireLive fails the test immediately if the liveness probe of the handler fails.
+func RequireLive(t testing.TB, h healthcheck.Prober) {
+	t.Helper()
+	requireStatus(t, "liveness", healthcheck.StatusPass, h.CheckLiveness)
+}
+
+// RequireNotLive fails the test immediately if the liveness probe of the handler passes.
+func RequireNotLive(t testing.TB, h healthcheck.Prober) {
+	t.Helper()
+	requireStatus(t, "liveness", healthcheck.StatusFail, h.CheckLiveness)
+}
+
+func requireStatus(
+	t testing.TB,
+	probe string,
+	expect healthcheck.Status,
+	check func(ctx context.Context) (healthcheck.Status, map[string]healthcheck.Result, error),
+) {
+	t.Helper()
+
+	status, results, err := check(context.Background())
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if status != expect {
+		t.Fatalf("Wrong %s status\n"+"expected: %v\n"+"actual  : %v\n%s", probe, expect, status, summary(results))
+	}
+}
+
+// RunCheck executes the named check of the handler and returns its result,
+// failing the test immediately if there's no such check.
+func RunCheck(t testing.TB, h healthcheck.Prober, name string) healthcheck.Result {
+	t.Helper()
+
+	result, err := h.RunCheck(context.Background(), name)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	return result
+}
+
+// Recorder captures the results of the checks executed by a handler.
+type Recorder struct {
+	mu      sync.Mutex
+	results map[string][]healthcheck.Result
+}
+
+// Record creates a Recorder capturing the results of the checks executed by h.
+func Record(h healthcheck.Handler) *Recorder {
+	r := &Recorder{results: make(map[string][]healthcheck.Result)}
+	h.AddCheckResultHandler(r.handle)
+	return r
+}
+
+func (r *Recorder) handle(name string, result healthcheck.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[name] = append(r.results[name], result)
+}
+
+// Results returns the results of the named check, in execution order.
+func (r *Recorder) Results(name string) []healthcheck.Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]healthcheck.Result(nil), r.results[name]...)
+}
+
+// Last returns the last result of the named check, false if it hasn't been executed.
+func (r *Recorder) Last(name string) (healthcheck.Result, bool) {
+	results := r.Results(name)
+	if len(results) == 0 {
+		return healthcheck.Result{}, false
+	}
+	return results[len(results)-1], true
+}
+
+// Count returns the number of executions of the named check.
+func (r *Recorder) Count(name string) int {
+	return len(r.Results(name))
+}
+
+// Reset forgets the captured results.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = make(map[string][]healthcheck.Result)
+}
+
+// summary returns the failed checks of the results, one per line.
+func summary(results map[string]healthcheck.Result) string {
+	var lines []string
+	for name, result := range results {
+		if result.Err != nil {
+			lines = append(lines, name+": "+result.Err.Error())
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,97 @@
+package healthchecktest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestHelpers(t *testing.T) {
+	h := healthcheck.NewHandler()
+	recorder := Record(h)
+
+	h.AddLivenessCheck("live", func() error { return nil })
+	RequireLive(t, h)
+	RequireReady(t, h)
+
+	failure := errors.New("failed")
+	h.AddReadinessCheck("database", func() error { return failure })
+	RequireLive(t, h)
+	RequireNotReady(t, h)
+
+	if result := RunCheck(t, h, "database"); result.Err != failure {
+		t.Errorf("Wrong result\n"+"expected: %v\n"+"actual  : %v", failure, result.Err)
+	}
+	if n := recorder.Count("database"); n != 2 {
+		t.Errorf("Wrong number of results\n"+"expected: %v\n"+"actual  : %v", 2, n)
+	}
+	if last, ok := recorder.Last("database"); !ok || last.Err != failure {
+		t.Errorf("Wrong last result\n"+"expected: %v\n"+"actual  : %v", failure, last.Err)
+	}
+}
+
+func TestFakeChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  healthcheck.Check
+		expect []bool
+	}{
+		{
+			name:   "always healthy",
+			check:  AlwaysHealthy(),
+			expect: []bool{true, true, true},
+		},
+		{
+			name:   "always failing",
+			check:  AlwaysFailing(nil),
+			expect: []bool{false, false, false},
+		},
+		{
+			name:   "fail first n",
+			check:  FailFirstN(2),
+			expect: []bool{false, false, true, true},
+		},
+		{
+			name:   "flaky",
+			check:  Flaky(0.5),
+			expect: []bool{true, false, true, false},
+		},
+		{
+			name:   "flaky with a third failing",
+			check:  Flaky(1.0 / 3),
+			expect: []bool{true, true, false, true, true, false, true, true, false},
+		},
+		{
+			name:   "flaky with most failing",
+			check:  Flaky(0.75),
+			expect: []bool{true, false, false, false, true, false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i, expect := range tt.expect {
+				if passed := tt.check() == nil; passed != expect {
+					t.Errorf("Wrong result of execution %d\n"+"expected: %v\n"+"actual  : %v", i+1, expect, passed)
+				}
+			}
+		})
+	}
+}
+
+func TestManualCheck(t *testing.T) {
+	h := healthcheck.NewHandler()
+	check := NewManualCheck()
+	h.AddReadinessCheck("manual", check.Check)
+
+	RequireReady(t, h)
+	check.SetFailing()
+	RequireNotReady(t, h)
+	check.SetHealthy()
+	RequireReady(t, h)
+}
@@ -0,0 +1,79 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Heartbeat is a push-style check: instead of being probed, a background worker
+// or consumer reports its own liveness by calling Beat, and the check fails
+// if it hasn't been called within the TTL.
+//
+//	heartbeat := healthcheck.NewHeartbeatCheck("consumer", time.Minute)
+//	handler.AddLivenessCheck(heartbeat.Name(), heartbeat.Check)
+//
+//	for msg := range messages {
+//		heartbeat.Beat()
+//		...
+//	}
+type Heartbeat struct {
+	name  string
+	ttl   time.Duration
+	clock Clock
+
+	mu   sync.RWMutex
+	last time.Time
+}
+
+// HeartbeatOption configures a Heartbeat.
+type HeartbeatOption func(h *Heartbeat)
+
+// WithHeartbeatClock sets the Clock of the heartbeat, SystemClock by default.
+func WithHeartbeatClock(clock Clock) HeartbeatOption {
+	return func(h *Heartbeat) {
+		h.clock = clock
+	}
+}
+
+// NewHeartbeatCheck creates a Heartbeat with the given TTL. The creation
+// counts as the first beat, so the worker has the TTL to start beating.
+func NewHeartbeatCheck(name string, ttl time.Duration, opts ...HeartbeatOption) *Heartbeat {
+	h := &Heartbeat{
+		name:  name,
+		ttl:   ttl,
+		clock: SystemClock,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.last = h.clock.Now()
+	return h
+}
+
+// Name returns the name of the heartbeat.
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+// Beat reports the worker is alive.
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = h.clock.Now()
+}
+
+// Last returns the time of the last beat.
+func (h *Heartbeat) Last() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.last
+}
+
+// Check is a Check failing if Beat hasn't been called within the TTL.
+func (h *Heartbeat) Check() error {
+	if since := h.clock.Now().Sub(h.Last()); since > h.ttl {
+		return fmt.Errorf("%s: no heartbeat for %v (ttl %v)", h.name, since.Round(time.Millisecond), h.ttl)
+	}
+	return nil
+}
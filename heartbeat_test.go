@@ -0,0 +1,24 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	heartbeat := NewHeartbeatCheck("consumer", time.Minute, WithHeartbeatClock(clock))
+	if err := heartbeat.Check(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if err := heartbeat.Check(); err == nil {
+		t.Errorf("Expected an error after the TTL")
+	}
+
+	heartbeat.Beat()
+	if err := heartbeat.Check(); err != nil {
+		t.Errorf("Received unexpected error:\n%+v", err)
+	}
+}
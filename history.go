@@ -0,0 +1,112 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+// WithHistory keeps the numeric details (see Result.Metrics) observed by the
+// last size executions of the check and exposes them as time series per detail
+// on the stats endpoint, e.g. to draw sparklines of a lag or a latency without
+// an external time series database.
+func WithHistory(size int) CheckOption {
+	return func(c *checkConfig) {
+		c.historySize = size
+	}
+}
+
+// Observation is a value observed by an execution of a check.
+type Observation struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// CheckStats is the statistics of a check on the stats endpoint.
+type CheckStats struct {
+	// SLOStats is the availability of the check, nil without WithSLO.
+	*SLOStats
+	// History are the recent values of the numeric details by name, oldest first.
+	History map[string][]Observation `json:"history,omitempty"`
+}
+
+// historySample is the numeric details observed by an execution.
+type historySample struct {
+	time    time.Time
+	metrics map[string]float64
+}
+
+// observationHistory is the ring buffer of the last executions of a check.
+type observationHistory struct {
+	mu      sync.Mutex
+	samples []historySample
+	next    int
+	full    bool
+}
+
+func newObservationHistory(size int) *observationHistory {
+	return &observationHistory{samples: make([]historySample, size)}
+}
+
+// record stores the details of an execution, evicting the oldest one if full.
+func (h *observationHistory) record(now time.Time, metrics map[string]float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[h.next] = historySample{time: now, metrics: metrics}
+	h.next = (h.next + 1) % len(h.samples)
+	h.full = h.full || h.next == 0
+}
+
+// series returns the recorded values by detail, oldest first.
+func (h *observationHistory) series() map[string][]Observation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.samples[:h.next]
+	if h.full {
+		samples = append(append([]historySample(nil), h.samples[h.next:]...), h.samples[:h.next]...)
+	}
+
+	series := make(map[string][]Observation)
+	for _, sample := range samples {
+		for key, value := range sample.metrics {
+			series[key] = append(series[key], Observation{Time: sample.time, Value: value})
+		}
+	}
+	return series
+}
+
+// recordHistory stores the details of the execution if the check has a history.
+func (s *basicHandler) recordHistory(entry *checkEntry, res Result) {
+	if entry.history != nil {
+		entry.history.record(s.clock.Now(), res.Metrics())
+	}
+}
+
+// checkStats returns the statistics of the checks with an SLO or a history by name.
+func (s *basicHandler) checkStats() map[string]CheckStats {
+	now := s.clock.Now()
+	stats := make(map[string]CheckStats)
+	for _, entry := range s.entries(s.readinessChecks, s.livenessChecks) {
+		if s.statsStore != nil {
+			if st, ok := s.storedStats(entry, now); ok && (st.SLOStats != nil || st.History != nil) {
+				stats[entry.name] = st
+			}
+			continue
+		}
+		if entry.slo == nil && entry.history == nil {
+			continue
+		}
+
+		var st CheckStats
+		if entry.slo != nil {
+			slo := entry.slo.stats(now)
+			st.SLOStats = &slo
+		}
+		if entry.history != nil {
+			st.History = entry.history.series()
+		}
+		stats[entry.name] = st
+	}
+	return stats
+}
@@ -0,0 +1,49 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	h := NewHandler(WithClock(clock))
+
+	lag := 0.0
+	h.AddReadinessChecker(Observer("replication", "lag", "seconds",
+		func(context.Context) (float64, error) {
+			lag++
+			return lag, nil
+		}, nil), WithHistory(2))
+	h.AddReadinessCheck("plain", func() error { return nil })
+
+	for i := 0; i < 3; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+		clock.Advance(time.Second)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatsHandlerPath, nil))
+
+	var stats map[string]CheckStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	expect := map[string]CheckStats{
+		"replication": {History: map[string][]Observation{
+			"lag": {
+				{Time: start.Add(time.Second), Value: 2},
+				{Time: start.Add(2 * time.Second), Value: 3},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(stats, expect) {
+		t.Errorf("Wrong stats\n"+"expected: %+v\n"+"actual  : %+v", expect, stats)
+	}
+}
@@ -0,0 +1,57 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+// WithReadinessHoldDown keeps the readiness probe failing for the hold-down
+// duration after it recovers from a failure, and restarts the hold-down if it
+// fails again meanwhile, so the load balancers don't thunder back onto a barely
+// recovered instance. The checks are reported passing in the full output
+// during the hold-down. The first recovery after startup isn't held down.
+func WithReadinessHoldDown(duration time.Duration) Option {
+	return func(h *basicHandler) {
+		h.holdDown = &holdDown{duration: duration}
+	}
+}
+
+// holdDown is the state of the readiness hold-down.
+type holdDown struct {
+	duration time.Duration
+
+	mu sync.Mutex
+	// failing is true if the last evaluation failed after the first pass
+	failing bool
+	// passed is true once the probe has passed
+	passed bool
+	// holding is true during the hold-down which started at recovered
+	holding   bool
+	recovered time.Time
+}
+
+// apply returns the outcome of the readiness probe taking the hold-down into account.
+func (h *holdDown) apply(state probeState, now time.Time) probeState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if state == probeFail {
+		h.failing = h.passed
+		h.holding = false
+		return state
+	}
+
+	if h.failing {
+		h.failing = false
+		h.holding = true
+		h.recovered = now
+	}
+	h.passed = true
+	if h.holding {
+		if now.Sub(h.recovered) < h.duration {
+			return probeFail
+		}
+		h.holding = false
+	}
+	return state
+}
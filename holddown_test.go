@@ -0,0 +1,50 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithReadinessHoldDown(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock), WithReadinessHoldDown(30*time.Second))
+
+	var failing atomic.Bool
+	h.AddReadinessCheck("database", func() error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		failing bool
+		code    int
+	}{
+		{name: "starting up", failing: true, code: http.StatusServiceUnavailable},
+		{name: "first recovery isn't held down", code: http.StatusOK},
+		{name: "failure", failing: true, code: http.StatusServiceUnavailable},
+		{name: "recovered", advance: time.Second, code: http.StatusServiceUnavailable},
+		{name: "held down", advance: 20 * time.Second, code: http.StatusServiceUnavailable},
+		{name: "failure restarts the hold-down", advance: time.Second, failing: true, code: http.StatusServiceUnavailable},
+		{name: "recovered again", advance: time.Second, code: http.StatusServiceUnavailable},
+		{name: "still held down", advance: 29 * time.Second, code: http.StatusServiceUnavailable},
+		{name: "hold-down elapsed", advance: time.Second, code: http.StatusOK},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		failing.Store(step.failing)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+		if rr.Code != step.code {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.code, rr.Code)
+		}
+	}
+}
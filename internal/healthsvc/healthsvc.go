@@ -0,0 +1,51 @@
+// Package healthsvc maps the state of a healthcheck.Handler to the gRPC
+// health checking protocol, for the health service implementations.
+package healthsvc
+
+import (
+	"context"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// ServingStatus has the values of grpc_health_v1.HealthCheckResponse_ServingStatus,
+// the health services convert it, so this package doesn't depend on grpc.
+type ServingStatus int32
+
+const (
+	// Unknown is the status before the first evaluation, it differs
+	// from every status a service can be reported with.
+	Unknown ServingStatus = -1
+	// StatusUnknown is the UNKNOWN status.
+	StatusUnknown ServingStatus = 0
+	// Serving is the SERVING status.
+	Serving ServingStatus = 1
+	// NotServing is the NOT_SERVING status.
+	NotServing ServingStatus = 2
+)
+
+// Status evaluates the serving status of the service: the readiness of the handler
+// for the empty name, the result of the check with this name otherwise. It returns
+// healthcheck.ErrCheckNotFound for unknown names and ctx.Err() if ctx is done first.
+func Status(ctx context.Context, h healthcheck.Prober, service string) (ServingStatus, error) {
+	if service == "" {
+		status, _, err := h.Check(ctx)
+		if err != nil {
+			return StatusUnknown, err
+		}
+		return servingStatus(status != healthcheck.StatusFail), nil
+	}
+
+	result, err := h.RunCheck(ctx, service)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	return servingStatus(result.Err == nil), nil
+}
+
+func servingStatus(serving bool) ServingStatus {
+	if serving {
+		return Serving
+	}
+	return NotServing
+}
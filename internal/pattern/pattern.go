@@ -0,0 +1,17 @@
+// Package pattern converts the route patterns registered by healthcheck.Handler
+// to the syntax of the web frameworks.
+package pattern
+
+import "strings"
+
+// Colon converts the "{name}" path parameters to ":name",
+// the syntax of gin, echo and fiber.
+func Colon(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + strings.Trim(segment, "{}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,80 @@
+// Package probe evaluates the probes of a healthcheck.Handler in process,
+// for the integrations pushing the handler state somewhere else.
+package probe
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// successResult is the result of a passed check in the full output.
+const successResult = "OK"
+
+// Result is the outcome of a probe.
+type Result struct {
+	// Status is the status of the probe.
+	Status healthcheck.Status
+	// Checks are the results of the checks by name.
+	Checks map[string]string
+}
+
+// Live evaluates the liveness probe of the handler.
+func Live(h healthcheck.Prober) Result {
+	return newResult(h.CheckLiveness(context.Background()))
+}
+
+// Ready evaluates the readiness probe of the handler.
+func Ready(h healthcheck.Prober) Result {
+	return newResult(h.Check(context.Background()))
+}
+
+// newResult converts the outcome of a probe evaluation, the error
+// is ignored as the evaluation can't be canceled.
+func newResult(status healthcheck.Status, results map[string]healthcheck.Result, _ error) Result {
+	result := Result{
+		Status: status,
+		Checks: make(map[string]string, len(results)),
+	}
+	for name, res := range results {
+		result.Checks[name] = successResult
+		if res.Err != nil {
+			result.Checks[name] = res.Err.Error()
+		}
+	}
+	return result
+}
+
+// Healthy reports whether the probe passed, possibly degraded.
+func (r Result) Healthy() bool {
+	return r.Status != healthcheck.StatusFail
+}
+
+// Failed returns the names of the failed checks, sorted.
+func (r Result) Failed() []string {
+	var failed []string
+	for name, result := range r.Checks {
+		if result != successResult {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// Summary returns a human readable summary of the failed checks,
+// "OK" if none failed.
+func (r Result) Summary() string {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return successResult
+	}
+
+	lines := make([]string, 0, len(failed))
+	for _, name := range failed {
+		lines = append(lines, name+": "+r.Checks[name])
+	}
+	return strings.Join(lines, "\n")
+}
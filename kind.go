@@ -0,0 +1,78 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+)
+
+// ResultKind is the kind of outcome of a check, separating e.g. a dependency
+// refusing the check from a dependency which never answered.
+type ResultKind string
+
+const (
+	// KindOK the check passed.
+	KindOK ResultKind = "ok"
+	// KindFailed the check failed.
+	KindFailed ResultKind = "failed"
+	// KindTimeout the check timed out.
+	KindTimeout ResultKind = "timeout"
+	// KindPanicked the check panicked.
+	KindPanicked ResultKind = "panicked"
+	// KindSkipped the check wasn't executed: disabled, disabled after too many
+	// panics or skipped because a dependency failed.
+	KindSkipped ResultKind = "skipped"
+	// KindSuppressed the check failed during a maintenance window.
+	KindSuppressed ResultKind = "suppressed"
+	// KindStale the result is outdated: restored from the persisted state
+	// or older than the TTL set by WithStaleAfter.
+	KindStale ResultKind = "stale"
+	// KindPending the check hasn't been evaluated yet.
+	KindPending ResultKind = "pending"
+)
+
+var (
+	// ErrPanicked is the error of a check which panicked.
+	ErrPanicked = errors.New("checker panic recovered")
+	// ErrSkipped is the error of a check which wasn't executed because
+	// a dependency failed or it panicked too many times.
+	ErrSkipped = errors.New("skipped")
+)
+
+// Kind returns the kind of outcome of the check.
+func (r Result) Kind() ResultKind {
+	var timeout interface{ Timeout() bool }
+
+	switch err := r.Err; {
+	case err == nil:
+		return KindOK
+	case errors.Is(err, ErrSuppressed):
+		return KindSuppressed
+	case errors.Is(err, ErrCheckDisabled), errors.Is(err, ErrSkipped):
+		return KindSkipped
+	case errors.Is(err, ErrPending):
+		return KindPending
+	case r.Stale, errors.Is(err, ErrStaleResult):
+		return KindStale
+	case errors.Is(err, ErrPanicked):
+		return KindPanicked
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeout) && timeout.Timeout():
+		return KindTimeout
+	default:
+		return KindFailed
+	}
+}
+
+// kindError is an error with its own message matching the kind sentinel
+// it wraps, for the errors whose message doesn't start with the sentinel.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
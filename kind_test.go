@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestResultKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		expect ResultKind
+	}{
+		{name: "passed", result: Result{}, expect: KindOK},
+		{name: "failed", result: Result{Err: errors.New("connection refused")}, expect: KindFailed},
+		{name: "context deadline", result: Result{Err: fmt.Errorf("ping: %w", context.DeadlineExceeded)}, expect: KindTimeout},
+		{name: "network timeout", result: Result{Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, expect: KindTimeout},
+		{name: "panicked", result: Result{Err: fmt.Errorf("%w: boom", ErrPanicked)}, expect: KindPanicked},
+		{name: "disabled", result: Result{Err: ErrCheckDisabled}, expect: KindSkipped},
+		{name: "disabled after panics", result: Result{Err: &kindError{msg: "disabled after 2 panics", kind: ErrSkipped}}, expect: KindSkipped},
+		{name: "suppressed timeout", result: Result{Err: fmt.Errorf("%w: %w", ErrSuppressed, context.DeadlineExceeded)}, expect: KindSuppressed},
+		{name: "restored", result: Result{Err: errors.New("connection refused"), Stale: true}, expect: KindStale},
+		{name: "pending", result: Result{Err: ErrPending}, expect: KindPending},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if kind := tt.result.Kind(); kind != tt.expect {
+				t.Errorf("Wrong kind\n"+"expected: %v\n"+"actual  : %v", tt.expect, kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTooSlow is the error of a check wrapped by MaxLatency which succeeded too slowly.
+var ErrTooSlow = errors.New("too slow")
+
+// MaxLatency wraps the check to fail when it succeeds but takes longer than
+// the threshold, as a dependency answering in 8 seconds is effectively
+// unhealthy for most SLOs:
+//
+//	handler.AddReadinessCheck("kafka", healthcheck.MaxLatency(kafkaCheck, time.Second))
+//
+// To degrade rather than fail, register the wrapped check as NonCritical
+// or ReportOnly, or with its own name next to the original check.
+func MaxLatency(check Check, threshold time.Duration, opts ...LatencyOption) Check {
+	l := latency{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	return func() error {
+		start := l.clock.Now()
+		if err := check(); err != nil {
+			return err
+		}
+		if elapsed := l.clock.Now().Sub(start); elapsed > threshold {
+			return fmt.Errorf("%w: took %v (threshold %v)", ErrTooSlow, elapsed.Round(time.Millisecond), threshold)
+		}
+		return nil
+	}
+}
+
+// LatencyOption configures a MaxLatency check.
+type LatencyOption func(l *latency)
+
+// WithLatencyClock sets the Clock measuring the latency, SystemClock by default.
+func WithLatencyClock(clock Clock) LatencyOption {
+	return func(l *latency) {
+		l.clock = clock
+	}
+}
+
+// latency is the configuration of a MaxLatency check.
+type latency struct {
+	clock Clock
+}
@@ -0,0 +1,41 @@
+package healthcheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaxLatency(t *testing.T) {
+	failure := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		fails   error
+		expect  error
+	}{
+		{name: "fast", elapsed: 5 * time.Millisecond},
+		{name: "slow", elapsed: 20 * time.Millisecond, expect: ErrTooSlow},
+		{name: "slow failure", elapsed: 20 * time.Millisecond, fails: failure, expect: failure},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			check := func() error {
+				clock.Advance(tt.elapsed)
+				return tt.fails
+			}
+
+			err := MaxLatency(check, 10*time.Millisecond, WithLatencyClock(clock))()
+			if !errors.Is(err, tt.expect) || (err != nil) != (tt.expect != nil) {
+				t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", tt.expect, err)
+			}
+		})
+	}
+}
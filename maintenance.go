@@ -0,0 +1,57 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSuppressed wraps the error of a check failing during one of its
+// maintenance windows, which doesn't fail the probes.
+var ErrSuppressed = errors.New("suppressed (maintenance)")
+
+// maintenanceWindow is a recurring maintenance window set by WithMaintenanceWindow.
+type maintenanceWindow struct {
+	schedule Schedule
+	duration time.Duration
+}
+
+// contains reports whether t is in one of the occurrences of the window.
+func (w maintenanceWindow) contains(t time.Time) bool {
+	// an occurrence containing t is the first one after t-duration
+	start := w.schedule.Next(t.Add(-w.duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// WithMaintenanceWindow suppresses the failures of the check during the recurring
+// maintenance windows starting at every time of the schedule and lasting for
+// duration, e.g. for a nightly database maintenance:
+//
+//	handler.AddReadinessCheck("database", check,
+//		healthcheck.WithMaintenanceWindow(healthcheck.MustParseCron("0 3 * * *"), 30*time.Minute))
+//
+// A failure during a window is reported wrapped in ErrSuppressed and doesn't fail
+// the probes, so the whole fleet doesn't churn. The check is executed as usual
+// and its error handlers are still notified. The option can be repeated.
+func WithMaintenanceWindow(schedule Schedule, duration time.Duration) CheckOption {
+	return func(c *checkConfig) {
+		c.maintenance = append(c.maintenance, maintenanceWindow{schedule: schedule, duration: duration})
+	}
+}
+
+// suppressMaintenance wraps the error of the result in ErrSuppressed
+// if the check is in one of its maintenance windows.
+func (s *basicHandler) suppressMaintenance(entry *checkEntry, res Result) Result {
+	if res.Err == nil || len(entry.config.maintenance) == 0 {
+		return res
+	}
+
+	now := s.clock.Now()
+	for _, window := range entry.config.maintenance {
+		if window.contains(now) {
+			res.Err = fmt.Errorf("%w: %w", ErrSuppressed, res.Err)
+			return res
+		}
+	}
+	return res
+}
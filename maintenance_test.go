@@ -0,0 +1,65 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		time       time.Time
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "before the window",
+			time:       time.Date(2024, 1, 1, 2, 59, 0, 0, time.UTC),
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"database":"connection refused"}` + "\n",
+		},
+		{
+			name:       "start of the window",
+			time:       time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			expectCode: http.StatusOK,
+			expectBody: `{"database":"suppressed (maintenance): connection refused"}` + "\n",
+		},
+		{
+			name:       "in the window",
+			time:       time.Date(2024, 1, 2, 3, 29, 0, 0, time.UTC),
+			expectCode: http.StatusOK,
+			expectBody: `{"database":"suppressed (maintenance): connection refused"}` + "\n",
+		},
+		{
+			name:       "end of the window",
+			time:       time.Date(2024, 1, 2, 3, 30, 0, 0, time.UTC),
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"database":"connection refused"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(WithClock(NewManualClock(tt.time)))
+			h.AddReadinessCheck("database", func() error {
+				return errors.New("connection refused")
+			}, WithMaintenanceWindow(MustParseCron("0 3 * * *"), 30*time.Minute))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1&compact=1", nil))
+			if rr.Code != tt.expectCode {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expectCode, rr.Code)
+			}
+			if rr.Body.String() != tt.expectBody {
+				t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", tt.expectBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package healthcheck
+
+// MessageMapper maps the error of a failed check to the status reported for it
+// in the full output, e.g. to emit standardized codes or translated messages
+// instead of the raw errors of the drivers.
+type MessageMapper func(name string, err error) string
+
+// WithSuccessString sets the status reported for a passed check
+// in the full output, "OK" by default.
+func WithSuccessString(success string) Option {
+	return func(h *basicHandler) {
+		h.successString = success
+	}
+}
+
+// WithMessageMapper sets the mapper of the failed checks errors
+// to their status in the full output.
+//
+//	healthcheck.WithMessageMapper(func(name string, err error) string {
+//		if errors.Is(err, context.DeadlineExceeded) {
+//			return "TIMEOUT"
+//		}
+//		return "UNAVAILABLE"
+//	})
+//
+// The errors passed to the error and result handlers aren't affected.
+func WithMessageMapper(mapper MessageMapper) Option {
+	return func(h *basicHandler) {
+		h.messageMapper = mapper
+	}
+}
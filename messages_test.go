@@ -0,0 +1,37 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerMessages(t *testing.T) {
+	h := NewHandler(
+		WithSuccessString("UP"),
+		WithMessageMapper(func(name string, _ error) string {
+			return name + "_UNAVAILABLE"
+		}),
+	)
+
+	var handled error
+	h.AddCheckErrorHandler(func(_ string, err error) {
+		handled = err
+	})
+
+	failure := errors.New("dial tcp 10.0.0.1:5432: connect: connection refused")
+	h.AddReadinessCheck("database", func() error { return failure })
+	h.AddReadinessCheck("cache", func() error { return nil })
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ready?full=1&compact=1", nil))
+
+	expect := `{"cache":"UP","database":"database_UNAVAILABLE"}` + "\n"
+	if rr.Body.String() != expect {
+		t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", expect, rr.Body.String())
+	}
+	if handled != failure {
+		t.Errorf("Wrong handled error\n"+"expected: %v\n"+"actual  : %v", failure, handled)
+	}
+}
@@ -0,0 +1,99 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Number is the constraint of the values observed by the checks.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// ObservedValue is a typed value observed by a check (free bytes, lag in seconds,
+// connections...), reported in Result.Details: it's rendered in the full output
+// and exported as a healthcheck_check_detail gauge whatever its numeric type,
+// including named types.
+type ObservedValue[T Number] struct {
+	// Value is the observed value.
+	Value T
+	// Unit is the unit of the value (e.g. "bytes"), optional.
+	Unit string
+}
+
+// Observe returns the observed value with its unit.
+func Observe[T Number](value T, unit string) ObservedValue[T] {
+	return ObservedValue[T]{Value: value, Unit: unit}
+}
+
+// Float64 returns the value as a float64, as exported in the metrics.
+func (v ObservedValue[T]) Float64() float64 {
+	return float64(v.Value)
+}
+
+// MarshalJSON renders the value alone, or along with its unit if set.
+func (v ObservedValue[T]) MarshalJSON() ([]byte, error) {
+	if v.Unit == "" {
+		return json.Marshal(v.Value)
+	}
+	return json.Marshal(struct {
+		Value T      `json:"value"`
+		Unit  string `json:"unit"`
+	}{Value: v.Value, Unit: v.Unit})
+}
+
+// unit returns the unit of the value.
+func (v ObservedValue[T]) unit() string {
+	return v.Unit
+}
+
+// observation is a detail exported as a metric, implemented by ObservedValue.
+type observation interface {
+	Float64() float64
+	unit() string
+}
+
+// observer is the Checker returned by Observer.
+type observer[T Number] struct {
+	name     string
+	detail   string
+	unit     string
+	observe  func(ctx context.Context) (T, error)
+	validate func(T) error
+}
+
+// Observer returns a Checker reporting the value returned by observe as the
+// detail, failing if observe fails or validate (if not nil) rejects the value:
+//
+//	handler.AddReadinessChecker(healthcheck.Observer("replication", "lag", "seconds",
+//		replicationLag,
+//		func(lag float64) error {
+//			if lag > 30 {
+//				return errors.New("replica lagging")
+//			}
+//			return nil
+//		}))
+func Observer[T Number](
+	name, detail, unit string, observe func(ctx context.Context) (T, error), validate func(T) error,
+) Checker {
+	return observer[T]{name: name, detail: detail, unit: unit, observe: observe, validate: validate}
+}
+
+func (o observer[T]) Name() string {
+	return o.name
+}
+
+func (o observer[T]) Check(ctx context.Context) Result {
+	value, err := o.observe(ctx)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	res := Result{Details: map[string]any{o.detail: Observe(value, o.unit)}}
+	if o.validate != nil {
+		res.Err = o.validate(value)
+	}
+	return res
+}
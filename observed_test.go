@@ -0,0 +1,46 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type byteCount uint64
+
+func TestObserver(t *testing.T) {
+	h := NewHandler(WithOpenMetrics(""))
+	h.AddReadinessChecker(Observer("disk", "free", "bytes",
+		func(context.Context) (byteCount, error) { return 512, nil },
+		func(free byteCount) error {
+			if free < 1024 {
+				return errors.New("disk almost full")
+			}
+			return nil
+		}))
+	h.AddReadinessChecker(Observer("replication", "lag", "",
+		func(context.Context) (float64, error) { return 1.5, nil }, nil))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1&compact=1", nil))
+
+	expectBody := `{"disk":{"status":"disk almost full","kind":"failed","details":{"free":{"value":512,"unit":"bytes"}}},` +
+		`"replication":{"status":"OK","kind":"ok","details":{"lag":1.5}}}` + "\n"
+	if rr.Body.String() != expectBody {
+		t.Errorf("Wrong body\n"+"expected: %v\n"+"actual  : %v", expectBody, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, OpenMetricsHandlerPath, nil))
+	for _, series := range []string{
+		`healthcheck_check_detail{check="disk",probe="readiness",detail="free"} 512`,
+		`healthcheck_check_detail{check="replication",probe="readiness",detail="lag"} 1.5`,
+	} {
+		if !strings.Contains(rr.Body.String(), series) {
+			t.Errorf("Missing series %s in metrics:\n%s", series, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIInfo is the info object of the OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPI returns an OpenAPI 3 document (JSON) describing the endpoints of the handler,
+// their parameters and responses, so they can be included in API catalogs:
+//
+//	spec, err := healthcheck.OpenAPI(handler, healthcheck.OpenAPIInfo{Title: "orders health", Version: "1.0.0"})
+//
+// Only the endpoints registered by the handler itself are described.
+func OpenAPI(h Handler, info OpenAPIInfo) ([]byte, error) {
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: openAPISchemas,
+		},
+	}
+
+	for _, route := range h.Routes() {
+		op := route.operation
+		for _, name := range pathParameters(route.Path) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   openAPISchema{"type": "string"},
+			})
+		}
+
+		method := strings.ToLower(route.Method)
+		if method == "" {
+			method = "get"
+		}
+		if doc.Paths[route.Path] == nil {
+			doc.Paths[route.Path] = make(map[string]openAPIOperation)
+		}
+		doc.Paths[route.Path][method] = op
+	}
+
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// pathParameters returns the names of the "{name}" parameters of the path.
+func pathParameters(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.TrimSuffix(segment[1:len(segment)-1], "..."))
+		}
+	}
+	return names
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       OpenAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]openAPISchema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	Summary    string                     `json:"summary"`
+	Parameters []openAPIParameter         `json:"parameters,omitempty"`
+	Responses  map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Schema      openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema openAPISchema `json:"schema"`
+}
+
+type openAPISchema map[string]any
+
+// openAPISchemas are the schemas of the responses of the endpoints.
+var openAPISchemas = map[string]openAPISchema{
+	"CheckResults": {
+		"type":        "object",
+		"description": "Results of the checks by name, with ?full=1 only.",
+		"additionalProperties": openAPISchema{
+			"oneOf": []openAPISchema{
+				{"type": "string", "description": "OK or the error of the check."},
+				{
+					"type":     "object",
+					"required": []string{"status"},
+					"properties": map[string]openAPISchema{
+						"status": {"type": "string"},
+						"kind": {
+							"type": "string",
+							"enum": []string{"ok", "failed", "timeout", "panicked", "skipped", "suppressed", "stale", "pending"},
+						},
+						"code":             {"type": "string"},
+						"duration_seconds": {"type": "number", "description": "With ?timing=1 only."},
+						"details":          {"type": "object"},
+					},
+				},
+			},
+		},
+	},
+	"Score": {
+		"type": "object",
+		"properties": map[string]openAPISchema{
+			"score":     {"type": "number"},
+			"threshold": {"type": "number"},
+		},
+	},
+	"Graph": {
+		"type": "object",
+		"properties": map[string]openAPISchema{
+			"nodes": {"type": "array", "items": openAPISchema{"type": "object"}},
+			"edges": {"type": "array", "items": openAPISchema{"type": "object"}},
+		},
+	},
+	"Stats": {
+		"type":        "object",
+		"description": "Availability of the checks tracked by WithSLO and history of the checks set up with WithHistory by name.",
+		"additionalProperties": openAPISchema{
+			"type": "object",
+			"properties": map[string]openAPISchema{
+				"executions":       {"type": "integer"},
+				"failures":         {"type": "integer"},
+				"availability":     {"type": "number"},
+				"target":           {"type": "number"},
+				"window_seconds":   {"type": "number"},
+				"burn_rate":        {"type": "number"},
+				"budget_remaining": {"type": "number"},
+				"history": {
+					"type":        "object",
+					"description": "Recent values of the numeric details by name, oldest first.",
+					"additionalProperties": openAPISchema{
+						"type": "array",
+						"items": openAPISchema{
+							"type": "object",
+							"properties": map[string]openAPISchema{
+								"time":  {"type": "string", "format": "date-time"},
+								"value": {"type": "number"},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+	"Callers": {
+		"type":        "object",
+		"description": "Number of requests to the liveness and readiness probes by caller.",
+		"additionalProperties": openAPISchema{
+			"type":                 "object",
+			"additionalProperties": openAPISchema{"type": "integer"},
+		},
+	},
+	"Diff": {
+		"type": "object",
+		"properties": map[string]openAPISchema{
+			"since":     {"type": "string", "format": "date-time"},
+			"time":      {"type": "string", "format": "date-time"},
+			"failing":   {"type": "array", "items": schemaRef("CheckChange")},
+			"recovered": {"type": "array", "items": schemaRef("CheckChange")},
+		},
+	},
+	"CheckChange": {
+		"type": "object",
+		"properties": map[string]openAPISchema{
+			"check": {"type": "string"},
+			"error": {"type": "string"},
+			"time":  {"type": "string", "format": "date-time"},
+		},
+	},
+	"HealthJSON": {
+		"type":     "object",
+		"required": []string{"status"},
+		"properties": map[string]openAPISchema{
+			"status": {"type": "string", "enum": []string{"pass", "warn", "fail"}},
+			"output": {"type": "string"},
+			"checks": {
+				"type":                 "object",
+				"description":          "With ?full=1 only.",
+				"additionalProperties": openAPISchema{"type": "array", "items": openAPISchema{"type": "object"}},
+			},
+		},
+	},
+	"Schemas": {
+		"type":                 "object",
+		"description":          "JSON Schemas of the response formats by format.",
+		"additionalProperties": openAPISchema{"type": "object"},
+	},
+	"Config": {"type": "object"},
+}
+
+// schemaRef returns a reference to the named schema of openAPISchemas.
+func schemaRef(name string) openAPISchema {
+	return openAPISchema{"$ref": "#/components/schemas/" + name}
+}
+
+// probeOperation describes a probe endpoint.
+func probeOperation(summary string) openAPIOperation {
+	results := map[string]openAPIMediaType{
+		"application/json":             {Schema: schemaRef("CheckResults")},
+		"application/health+json":      {Schema: schemaRef("HealthJSON")},
+		"text/plain":                   {Schema: openAPISchema{"type": "string"}},
+		"application/openmetrics-text": {Schema: openAPISchema{"type": "string"}},
+		"text/html":                    {Schema: openAPISchema{"type": "string"}},
+	}
+	return openAPIOperation{
+		Summary: summary,
+		Parameters: []openAPIParameter{
+			{
+				Name:        "full",
+				In:          "query",
+				Description: "Set to 1 to get the results of the checks.",
+				Schema:      openAPISchema{"type": "string", "enum": []string{"1"}},
+			},
+			{
+				Name:        "compact",
+				In:          "query",
+				Description: "Set to 1 to get the results unindented.",
+				Schema:      openAPISchema{"type": "string", "enum": []string{"1"}},
+			},
+			{
+				Name:        "timing",
+				In:          "query",
+				Description: "Set to 1 to get the duration of each check.",
+				Schema:      openAPISchema{"type": "string", "enum": []string{"1"}},
+			},
+			{
+				Name:        "format",
+				In:          "query",
+				Description: "Output format, overriding the Accept header.",
+				Schema:      openAPISchema{"type": "string", "enum": []string{"json", "health+json", "text", "openmetrics", "html"}},
+			},
+		},
+		Responses: map[string]openAPIResponse{
+			"200": {Description: "The probe passes.", Content: results},
+			"503": {Description: "The probe fails.", Content: results},
+			"406": {Description: "Unknown format."},
+		},
+	}
+}
+
+// jsonOperation describes an endpoint responding with the named schema.
+func jsonOperation(summary, schema string, statuses ...int) openAPIOperation {
+	op := openAPIOperation{
+		Summary:   summary,
+		Responses: make(map[string]openAPIResponse),
+	}
+	for _, status := range append([]int{http.StatusOK}, statuses...) {
+		op.Responses[strconv.Itoa(status)] = openAPIResponse{
+			Description: http.StatusText(status) + ".",
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: schemaRef(schema)},
+			},
+		}
+	}
+	return op
+}
+
+// textOperation describes an endpoint responding with the given content type.
+func textOperation(summary, contentType string) openAPIOperation {
+	return openAPIOperation{
+		Summary: summary,
+		Responses: map[string]openAPIResponse{
+			"200": {
+				Description: "OK.",
+				Content: map[string]openAPIMediaType{
+					contentType: {Schema: openAPISchema{"type": "string"}},
+				},
+			},
+		},
+	}
+}
+
+// actionOperation describes an admin endpoint without response body.
+func actionOperation(summary string, statuses ...int) openAPIOperation {
+	op := openAPIOperation{
+		Summary:   summary,
+		Responses: make(map[string]openAPIResponse),
+	}
+	for _, status := range statuses {
+		op.Responses[strconv.Itoa(status)] = openAPIResponse{Description: http.StatusText(status) + "."}
+	}
+	return op
+}
+
+// adminOperation adds the response of the requests rejected by WithAdminAuth to op.
+func adminOperation(op openAPIOperation) openAPIOperation {
+	op.Responses[strconv.Itoa(http.StatusForbidden)] = openAPIResponse{Description: "Rejected by the admin auth."}
+	return op
+}
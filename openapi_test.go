@@ -0,0 +1,67 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	h := NewHandler(WithAdminEndpoints(), WithOpenMetrics(""))
+
+	spec, err := OpenAPI(h, OpenAPIInfo{Title: "health", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("Wrong version\n"+"expected: %v\n"+"actual  : %v", "3.0.3", doc.OpenAPI)
+	}
+
+	tests := []struct {
+		path     string
+		method   string
+		param    string
+		response string
+	}{
+		{path: LivenessHandlerPath, method: "get", param: "full", response: "503"},
+		{path: ReadinessHandlerPath, method: "get", param: "compact", response: "200"},
+		{path: GraphHandlerPath, method: "get", param: "format", response: "200"},
+		{path: OpenMetricsHandlerPath, method: "get", response: "200"},
+		{path: AdminHandlerPath + "/checks/{name}/disable", method: "post", param: "name", response: "404"},
+		{path: AdminHandlerPath + "/readiness", method: "delete", response: "403"},
+	}
+
+	for _, tt := range tests {
+		op, ok := doc.Paths[tt.path][tt.method]
+		if !ok {
+			t.Errorf("Missing operation %s %s", tt.method, tt.path)
+			continue
+		}
+		if _, ok := op.Responses[tt.response]; !ok {
+			t.Errorf("Missing response %s of %s %s", tt.response, tt.method, tt.path)
+		}
+		if tt.param == "" {
+			continue
+		}
+		var found bool
+		for _, param := range op.Parameters {
+			found = found || param.Name == tt.param
+		}
+		if !found {
+			t.Errorf("Missing parameter %s of %s %s", tt.param, tt.method, tt.path)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenMetricsHandlerPath default path to the OpenMetrics exposition of the checks.
+const OpenMetricsHandlerPath = "/health/metrics"
+
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WithOpenMetrics exposes the current state of the checks in the OpenMetrics
+// text format on path (OpenMetricsHandlerPath if empty), so it can be scraped
+// without the Prometheus client. The following gauges are exposed:
+//
+//	healthcheck_check_up{check,probe}                1 if the check passes, 0 otherwise
+//	healthcheck_check_pending{check,probe}           1 if the check hasn't been evaluated yet
+//	healthcheck_check_result{check,probe,kind}       1 with the ResultKind of the last execution
+//	healthcheck_check_duration_seconds{check,probe}  duration of the last execution
+//	healthcheck_check_detail{check,probe,detail}     numeric details reported by a Checker
+//	healthcheck_probe_up{probe}                      1 if the probe passes, 0 otherwise
+//
+// along with the healthcheck_probe_requests_total{probe,caller} counter
+// of the probe requests by caller (see ClassifyCaller).
+func WithOpenMetrics(path string) Option {
+	return func(h *basicHandler) {
+		if path == "" {
+			path = OpenMetricsHandlerPath
+		}
+		h.openMetricsPath = path
+	}
+}
+
+// registerOpenMetrics registers the OpenMetrics endpoint on the mux if it's enabled.
+func (s *basicHandler) registerOpenMetrics() {
+	if s.openMetricsPath == "" {
+		return
+	}
+	s.route(s.openMetricsPath, textOperation("Checks in the OpenMetrics text format", openMetricsContentType), s.OpenMetricsEndpoint)
+}
+
+// OpenMetricsEndpoint is an HTTP handler exposing the checks in the OpenMetrics text format.
+func (s *basicHandler) OpenMetricsEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) || !s.refresh(w, r, s.readinessChecks, s.livenessChecks) {
+		return
+	}
+
+	var (
+		liveness  = s.entries(s.livenessChecks)
+		readiness = s.entries(s.readinessChecks)
+		all       = s.entries(s.readinessChecks, s.livenessChecks)
+		results   = s.collectChecks(all)
+		probe     = s.entries(s.readinessProbe...)
+	)
+
+	probes := []struct {
+		name   string
+		checks []*checkEntry
+		status int
+	}{
+		{name: "liveness", checks: liveness, status: s.probeStatus(liveness, results, false)},
+		{name: "readiness", checks: readiness, status: s.readinessOverride.apply(s.probeStatus(probe, results, true))},
+	}
+	for _, probe := range probes {
+		sort.Slice(probe.checks, func(i, j int) bool { return probe.checks[i].name < probe.checks[j].name })
+	}
+
+	var b strings.Builder
+
+	writeMetricFamily(&b, "healthcheck_check_up", "Whether the check passes (1) or fails (0).")
+	for _, probe := range probes {
+		for _, entry := range probe.checks {
+			fmt.Fprintf(&b, "healthcheck_check_up{%s} %d\n",
+				checkLabels(entry, probe.name), boolGauge(!checkFailed(results[entry.name].Err)))
+		}
+	}
+
+	writeMetricFamily(&b, "healthcheck_check_result", "Kind of outcome of the last check execution, always 1.")
+	for _, probe := range probes {
+		for _, entry := range probe.checks {
+			fmt.Fprintf(&b, "healthcheck_check_result{%s,kind=\"%s\"} 1\n",
+				checkLabels(entry, probe.name), results[entry.name].Kind())
+		}
+	}
+
+	writeMetricFamily(&b, "healthcheck_check_pending", "Whether the check hasn't been evaluated yet (1) or has (0).")
+	for _, probe := range probes {
+		for _, entry := range probe.checks {
+			fmt.Fprintf(&b, "healthcheck_check_pending{%s} %d\n",
+				checkLabels(entry, probe.name), boolGauge(errors.Is(results[entry.name].Err, ErrPending)))
+		}
+	}
+
+	writeMetricFamily(&b, "healthcheck_check_duration_seconds", "Duration of the last check execution in seconds.")
+	for _, probe := range probes {
+		for _, entry := range probe.checks {
+			fmt.Fprintf(&b, "healthcheck_check_duration_seconds{%s} %s\n",
+				checkLabels(entry, probe.name), strconv.FormatFloat(results[entry.name].Duration.Seconds(), 'f', -1, 64))
+		}
+	}
+
+	writeMetricFamily(&b, "healthcheck_check_detail", "Numeric value observed by the check.")
+	for _, probe := range probes {
+		for _, entry := range probe.checks {
+			metrics := results[entry.name].Metrics()
+			keys := make([]string, 0, len(metrics))
+			for key := range metrics {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				fmt.Fprintf(&b, "healthcheck_check_detail{%s,detail=\"%s\"} %s\n",
+					checkLabels(entry, probe.name), escapeLabel(key), strconv.FormatFloat(metrics[key], 'f', -1, 64))
+			}
+		}
+	}
+
+	slos := s.sloStats()
+	for _, family := range []struct {
+		name  string
+		help  string
+		value func(SLOStats) float64
+	}{
+		{"healthcheck_check_availability", "Share of passed executions of the check over its SLO window.", func(st SLOStats) float64 { return st.Availability }},
+		{"healthcheck_check_slo_target", "Availability objective of the check.", func(st SLOStats) float64 { return st.Target }},
+		{"healthcheck_check_error_budget_burn_rate", "Rate the error budget of the check is consumed at.", func(st SLOStats) float64 { return st.BurnRate }},
+		{"healthcheck_check_error_budget_remaining", "Share of the error budget of the check left in its SLO window.", func(st SLOStats) float64 { return st.BudgetRemaining }},
+	} {
+		if len(slos) == 0 {
+			break
+		}
+		writeMetricFamily(&b, family.name, family.help)
+		for _, probe := range probes {
+			for _, entry := range probe.checks {
+				if stats, ok := slos[entry.name]; ok {
+					fmt.Fprintf(&b, "%s{%s} %s\n",
+						family.name, checkLabels(entry, probe.name), strconv.FormatFloat(family.value(stats), 'f', -1, 64))
+				}
+			}
+		}
+	}
+
+	writeMetricFamily(&b, "healthcheck_probe_up", "Whether the probe passes (1) or fails (0).")
+	for _, probe := range probes {
+		fmt.Fprintf(&b, "healthcheck_probe_up{probe=\"%s\"} %d\n", probe.name, boolGauge(probe.status == http.StatusOK))
+	}
+
+	if callers := s.callers.snapshot(); len(callers) > 0 {
+		b.WriteString("# HELP healthcheck_probe_requests Number of probe requests by caller.\n# TYPE healthcheck_probe_requests counter\n")
+		for _, probe := range []string{"liveness", "readiness"} {
+			names := make([]string, 0, len(callers[probe]))
+			for caller := range callers[probe] {
+				names = append(names, string(caller))
+			}
+			sort.Strings(names)
+
+			for _, caller := range names {
+				fmt.Fprintf(&b, "healthcheck_probe_requests_total{probe=\"%s\",caller=\"%s\"} %d\n",
+					probe, escapeLabel(caller), callers[probe][Caller(caller)])
+			}
+		}
+	}
+
+	b.WriteString("# EOF\n")
+
+	w.Header().Set("Content-Type", openMetricsContentType)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func writeMetricFamily(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func boolGauge(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// checkLabels returns the labels of the series of a check: its name,
+// probe and the labels set by WithLabels, sorted.
+func checkLabels(entry *checkEntry, probe string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "check=\"%s\",probe=\"%s\"", escapeLabel(entry.name), probe)
+
+	keys := make([]string, 0, len(entry.config.labels))
+	for key := range entry.config.labels {
+		if key != "check" && key != "probe" && key != "detail" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(&b, ",%s=\"%s\"", labelName(key), escapeLabel(entry.config.labels[key]))
+	}
+	return b.String()
+}
+
+// labelName replaces the characters not allowed in a label name.
+func labelName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// escapeLabel escapes a label value as required by the OpenMetrics text format.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
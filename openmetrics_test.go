@@ -0,0 +1,96 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenMetricsEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		expect []string
+	}{
+		{
+			name: "default path",
+			path: "",
+			expect: []string{
+				"# TYPE healthcheck_check_up gauge\n",
+				"healthcheck_check_up{check=\"live\",probe=\"liveness\"} 1\n",
+				"healthcheck_check_up{check=\"database \\\"main\\\"\",probe=\"readiness\"} 0\n",
+				"# TYPE healthcheck_check_duration_seconds gauge\n",
+				"healthcheck_probe_up{probe=\"liveness\"} 1\n",
+				"healthcheck_probe_up{probe=\"readiness\"} 0\n",
+			},
+		},
+		{
+			name: "custom path",
+			path: "/metrics",
+			expect: []string{
+				"# HELP healthcheck_probe_up Whether the probe passes (1) or fails (0).\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(WithOpenMetrics(tt.path))
+			h.AddLivenessCheck("live", func() error { return nil })
+			h.AddReadinessCheck("database \"main\"", func() error { return errors.New("failed") })
+
+			path := tt.path
+			if path == "" {
+				path = OpenMetricsHandlerPath
+			}
+			req, err := http.NewRequest(http.MethodGet, path, nil)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != openMetricsContentType {
+				t.Errorf("Wrong content type\n"+"expected: %v\n"+"actual  : %v", openMetricsContentType, ct)
+			}
+
+			body := rr.Body.String()
+			for _, expect := range tt.expect {
+				if !strings.Contains(body, expect) {
+					t.Errorf("Missing line\n"+"expected: %q\n"+"actual  : %q", expect, body)
+				}
+			}
+			if !strings.HasSuffix(body, "# EOF\n") {
+				t.Errorf("Missing EOF marker\n"+"actual  : %q", body)
+			}
+		})
+	}
+}
+
+func TestOpenMetricsLabels(t *testing.T) {
+	h := NewHandler(WithOpenMetrics(""))
+	h.AddReadinessCheck("database", func() error { return nil },
+		WithLabels(map[string]string{"team": "payments", "tier-1": "yes", "probe": "ignored"}))
+
+	req, err := http.NewRequest(http.MethodGet, OpenMetricsHandlerPath, nil)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	expect := "healthcheck_check_up{check=\"database\",probe=\"readiness\",team=\"payments\",tier_1=\"yes\"} 1\n"
+	if !strings.Contains(rr.Body.String(), expect) {
+		t.Errorf("Missing line\n"+"expected: %q\n"+"actual  : %q", expect, rr.Body.String())
+	}
+}
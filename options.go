@@ -0,0 +1,22 @@
+package healthcheck
+
+import "context"
+
+// Option configures a Handler created by NewHandler.
+type Option func(h *basicHandler)
+
+// WithContext sets the context bounding the lifetime of the handler:
+// background checks stop being executed once it's done.
+func WithContext(ctx context.Context) Option {
+	return func(h *basicHandler) {
+		h.ctx = ctx
+	}
+}
+
+// WithIndependentProbes stops including the liveness checks in the readiness
+// probe, so it reflects the readiness checks (external dependencies...) only.
+func WithIndependentProbes() Option {
+	return func(h *basicHandler) {
+		h.independentProbes = true
+	}
+}
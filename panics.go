@@ -0,0 +1,51 @@
+package healthcheck
+
+import "fmt"
+
+// WithPanicLimit disables a check after it has panicked limit times.
+// A disabled check isn't executed anymore and is reported as failed with
+// "disabled after N panics", so a buggy checker can't flood the logs on every probe,
+// until EnableCheck resets its panic count. The counts are exposed by the admin
+// config endpoint. Zero (the default) never disables a check.
+func WithPanicLimit(limit int) Option {
+	return func(h *basicHandler) {
+		h.panicLimit = limit
+	}
+}
+
+// recordPanic increments the panic counter of the check.
+func (s *basicHandler) recordPanic(name string) {
+	s.panicsMutex.Lock()
+	defer s.panicsMutex.Unlock()
+	s.panics[name]++
+}
+
+// panicCount returns the number of panics of the check.
+func (s *basicHandler) panicCount(name string) int {
+	s.panicsMutex.Lock()
+	defer s.panicsMutex.Unlock()
+	return s.panics[name]
+}
+
+// resetPanics resets the panic counter of the check.
+func (s *basicHandler) resetPanics(name string) {
+	s.panicsMutex.Lock()
+	defer s.panicsMutex.Unlock()
+	delete(s.panics, name)
+}
+
+// disabledByPanics returns a non-nil error if the check
+// has reached the panic limit and mustn't be executed.
+func (s *basicHandler) disabledByPanics(name string) error {
+	if s.panicLimit <= 0 {
+		return nil
+	}
+
+	s.panicsMutex.Lock()
+	defer s.panicsMutex.Unlock()
+
+	if count := s.panics[name]; count >= s.panicLimit {
+		return &kindError{msg: fmt.Sprintf("disabled after %d panics", count), kind: ErrSkipped}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPanicLimit(t *testing.T) {
+	var (
+		calls  int
+		errors int
+	)
+
+	h := NewHandler(WithPanicLimit(2))
+	h.AddCheckErrorHandler(func(string, error) { errors++ })
+	h.AddLivenessCheck("panicking", func() error {
+		calls++
+		panic("boom")
+	})
+
+	var body string
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequest(http.MethodGet, LivenessHandlerPath+"?full=1", nil)
+		if err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong code\n"+
+				"expected: %v\n"+
+				"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+		}
+		body = rr.Body.String()
+	}
+
+	if calls != 2 || errors != 2 {
+		t.Errorf("Check must be disabled after 2 panics, got %d calls and %d errors", calls, errors)
+	}
+
+	expectBody := "{\n    \"panicking\": \"disabled after 2 panics\"\n}\n"
+	if body != expectBody {
+		t.Errorf("Wrong body\n"+
+			"expected: %v"+
+			"actual  : %v", expectBody, body)
+	}
+}
+
+func TestPanicLimitEnableCheck(t *testing.T) {
+	var calls int
+
+	h := NewHandler(WithPanicLimit(1), WithAdminEndpoints(), WithAdminAuth(AdminAllowAll))
+	h.AddLivenessCheck("panicking", func() error {
+		calls++
+		panic("boom")
+	})
+
+	panics := func() int {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, AdminHandlerPath+"/config", nil))
+
+		var cfg adminConfig
+		if err := json.Unmarshal(rr.Body.Bytes(), &cfg); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		return cfg.Checks[0].Panics
+	}
+	probe := func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, LivenessHandlerPath, nil))
+	}
+
+	probe()
+	probe()
+	if calls != 1 || panics() != 1 {
+		t.Errorf("Check must be disabled after 1 panic, got %d calls and %d panics", calls, panics())
+	}
+
+	// enabling the check back resets its panic count
+	if err := h.EnableCheck("panicking"); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if n := panics(); n != 0 {
+		t.Errorf("Wrong panics\n"+"expected: %v\n"+"actual  : %v", 0, n)
+	}
+
+	probe()
+	if calls != 2 || panics() != 1 {
+		t.Errorf("Check must run again once enabled, got %d calls and %d panics", calls, panics())
+	}
+}
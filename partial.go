@@ -0,0 +1,52 @@
+package healthcheck
+
+// NonCritical marks the check as non-critical for readiness: its failure only fails
+// the readiness probe when the share or the number of failed non-critical checks
+// exceeds the threshold set by WithNonCriticalFailurePercent or
+// WithNonCriticalFailureCount. Without a threshold it fails readiness as any other check.
+// The liveness probe isn't affected.
+func NonCritical() CheckOption {
+	return func(c *checkConfig) {
+		c.nonCritical = true
+	}
+}
+
+// WithNonCriticalFailurePercent makes readiness fail only if more than percent (0-100)
+// of the non-critical checks fail, so a single optional dependency outage doesn't
+// remove the entire fleet from rotation.
+func WithNonCriticalFailurePercent(percent float64) Option {
+	return func(h *basicHandler) {
+		h.partialReadiness.percent = percent
+		h.partialReadiness.percentSet = true
+	}
+}
+
+// WithNonCriticalFailureCount makes readiness fail only if more than count
+// non-critical checks fail.
+func WithNonCriticalFailureCount(count int) Option {
+	return func(h *basicHandler) {
+		h.partialReadiness.count = count
+		h.partialReadiness.countSet = true
+	}
+}
+
+// partialReadiness is the threshold of failed non-critical checks.
+type partialReadiness struct {
+	percent    float64
+	percentSet bool
+	count      int
+	countSet   bool
+}
+
+// exceeded reports whether failed of total non-critical checks exceed the threshold.
+func (p partialReadiness) exceeded(failed, total int) bool {
+	if failed == 0 {
+		return false
+	}
+	if !p.percentSet && !p.countSet {
+		return true
+	}
+
+	return (p.percentSet && float64(failed)/float64(total)*100 > p.percent) ||
+		(p.countSet && failed > p.count)
+}
@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNonCriticalThreshold(t *testing.T) {
+	failing := func() error { return errors.New("failed") }
+	passing := func() error { return nil }
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		failed      int
+		critical    bool
+		expectLive  int
+		expectReady int
+	}{
+		{
+			name:        "without threshold any failure fails readiness",
+			failed:      1,
+			expectLive:  http.StatusOK,
+			expectReady: http.StatusServiceUnavailable,
+		},
+		{
+			name:        "failures within the percent threshold",
+			opts:        []Option{WithNonCriticalFailurePercent(50)},
+			failed:      2,
+			expectLive:  http.StatusOK,
+			expectReady: http.StatusOK,
+		},
+		{
+			name:        "failures above the percent threshold",
+			opts:        []Option{WithNonCriticalFailurePercent(50)},
+			failed:      3,
+			expectLive:  http.StatusOK,
+			expectReady: http.StatusServiceUnavailable,
+		},
+		{
+			name:        "failures above the count threshold",
+			opts:        []Option{WithNonCriticalFailureCount(1)},
+			failed:      2,
+			expectLive:  http.StatusOK,
+			expectReady: http.StatusServiceUnavailable,
+		},
+		{
+			name:        "critical failure always fails",
+			opts:        []Option{WithNonCriticalFailureCount(1)},
+			critical:    true,
+			expectLive:  http.StatusOK,
+			expectReady: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.opts...)
+			for i, name := range []string{"a", "b", "c", "d"} {
+				check := passing
+				if i < tt.failed {
+					check = failing
+				}
+				h.AddReadinessCheck(name, check, NonCritical())
+			}
+			if tt.critical {
+				h.AddReadinessCheck("critical", failing)
+			}
+
+			for path, expect := range map[string]int{
+				LivenessHandlerPath:  tt.expectLive,
+				ReadinessHandlerPath: tt.expectReady,
+			} {
+				req, err := http.NewRequest(http.MethodGet, path, nil)
+				if err != nil {
+					t.Fatalf("Received unexpected error:\n%+v", err)
+				}
+
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, req)
+				if rr.Code != expect {
+					t.Errorf("Wrong code for %q\n"+
+						"expected: %v\n"+
+						"actual  : %v", path, expect, rr.Code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package healthcheck
+
+import "errors"
+
+// ErrPending is the result of a background check until its first execution
+// completes, distinguishing a check which hasn't been evaluated yet from a
+// failed one.
+var ErrPending = errors.New("pending")
+
+// WithPendingAsPassing makes the checks which haven't been evaluated yet
+// (see ErrPending) count as passing in the probes rather than failing them,
+// e.g. so slow background checks don't delay readiness right after startup.
+// They are still reported as pending in the full output and the metrics.
+// Along with WithStaleAfter, a first execution exceeding the TTL fails the check.
+func WithPendingAsPassing() Option {
+	return func(h *basicHandler) {
+		h.pendingPassing = true
+	}
+}
+
+// pendingFailed reports whether the result fails the probes, taking
+// WithPendingAsPassing into account.
+func (s *basicHandler) pendingFailed(err error) bool {
+	if s.pendingPassing && errors.Is(err, ErrPending) {
+		return false
+	}
+	return checkFailed(err)
+}
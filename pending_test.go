@@ -0,0 +1,44 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithPendingAsPassing(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		expectCode int
+	}{
+		{name: "pending fails", expectCode: http.StatusServiceUnavailable},
+		{name: "pending passes", opts: []Option{WithPendingAsPassing()}, expectCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+
+			h := NewHandler(tt.opts...)
+			h.AddReadinessCheck("warmup", func() error {
+				<-release
+				return nil
+			}, WithSchedule(Every(time.Minute)))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1&compact=1", nil))
+
+			expectBody := `{"warmup":"pending"}` + "\n"
+			if rr.Code != tt.expectCode || rr.Body.String() != expectBody {
+				t.Errorf("Wrong response\n"+"expected: %d %s\n"+"actual  : %d %s", tt.expectCode, expectBody, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// statePersistenceErrorName is the name passed to the error handler
+// when the health state can't be loaded or saved.
+const statePersistenceErrorName = "state_persistence"
+
+// staleSuffix is appended to the results restored from the persisted state in the full output.
+const staleSuffix = " (stale)"
+
+// WithStatePersistence saves the results of every evaluation to the file at path
+// and restores them on startup: until their first execution completes, background
+// checks are served with the persisted result marked as stale instead of failing
+// with ErrPending. It smooths restarts for aggregators
+// treating "no data" as down.
+//
+// The file is only rewritten when the results change. Load and save errors are
+// passed to the error handler as "state_persistence", the load error once
+// the error handler is added.
+func WithStatePersistence(path string) Option {
+	return func(h *basicHandler) {
+		h.state = &persistedState{
+			path:    path,
+			results: make(map[string]persistedResult),
+		}
+	}
+}
+
+// persistedState is the last known health state kept on disk.
+type persistedState struct {
+	path string
+
+	mu       sync.Mutex
+	results  map[string]persistedResult
+	restored map[string]persistedResult
+	// loadErr is the load error, kept until the error handler is added.
+	loadErr error
+}
+
+// persistedFile is the format of the state file.
+type persistedFile struct {
+	Time   time.Time                  `json:"time"`
+	Checks map[string]persistedResult `json:"checks"`
+}
+
+// persistedResult is a persisted check result.
+type persistedResult struct {
+	Error string `json:"error,omitempty"`
+}
+
+func (p persistedResult) result() Result {
+	res := Result{Stale: true}
+	if p.Error != "" {
+		res.Err = errors.New(p.Error)
+	}
+	return res
+}
+
+// loadState restores the persisted state, a missing file isn't an error.
+func (s *basicHandler) loadState() {
+	if s.state == nil {
+		return
+	}
+
+	data, err := os.ReadFile(s.state.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+
+	var file persistedFile
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		// the error handler can only be added once the handler is created
+		s.state.loadErr = err
+		return
+	}
+
+	s.state.restored = file.Checks
+	for name, res := range file.Checks {
+		s.state.results[name] = res
+	}
+}
+
+// restoredResult returns the persisted result of the check, if any.
+func (s *basicHandler) restoredResult(name string) (Result, bool) {
+	if s.state == nil {
+		return Result{}, false
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	res, ok := s.state.restored[name]
+	if !ok {
+		return Result{}, false
+	}
+	return res.result(), true
+}
+
+// saveState persists the results of an evaluation.
+func (s *basicHandler) saveState(results map[string]Result) {
+	if s.state == nil {
+		return
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	changed := false
+	for name, res := range results {
+		if res.Stale || errors.Is(res.Err, ErrPending) || errors.Is(res.Err, ErrCheckDisabled) {
+			continue
+		}
+
+		var persisted persistedResult
+		if res.Err != nil {
+			persisted.Error = res.Err.Error()
+		}
+		if previous, ok := s.state.results[name]; !ok || previous != persisted {
+			s.state.results[name] = persisted
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	data, err := json.Marshal(persistedFile{Time: s.clock.Now(), Checks: s.state.results})
+	if err == nil {
+		err = writeFileAtomic(s.state.path, data)
+	}
+	if err != nil {
+		s.stateError(err)
+	}
+}
+
+// reportLoadError passes the load error of the state to the error handler, once.
+func (s *basicHandler) reportLoadError() {
+	if s.state == nil {
+		return
+	}
+
+	s.state.mu.Lock()
+	err := s.state.loadErr
+	s.state.loadErr = nil
+	s.state.mu.Unlock()
+
+	if err != nil {
+		s.stateError(err)
+	}
+}
+
+func (s *basicHandler) stateError(err error) {
+	if s.errorHandler != nil {
+		s.errorHandler(statePersistenceErrorName, err)
+	}
+}
+
+// writeFileAtomic writes the file through a temporary one, so a crash
+// never leaves a truncated state behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,106 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := `{"time":"2024-01-01T00:00:00Z","checks":{"database":{},"cache":{"error":"connection refused"}}}`
+	if err := os.WriteFile(path, []byte(state), 0o600); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	release := make(chan struct{})
+	h := NewHandler(WithStatePersistence(path))
+	h.AddReadinessCheck("database", func() error {
+		<-release
+		return nil
+	}, WithSchedule(Every(time.Hour)))
+	h.AddReadinessCheck("cache", func() error { return errors.New("timeout") })
+
+	req, err := http.NewRequest(http.MethodGet, "/ready?full=1", nil)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	expectBody := "{\n    \"cache\": \"timeout\",\n    \"database\": \"OK (stale)\"\n}\n"
+	if rr.Body.String() != expectBody {
+		t.Errorf("Wrong body\n"+"expected: %q\n"+"actual  : %q", expectBody, rr.Body.String())
+	}
+
+	var file persistedFile
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	expect := map[string]persistedResult{
+		"database": {},
+		"cache":    {Error: "timeout"},
+	}
+	for name, res := range expect {
+		if file.Checks[name] != res {
+			t.Errorf("Wrong persisted result of %q\n"+"expected: %v\n"+"actual  : %v", name, res, file.Checks[name])
+		}
+	}
+
+	close(release)
+}
+
+func TestStatePersistenceLoadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{corrupt"), 0o600); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	h := NewHandler(WithStatePersistence(path))
+
+	var reported []string
+	h.AddCheckErrorHandler(func(name string, _ error) {
+		reported = append(reported, name)
+	})
+	h.AddCheckErrorHandler(func(name string, _ error) {
+		reported = append(reported, name)
+	})
+
+	if len(reported) != 1 || reported[0] != statePersistenceErrorName {
+		t.Errorf("Wrong reported errors\n"+"expected: %v\n"+"actual  : %v", []string{statePersistenceErrorName}, reported)
+	}
+}
+
+func TestStatePersistenceUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	h := NewHandler(WithStatePersistence(path))
+	h.AddReadinessCheck("database", func() error { return nil })
+
+	ready := func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	}
+
+	ready()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	// the same results aren't written again
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	ready()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Unexpected write of unchanged results: %v", err)
+	}
+}
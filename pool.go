@@ -0,0 +1,61 @@
+package healthcheck
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// emptyBody is the body of the probes without ?full=1.
+var emptyBody = []byte("{}\n")
+
+// The pools below reuse the per request allocations of the probe endpoints,
+// which are noticeable when a large fleet is probed every second.
+var (
+	entriesPool = sync.Pool{
+		New: func() any {
+			entries := make([]*checkEntry, 0, 16)
+			return &entries
+		},
+	}
+	resultsPool = sync.Pool{
+		New: func() any {
+			return make(map[string]Result)
+		},
+	}
+	writerPool = sync.Pool{
+		New: func() any {
+			return bufio.NewWriter(nil)
+		},
+	}
+)
+
+func acquireEntries() *[]*checkEntry {
+	return entriesPool.Get().(*[]*checkEntry)
+}
+
+func releaseEntries(entries *[]*checkEntry) {
+	clear(*entries)
+	*entries = (*entries)[:0]
+	entriesPool.Put(entries)
+}
+
+func acquireResults() map[string]Result {
+	return resultsPool.Get().(map[string]Result)
+}
+
+func releaseResults(results map[string]Result) {
+	clear(results)
+	resultsPool.Put(results)
+}
+
+func acquireWriter(w io.Writer) *bufio.Writer {
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+func releaseWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	writerPool.Put(bw)
+}
@@ -0,0 +1,214 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/probe"
+)
+
+const (
+	// DefaultInterval is the default interval between pushes.
+	DefaultInterval = 30 * time.Second
+	// DefaultMinBackoff is the default delay before retrying a failed push.
+	DefaultMinBackoff = time.Second
+	// DefaultMaxBackoff is the default maximum delay before retrying a failed push.
+	DefaultMaxBackoff = 5 * time.Minute
+)
+
+// Snapshot is the health state pushed to the collector.
+type Snapshot struct {
+	Service   string            `json:"service,omitempty"`
+	Instance  string            `json:"instance"`
+	Timestamp time.Time         `json:"timestamp"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Live      ProbeSnapshot     `json:"live"`
+	Ready     ProbeSnapshot     `json:"ready"`
+}
+
+// ProbeSnapshot is the state of a single probe.
+type ProbeSnapshot struct {
+	// Status is "pass" or "fail".
+	Status string `json:"status"`
+	// Checks are the results of the checks by name.
+	Checks map[string]string `json:"checks"`
+}
+
+// Reporter periodically POSTs the full health snapshot of the handler as JSON
+// to a central collector, for environments where the instances can't be probed
+// across network boundaries. Failed pushes are retried with exponential backoff.
+type Reporter struct {
+	handler    healthcheck.Prober
+	url        string
+	client     *http.Client
+	interval   time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	header     http.Header
+	service    string
+	instance   string
+	labels     map[string]string
+}
+
+// Option configures a Reporter.
+type Option func(r *Reporter)
+
+// WithInterval sets the interval between pushes, DefaultInterval by default.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Reporter) {
+		r.interval = interval
+	}
+}
+
+// WithBackoff sets the bounds of the exponential backoff of failed pushes,
+// DefaultMinBackoff and DefaultMaxBackoff by default.
+func WithBackoff(minBackoff, maxBackoff time.Duration) Option {
+	return func(r *Reporter) {
+		r.minBackoff, r.maxBackoff = minBackoff, maxBackoff
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to push, e.g. for mTLS.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Reporter) {
+		r.client = client
+	}
+}
+
+// WithHeader adds a header to every push.
+func WithHeader(key, value string) Option {
+	return func(r *Reporter) {
+		r.header.Add(key, value)
+	}
+}
+
+// WithBearerToken authenticates the pushes with the bearer token.
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithSource identifies the pushing instance, the instance is the hostname by default.
+func WithSource(service, instance string) Option {
+	return func(r *Reporter) {
+		r.service, r.instance = service, instance
+	}
+}
+
+// WithLabels attaches static labels (region, cluster, etc.) to every snapshot.
+func WithLabels(labels map[string]string) Option {
+	return func(r *Reporter) {
+		r.labels = labels
+	}
+}
+
+// NewReporter creates a Reporter pushing the state of the handler to the URL.
+func NewReporter(h healthcheck.Prober, url string, opts ...Option) *Reporter {
+	hostname, _ := os.Hostname()
+
+	r := &Reporter{
+		handler:    h,
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		interval:   DefaultInterval,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		header:     make(http.Header),
+		instance:   hostname,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run pushes the snapshot every interval until the context is done.
+func (r *Reporter) Run(ctx context.Context) {
+	backoff := time.Duration(0)
+
+	for {
+		delay := r.interval
+		if err := r.Push(ctx); err != nil {
+			backoff = r.nextBackoff(backoff)
+			delay = backoff
+		} else {
+			backoff = 0
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// nextBackoff doubles the previous backoff within the bounds, adding up to 10% of jitter.
+func (r *Reporter) nextBackoff(prev time.Duration) time.Duration {
+	next := min(max(prev*2, r.minBackoff), r.maxBackoff)
+	if jitter := next / 10; jitter > 0 {
+		next += rand.N(jitter)
+	}
+	return next
+}
+
+// Snapshot evaluates the probes of the handler.
+func (r *Reporter) Snapshot() Snapshot {
+	return Snapshot{
+		Service:   r.service,
+		Instance:  r.instance,
+		Timestamp: time.Now().UTC(),
+		Labels:    r.labels,
+		Live:      probeSnapshot(probe.Live(r.handler)),
+		Ready:     probeSnapshot(probe.Ready(r.handler)),
+	}
+}
+
+func probeSnapshot(result probe.Result) ProbeSnapshot {
+	status := "fail"
+	if result.Healthy() {
+		status = "pass"
+	}
+	return ProbeSnapshot{
+		Status: status,
+		Checks: result.Checks,
+	}
+}
+
+// Push sends the current snapshot once.
+func (r *Reporter) Push(ctx context.Context) error {
+	body, err := json.Marshal(r.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// fakeCollector records the pushed snapshots, failing the first failures pushes.
+type fakeCollector struct {
+	mu        sync.Mutex
+	failures  int
+	attempts  int
+	snapshots []Snapshot
+	pushed    chan struct{}
+}
+
+func (c *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attempts++
+	if c.attempts <= c.failures {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.snapshots = append(c.snapshots, snapshot)
+	w.WriteHeader(http.StatusAccepted)
+
+	select {
+	case c.pushed <- struct{}{}:
+	default:
+	}
+}
+
+func TestPush(t *testing.T) {
+	collector := &fakeCollector{pushed: make(chan struct{}, 1)}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	h := healthcheck.NewHandler()
+	h.AddReadinessCheck("database", func() error { return errors.New("connection refused") })
+
+	r := NewReporter(h, srv.URL,
+		WithBearerToken("secret"),
+		WithSource("api", "api-1"),
+		WithLabels(map[string]string{"region": "eu"}),
+	)
+	if err := r.Push(context.Background()); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	if len(collector.snapshots) != 1 {
+		t.Fatalf("Wrong number of snapshots\n"+"expected: %v\n"+"actual  : %v", 1, len(collector.snapshots))
+	}
+	snapshot := collector.snapshots[0]
+	if snapshot.Service != "api" || snapshot.Instance != "api-1" || snapshot.Labels["region"] != "eu" {
+		t.Errorf("Wrong source: %+v", snapshot)
+	}
+	if snapshot.Live.Status != "pass" {
+		t.Errorf("Wrong liveness status\n"+"expected: %v\n"+"actual  : %v", "pass", snapshot.Live.Status)
+	}
+	if snapshot.Ready.Status != "fail" || snapshot.Ready.Checks["database"] != "connection refused" {
+		t.Errorf("Wrong readiness snapshot: %+v", snapshot.Ready)
+	}
+}
+
+func TestPushError(t *testing.T) {
+	srv := httptest.NewServer(&fakeCollector{failures: 1})
+	defer srv.Close()
+
+	err := NewReporter(healthcheck.NewHandler(), srv.URL, WithBearerToken("secret")).Push(context.Background())
+	if err == nil || err.Error() != "collector returned status 502" {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", "collector returned status 502", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	collector := &fakeCollector{failures: 2, pushed: make(chan struct{}, 1)}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	// the failed pushes are retried with the backoff rather than the interval
+	r := NewReporter(healthcheck.NewHandler(), srv.URL,
+		WithBearerToken("secret"),
+		WithInterval(time.Hour),
+		WithBackoff(time.Millisecond, 2*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(ctx)
+	}()
+
+	select {
+	case <-collector.pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Snapshot not pushed after the failures")
+	}
+	cancel()
+	<-done
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if collector.attempts != 3 {
+		t.Errorf("Wrong number of attempts\n"+"expected: %v\n"+"actual  : %v", 3, collector.attempts)
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	r := NewReporter(healthcheck.NewHandler(), "", WithBackoff(time.Second, 4*time.Second))
+
+	tests := []struct {
+		prev   time.Duration
+		expect time.Duration
+	}{
+		{prev: 0, expect: time.Second},
+		{prev: time.Second, expect: 2 * time.Second},
+		{prev: 2 * time.Second, expect: 4 * time.Second},
+		{prev: 4 * time.Second, expect: 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		// up to 10% of jitter is added
+		if next := r.nextBackoff(tt.prev); next < tt.expect || next > tt.expect+tt.expect/10 {
+			t.Errorf("Wrong backoff after %v\n"+"expected: %v\n"+"actual  : %v", tt.prev, tt.expect, next)
+		}
+	}
+}
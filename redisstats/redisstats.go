@@ -0,0 +1,314 @@
+// Package redisstats provides a healthcheck.StatsStore keeping the statistics
+// of the checks in Redis, so the availability and the history of the checks
+// survive restarts and are aggregated across the replicas of a service.
+package redisstats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultPrefix is the default prefix of the keys.
+const DefaultPrefix = "healthcheck:stats:"
+
+// DefaultQueueSize is the default number of writes waiting to be sent to Redis.
+const DefaultQueueSize = 1024
+
+// DefaultTimeout is the default timeout of a write to Redis.
+const DefaultTimeout = time.Second
+
+var (
+	// ErrQueueFull is the error of a write dropped because the queue is full,
+	// i.e. Redis doesn't keep up with the executions of the checks.
+	ErrQueueFull = errors.New("redis stats queue is full")
+	// ErrClosed is the error of a write after Close.
+	ErrClosed = errors.New("redis stats store is closed")
+)
+
+// Client is the part of the Redis clients used by Store,
+// implemented by *redis.Client, *redis.ClusterClient and *redis.Ring.
+type Client interface {
+	HIncrBy(ctx context.Context, key, field string, incr int64) *redis.IntCmd
+	HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd
+	HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd
+	RPush(ctx context.Context, key string, values ...any) *redis.IntCmd
+	LTrim(ctx context.Context, key string, start, stop int64) *redis.StatusCmd
+	LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+}
+
+// Store is a healthcheck.StatsStore keeping the SLO buckets of every check in
+// a hash ("<prefix><check>:buckets", incremented atomically by all the replicas)
+// and its samples in a list ("<prefix><check>:samples"):
+//
+//	handler := healthcheck.NewHandler(healthcheck.WithStatsStore(redisstats.New(client)))
+//
+// The executions and the samples are queued and written in background, never
+// on the path of the checks: AddExecution and AddSample only fail with
+// ErrQueueFull or ErrClosed, the errors of the writes are passed to OnError.
+type Store struct {
+	client    Client
+	prefix    string
+	retention time.Duration
+	timeout   time.Duration
+	queueSize int
+
+	queue chan func(ctx context.Context) error
+	done  chan struct{}
+
+	mu      sync.Mutex
+	onError func(error)
+	closed  bool
+}
+
+// Option configures a Store.
+type Option func(s *Store)
+
+// WithPrefix sets the prefix of the keys, followed by the name of the check.
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// WithRetention expires the samples of the checks not executed for the duration,
+// e.g. to clean up after the checks removed from the services. The buckets
+// expire after the SLO window anyway.
+func WithRetention(retention time.Duration) Option {
+	return func(s *Store) {
+		s.retention = retention
+	}
+}
+
+// WithQueueSize sets the number of writes waiting to be sent to Redis
+// (DefaultQueueSize by default), the writes beyond are dropped.
+func WithQueueSize(size int) Option {
+	return func(s *Store) {
+		s.queueSize = size
+	}
+}
+
+// WithTimeout sets the timeout of a write to Redis (DefaultTimeout by default).
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Store) {
+		s.timeout = timeout
+	}
+}
+
+// New creates a Store on the client, writing in background until Close.
+func New(client Client, opts ...Option) *Store {
+	s := &Store{
+		client:    client,
+		prefix:    DefaultPrefix,
+		timeout:   DefaultTimeout,
+		queueSize: DefaultQueueSize,
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.queue = make(chan func(ctx context.Context) error, s.queueSize)
+	go s.work()
+	return s
+}
+
+// OnError sets the func receiving the errors of the background writes.
+func (s *Store) OnError(onError func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = onError
+}
+
+// Close sends the queued writes and stops the background writes.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+	return nil
+}
+
+// enqueue queues the write, unless the queue is full or the store closed.
+func (s *Store) enqueue(write func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case s.queue <- write:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// work sends the queued writes to Redis, each within the timeout.
+func (s *Store) work() {
+	defer close(s.done)
+
+	for write := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err := write(ctx)
+		cancel()
+
+		if err != nil {
+			s.mu.Lock()
+			onError := s.onError
+			s.mu.Unlock()
+
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// bucket fields are "<start in unix microseconds>:executions" and "<start>:failures".
+const (
+	executionsField = ":executions"
+	failuresField   = ":failures"
+)
+
+// AddExecution implements healthcheck.StatsStore.
+func (s *Store) AddExecution(_ context.Context, check string, start time.Time, window time.Duration, failed bool) error {
+	return s.enqueue(func(ctx context.Context) error {
+		return s.addExecution(ctx, check, start, window, failed)
+	})
+}
+
+func (s *Store) addExecution(ctx context.Context, check string, start time.Time, window time.Duration, failed bool) error {
+	key := s.prefix + check + ":buckets"
+	field := strconv.FormatInt(start.UnixMicro(), 10)
+
+	if err := s.client.HIncrBy(ctx, key, field+executionsField, 1).Err(); err != nil {
+		return err
+	}
+	if failed {
+		if err := s.client.HIncrBy(ctx, key, field+failuresField, 1).Err(); err != nil {
+			return err
+		}
+	}
+
+	// drop the buckets out of the window, the hash holds about 120 fields
+	fields, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	var expired []string
+	for name := range fields {
+		if bucketStart, ok := parseField(name); ok && start.Sub(bucketStart) >= window {
+			expired = append(expired, name)
+		}
+	}
+	if len(expired) > 0 {
+		if err := s.client.HDel(ctx, key, expired...).Err(); err != nil {
+			return err
+		}
+	}
+	return s.client.Expire(ctx, key, window).Err()
+}
+
+// parseField returns the start of the bucket of a field.
+func parseField(field string) (time.Time, bool) {
+	micros, _, _ := strings.Cut(field, ":")
+	n, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(n), true
+}
+
+// Buckets implements healthcheck.StatsStore.
+func (s *Store) Buckets(ctx context.Context, check string, since time.Time) ([]healthcheck.StatsBucket, error) {
+	fields, err := s.client.HGetAll(ctx, s.prefix+check+":buckets").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	byStart := make(map[int64]*healthcheck.StatsBucket)
+	for name, value := range fields {
+		start, ok := parseField(name)
+		n, err := strconv.Atoi(value)
+		if !ok || err != nil || start.Before(since) {
+			continue
+		}
+
+		b, ok := byStart[start.UnixMicro()]
+		if !ok {
+			b = &healthcheck.StatsBucket{Start: start}
+			byStart[start.UnixMicro()] = b
+		}
+		switch {
+		case strings.HasSuffix(name, executionsField):
+			b.Executions = n
+		case strings.HasSuffix(name, failuresField):
+			b.Failures = n
+		}
+	}
+
+	buckets := make([]healthcheck.StatsBucket, 0, len(byStart))
+	for _, b := range byStart {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets, nil
+}
+
+// AddSample implements healthcheck.StatsStore.
+func (s *Store) AddSample(_ context.Context, check string, sample healthcheck.Sample, size int) error {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	return s.enqueue(func(ctx context.Context) error {
+		return s.addSample(ctx, check, data, size)
+	})
+}
+
+func (s *Store) addSample(ctx context.Context, check string, data []byte, size int) error {
+	key := s.prefix + check + ":samples"
+	if err := s.client.RPush(ctx, key, data).Err(); err != nil {
+		return err
+	}
+	if err := s.client.LTrim(ctx, key, -int64(size), -1).Err(); err != nil {
+		return err
+	}
+	if s.retention > 0 {
+		return s.client.Expire(ctx, key, s.retention).Err()
+	}
+	return nil
+}
+
+// Samples implements healthcheck.StatsStore.
+func (s *Store) Samples(ctx context.Context, check string) ([]healthcheck.Sample, error) {
+	values, err := s.client.LRange(ctx, s.prefix+check+":samples", 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]healthcheck.Sample, 0, len(values))
+	for _, value := range values {
+		var sample healthcheck.Sample
+		if err := json.Unmarshal([]byte(value), &sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
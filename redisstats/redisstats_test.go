@@ -0,0 +1,231 @@
+package redisstats
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// fakeClient is an in-memory Client.
+type fakeClient struct {
+	mu      sync.Mutex
+	hashes  map[string]map[string]string
+	lists   map[string][]string
+	expires map[string]time.Duration
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		hashes:  make(map[string]map[string]string),
+		lists:   make(map[string][]string),
+		expires: make(map[string]time.Duration),
+	}
+}
+
+func (f *fakeClient) HIncrBy(_ context.Context, key, field string, incr int64) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.hashes[key] == nil {
+		f.hashes[key] = make(map[string]string)
+	}
+	n, _ := strconv.ParseInt(f.hashes[key][field], 10, 64)
+	n += incr
+	f.hashes[key][field] = strconv.FormatInt(n, 10)
+	return redis.NewIntResult(n, nil)
+}
+
+func (f *fakeClient) HGetAll(_ context.Context, key string) *redis.MapStringStringCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fields := make(map[string]string, len(f.hashes[key]))
+	for k, v := range f.hashes[key] {
+		fields[k] = v
+	}
+	return redis.NewMapStringStringResult(fields, nil)
+}
+
+func (f *fakeClient) HDel(_ context.Context, key string, fields ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, field := range fields {
+		delete(f.hashes[key], field)
+	}
+	return redis.NewIntResult(int64(len(fields)), nil)
+}
+
+func (f *fakeClient) RPush(_ context.Context, key string, values ...any) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, v := range values {
+		f.lists[key] = append(f.lists[key], string(v.([]byte)))
+	}
+	return redis.NewIntResult(int64(len(f.lists[key])), nil)
+}
+
+func (f *fakeClient) LTrim(_ context.Context, key string, start, _ int64) *redis.StatusCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// only the negative starts used by the store
+	if list := f.lists[key]; int64(len(list)) > -start {
+		f.lists[key] = list[int64(len(list))+start:]
+	}
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeClient) LRange(_ context.Context, key string, _, _ int64) *redis.StringSliceCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return redis.NewStringSliceResult(append([]string(nil), f.lists[key]...), nil)
+}
+
+func (f *fakeClient) Expire(_ context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expires[key] = expiration
+	return redis.NewBoolResult(true, nil)
+}
+
+func TestStoreBuckets(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	// two replicas sharing the keys
+	replicas := []*Store{New(client), New(client)}
+
+	start := time.UnixMicro(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMicro())
+	for i := 0; i < 4; i++ {
+		bucket := start.Add(time.Duration(i) * time.Minute)
+		if err := replicas[0].AddExecution(ctx, "db", bucket, 2*time.Minute, false); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		if err := replicas[1].AddExecution(ctx, "db", bucket, 2*time.Minute, true); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+	}
+	// Close sends the queued writes
+	for _, store := range replicas {
+		if err := store.Close(); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+	}
+
+	buckets, err := replicas[0].Buckets(ctx, "db", time.Time{})
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	expect := []healthcheck.StatsBucket{
+		{Start: start.Add(2 * time.Minute), Executions: 2, Failures: 1},
+		{Start: start.Add(3 * time.Minute), Executions: 2, Failures: 1},
+	}
+	if !reflect.DeepEqual(expect, buckets) {
+		t.Errorf("Wrong buckets\n"+"expected: %+v\n"+"actual  : %+v", expect, buckets)
+	}
+	if expire := client.expires[DefaultPrefix+"db:buckets"]; expire != 2*time.Minute {
+		t.Errorf("Wrong expiration\n"+"expected: %v\n"+"actual  : %v", 2*time.Minute, expire)
+	}
+}
+
+func TestStoreSamples(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	store := New(client, WithPrefix("app:"), WithRetention(time.Hour))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		sample := healthcheck.Sample{Time: start.Add(time.Duration(i) * time.Second), Metrics: map[string]float64{"lag": float64(i)}}
+		if err := store.AddSample(ctx, "db", sample, 2); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	samples, err := store.Samples(ctx, "db")
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	expect := []healthcheck.Sample{
+		{Time: start.Add(3 * time.Second), Metrics: map[string]float64{"lag": 3}},
+		{Time: start.Add(4 * time.Second), Metrics: map[string]float64{"lag": 4}},
+	}
+	if !reflect.DeepEqual(expect, samples) {
+		t.Errorf("Wrong samples\n"+"expected: %+v\n"+"actual  : %+v", expect, samples)
+	}
+	if expire := client.expires["app:db:samples"]; expire != time.Hour {
+		t.Errorf("Wrong expiration\n"+"expected: %v\n"+"actual  : %v", time.Hour, expire)
+	}
+}
+
+// slowClient is a Client whose increments never complete before the timeout.
+type slowClient struct {
+	*fakeClient
+
+	started chan struct{}
+}
+
+func (c *slowClient) HIncrBy(ctx context.Context, _, _ string, _ int64) *redis.IntCmd {
+	select {
+	case c.started <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return redis.NewIntResult(0, ctx.Err())
+}
+
+func TestStoreBackgroundWrites(t *testing.T) {
+	ctx := context.Background()
+	client := &slowClient{fakeClient: newFakeClient(), started: make(chan struct{}, 1)}
+	store := New(client, WithQueueSize(1), WithTimeout(10*time.Millisecond))
+
+	var mu sync.Mutex
+	var writeErrs []error
+	store.OnError(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeErrs = append(writeErrs, err)
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the first write is sent, the second one queued and the third one dropped
+	if err := store.AddExecution(ctx, "db", start, time.Minute, false); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	<-client.started
+	if err := store.AddExecution(ctx, "db", start, time.Minute, false); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if err := store.AddExecution(ctx, "db", start, time.Minute, false); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrQueueFull, err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if err := store.AddExecution(ctx, "db", start, time.Minute, false); !errors.Is(err, ErrClosed) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrClosed, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(writeErrs) != 2 {
+		t.Fatalf("Wrong number of errors\n"+"expected: %v\n"+"actual  : %v", 2, writeErrs)
+	}
+	for _, err := range writeErrs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", context.DeadlineExceeded, err)
+		}
+	}
+}
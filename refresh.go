@@ -0,0 +1,34 @@
+package healthcheck
+
+import "net/http"
+
+// refreshParameter is the query parameter forcing the execution of the background checks.
+const refreshParameter = "refresh"
+
+// WithRefreshParameter makes the probe, score and metrics endpoints execute
+// the background checks synchronously when requested with ?refresh=1, rather
+// than serving their last results, giving operators an on-demand live view
+// during incidents. The refreshed results are kept for the following probes.
+// If auth isn't nil, refresh requests it rejects get 403 Forbidden, e.g.
+// with AdminBearerToken, as refreshing on demand may overload dependencies.
+func WithRefreshParameter(auth AdminAuthFunc) Option {
+	return func(h *basicHandler) {
+		h.refreshParameter = true
+		h.refreshAuth = auth
+	}
+}
+
+// refresh executes the background checks of the sets if the request asks for
+// it. It replies with 403 Forbidden and returns false if the request isn't allowed.
+func (s *basicHandler) refresh(w http.ResponseWriter, r *http.Request, checks ...map[string]*checkEntry) bool {
+	if !s.refreshParameter || r.URL.Query().Get(refreshParameter) != "1" {
+		return true
+	}
+	if s.refreshAuth != nil && !s.refreshAuth(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+
+	s.refreshBackground(s.entries(checks...))
+	return true
+}
@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRefreshParameter(t *testing.T) {
+	h := NewHandler(WithRefreshParameter(AdminBearerToken("secret")))
+
+	var executions atomic.Int32
+	executed := make(chan struct{}, 1)
+	// the scheduler dies after the first execution
+	once := ScheduleFunc(func(time.Time) time.Time { return time.Time{} })
+	h.AddReadinessCheck("worker", func() error {
+		executions.Add(1)
+		select {
+		case executed <- struct{}{}:
+		default:
+		}
+		return nil
+	}, WithSchedule(once))
+	<-executed
+
+	tests := []struct {
+		name       string
+		target     string
+		token      string
+		code       int
+		executions int32
+	}{
+		{name: "cached", target: "/ready", code: http.StatusOK, executions: 1},
+		{name: "forbidden", target: "/ready?refresh=1", code: http.StatusForbidden, executions: 1},
+		{name: "refreshed", target: "/ready?refresh=1", token: "secret", code: http.StatusOK, executions: 2},
+		{name: "score", target: "/health/score?refresh=1", token: "secret", code: http.StatusOK, executions: 3},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != tt.code {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.code, rr.Code)
+		}
+		if n := executions.Load(); n != tt.executions {
+			t.Errorf("Wrong executions for %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.executions, n)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"errors"
+	"time"
+)
+
+// WithRetryOnFailure makes the probes retry a failed check once after the
+// delay before finalizing the response, absorbing single-packet blips
+// without the statefulness of a failure threshold. Only the result of the
+// retry is reported; the error and result handlers are notified of both
+// executions. Background checks aren't retried, as they're not executed
+// by the probes, and neither are the results a retry can't change: disabled,
+// suppressed, skipped (e.g. after too many panics) or chaos-injected.
+func WithRetryOnFailure(delay time.Duration) Option {
+	return func(h *basicHandler) {
+		h.retryOnFailure = true
+		h.retryDelay = delay
+	}
+}
+
+// retryCheck executes the failed check again after the retry delay,
+// or returns the failed result if the handler context is done first.
+func (s *basicHandler) retryCheck(entry *checkEntry, failed Result) Result {
+	if s.retryDelay > 0 {
+		timer := s.clock.NewTimer(s.retryDelay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return failed
+		case <-timer.C():
+		}
+	}
+	return s.runCheck(entry)
+}
+
+// retryable reports whether the error is a genuine failure of the check a retry may absorb.
+func retryable(err error) bool {
+	return checkFailed(err) && !errors.Is(err, ErrSkipped) && !errors.Is(err, ErrChaos)
+}
@@ -0,0 +1,90 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRetryOnFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		failures int32
+		expect   int
+		calls    int32
+	}{
+		{name: "blip without retry", failures: 1, expect: http.StatusServiceUnavailable, calls: 1},
+		{name: "blip absorbed by the retry", opts: []Option{WithRetryOnFailure(time.Millisecond)}, failures: 1, expect: http.StatusOK, calls: 2},
+		{name: "outage", opts: []Option{WithRetryOnFailure(time.Millisecond)}, failures: 5, expect: http.StatusServiceUnavailable, calls: 2},
+		{name: "passing check isn't retried", opts: []Option{WithRetryOnFailure(time.Millisecond)}, expect: http.StatusOK, calls: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			h := NewHandler(tt.opts...)
+			h.AddReadinessCheck("flaky", func() error {
+				if calls.Add(1) <= tt.failures {
+					return errors.New("connection reset")
+				}
+				return nil
+			})
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, rr.Code)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("Wrong calls\n"+"expected: %v\n"+"actual  : %v", tt.calls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{name: "passed"},
+		{name: "failed", err: errors.New("connection reset"), expect: true},
+		{name: "panicked", err: ErrPanicked, expect: true},
+		{name: "disabled", err: ErrCheckDisabled},
+		{name: "wrong role", err: ErrWrongRole},
+		{name: "suppressed", err: ErrSuppressed},
+		{name: "disabled after panics", err: &kindError{msg: "disabled after 3 panics", kind: ErrSkipped}},
+		{name: "chaos", err: ErrChaos},
+	}
+
+	for _, tt := range tests {
+		if actual := retryable(tt.err); actual != tt.expect {
+			t.Errorf("Wrong retryable of %s\n"+"expected: %v\n"+"actual  : %v", tt.name, tt.expect, actual)
+		}
+	}
+}
+
+func TestRetryOnFailureChaos(t *testing.T) {
+	var calls atomic.Int32
+	h := NewHandler(WithRetryOnFailure(time.Hour), WithChaos(map[string]ChaosRule{"db": {FailureRate: 1}}))
+	h.AddReadinessCheck("db", func() error {
+		calls.Add(1)
+		return nil
+	})
+
+	// an injected failure is reported right away, without waiting for a retry
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	if rr.Code != http.StatusServiceUnavailable || calls.Load() != 0 {
+		t.Errorf("Wrong result\n"+"expected: %v %v\n"+"actual  : %v %v", http.StatusServiceUnavailable, 0, rr.Code, calls.Load())
+	}
+}
@@ -0,0 +1,78 @@
+package healthcheck
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Role is the role of a replica of a service with leader election.
+type Role string
+
+const (
+	// RoleLeader the replica holds the leadership.
+	RoleLeader Role = "leader"
+	// RoleFollower the replica doesn't hold the leadership.
+	RoleFollower Role = "follower"
+)
+
+// ErrWrongRole is the result of a check restricted to a role the replica
+// doesn't hold. It's a skip which doesn't fail the probes.
+var ErrWrongRole error = &kindError{msg: "skipped: not applicable to the role", kind: ErrCheckDisabled}
+
+// RoleGate tracks the role of the replica for services with leader election
+// (e.g. operators), so checks only relevant to the leader (or the followers)
+// don't fail the other replicas:
+//
+//	gate := healthcheck.NewRoleGate()
+//	handler.AddReadinessCheck("reconciler", check, gate.Require(healthcheck.RoleLeader))
+//	handler.AddReadinessChecker(gate) // reports the role in the full output
+//
+//	// in the leader election callbacks
+//	gate.SetLeader(true)
+//
+// The replica is a follower until SetLeader(true) is called.
+type RoleGate struct {
+	leader atomic.Bool
+}
+
+// NewRoleGate creates a RoleGate of a follower.
+func NewRoleGate() *RoleGate {
+	return &RoleGate{}
+}
+
+// SetLeader sets whether the replica holds the leadership.
+func (g *RoleGate) SetLeader(leader bool) {
+	g.leader.Store(leader)
+}
+
+// Role returns the current role of the replica.
+func (g *RoleGate) Role() Role {
+	if g.leader.Load() {
+		return RoleLeader
+	}
+	return RoleFollower
+}
+
+// Require restricts the check to the replicas holding the role, it's
+// skipped with ErrWrongRole on the others.
+func (g *RoleGate) Require(role Role) CheckOption {
+	return func(c *checkConfig) {
+		c.roleGate = g
+		c.role = role
+	}
+}
+
+// Name returns "role".
+func (g *RoleGate) Name() string {
+	return "role"
+}
+
+// Check always passes, reporting the role in the "role" detail.
+func (g *RoleGate) Check(context.Context) Result {
+	return Result{Details: map[string]any{"role": string(g.Role())}}
+}
+
+// wrongRole reports whether the check is restricted to a role the replica doesn't hold.
+func (c *checkConfig) wrongRole() bool {
+	return c.roleGate != nil && c.roleGate.Role() != c.role
+}
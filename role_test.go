@@ -0,0 +1,50 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoleGate(t *testing.T) {
+	gate := NewRoleGate()
+	h := NewHandler()
+	h.AddReadinessCheck("reconciler", func() error {
+		return errors.New("informers not synced")
+	}, gate.Require(RoleLeader))
+	h.AddReadinessChecker(gate)
+
+	steps := []struct {
+		name       string
+		leader     bool
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "follower",
+			expectCode: http.StatusOK,
+			expectBody: `{"reconciler":"skipped: not applicable to the role",` +
+				`"role":{"status":"OK","kind":"ok","details":{"role":"follower"}}}` + "\n",
+		},
+		{
+			name:       "leader",
+			leader:     true,
+			expectCode: http.StatusServiceUnavailable,
+			expectBody: `{"reconciler":"informers not synced",` +
+				`"role":{"status":"OK","kind":"ok","details":{"role":"leader"}}}` + "\n",
+		},
+	}
+	for _, step := range steps {
+		gate.SetLeader(step.leader)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath+"?full=1&compact=1", nil))
+		if rr.Code != step.expectCode {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.expectCode, rr.Code)
+		}
+		if rr.Body.String() != step.expectBody {
+			t.Errorf("Wrong body for %s\n"+"expected: %v\n"+"actual  : %v", step.name, step.expectBody, rr.Body.String())
+		}
+	}
+}
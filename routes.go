@@ -0,0 +1,114 @@
+package healthcheck
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router is the minimal interface of a router the health endpoints can be
+// registered on, e.g. *http.ServeMux or chi.Router.
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// RouterFunc adapts a function to the Router interface, for the routers whose
+// Handle method has another signature, e.g. gorilla/mux:
+//
+//	h.RegisterRoutes(healthcheck.RouterFunc(func(pattern string, handler http.Handler) {
+//		r.Handle(pattern, handler)
+//	}))
+type RouterFunc func(pattern string, handler http.Handler)
+
+// Handle calls f(pattern, handler).
+func (f RouterFunc) Handle(pattern string, handler http.Handler) {
+	f(pattern, handler)
+}
+
+// Route is an endpoint of the handler.
+type Route struct {
+	// Method is the HTTP method the endpoint serves, empty for any method.
+	Method string
+	// Path is the path of the endpoint, with "{name}" path parameters.
+	// Handler reads them with http.Request.PathValue, so a Route registered
+	// on another router than http.ServeMux must set them with SetPathValue.
+	Path string
+	// Handler serves the endpoint.
+	Handler http.HandlerFunc
+
+	operation openAPIOperation
+}
+
+// route registers the handler on the mux and records it for Routes, RegisterRoutes and OpenAPI.
+// The pattern may start with a method, as supported by http.ServeMux.
+func (s *basicHandler) route(pattern string, op openAPIOperation, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+
+	var method string
+	path := pattern
+	if i := strings.IndexByte(path, ' '); i >= 0 {
+		method, path = path[:i], path[i+1:]
+	}
+	// "/{$}" is the ServeMux spelling of the exact "/" path
+	path = strings.TrimSuffix(path, "{$}")
+
+	s.routes = append(s.routes, Route{Method: method, Path: path, Handler: handler, operation: op})
+}
+
+// ServeHTTP dispatches the request to the endpoint matching its method and path.
+func (s *basicHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Routes returns the endpoints of the handler.
+func (s *basicHandler) Routes() []Route {
+	return slices.Clone(s.routes)
+}
+
+// RegisterRoutes registers every health endpoint path on r, served by the handler.
+// The paths use the "{name}" syntax for path parameters, understood by
+// net/http, chi and gorilla/mux. The handler still matches the method and
+// the path of the request, so it must be registered at the root of the router
+// (or of a sub-router which doesn't strip the prefix).
+func (s *basicHandler) RegisterRoutes(r Router) {
+	var paths []string
+	for _, route := range s.routes {
+		if !slices.Contains(paths, route.Path) {
+			paths = append(paths, route.Path)
+			r.Handle(route.Path, s)
+		}
+	}
+}
+
+// WithServeMuxMethods makes the handler returned by NewHandler keep the Handle,
+// HandleFunc and Handler methods of the http.ServeMux it used to embed, for the
+// code registering its own routes on it through a type assertion:
+//
+//	handler.(interface{ Handle(string, http.Handler) }).Handle("/version", version)
+//
+// New code should use RegisterRoutes or Routes instead.
+func WithServeMuxMethods() Option {
+	return func(h *basicHandler) {
+		h.serveMuxMethods = true
+	}
+}
+
+// serveMuxHandler is the handler created with WithServeMuxMethods.
+type serveMuxHandler struct {
+	*basicHandler
+}
+
+// Handle registers the handler for the given pattern, see http.ServeMux.Handle.
+func (s serveMuxHandler) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// HandleFunc registers the handler function for the given pattern, see http.ServeMux.HandleFunc.
+func (s serveMuxHandler) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Handler returns the handler to use for the given request, see http.ServeMux.Handler.
+func (s serveMuxHandler) Handler(r *http.Request) (http.Handler, string) {
+	return s.mux.Handler(r)
+}
@@ -0,0 +1,137 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	h := NewHandler(WithAdminEndpoints(), WithAdminAuth(AdminAllowAll))
+	h.AddReadinessCheck("database", func() error { return nil })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h.RegisterRoutes(mux)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		expect int
+	}{
+		{
+			name:   "application route",
+			method: http.MethodGet,
+			target: "/app",
+			expect: http.StatusTeapot,
+		},
+		{
+			name:   "liveness",
+			method: http.MethodGet,
+			target: "/live",
+			expect: http.StatusOK,
+		},
+		{
+			name:   "admin route with a path parameter",
+			method: http.MethodPost,
+			target: "/health/admin/checks/database/disable",
+			expect: http.StatusNoContent,
+		},
+		{
+			name:   "admin route with a wrong method",
+			method: http.MethodGet,
+			target: "/health/admin/checks/database/disable",
+			expect: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "unknown route",
+			method: http.MethodGet,
+			target: "/unknown",
+			expect: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", tt.expect, rr.Code)
+			}
+		})
+	}
+}
+
+func TestRegisterRoutesRouterFunc(t *testing.T) {
+	h := NewHandler()
+
+	var patterns []string
+	h.RegisterRoutes(RouterFunc(func(pattern string, _ http.Handler) {
+		patterns = append(patterns, pattern)
+	}))
+
+	expect := []string{LivenessHandlerPath, ReadinessHandlerPath, GraphHandlerPath, ScoreHandlerPath, StatsHandlerPath, CallersHandlerPath, DiffHandlerPath, SchemaHandlerPath}
+	if len(patterns) != len(expect) {
+		t.Fatalf("Wrong patterns\n"+"expected: %v\n"+"actual  : %v", expect, patterns)
+	}
+	for i := range expect {
+		if patterns[i] != expect[i] {
+			t.Errorf("Wrong pattern\n"+"expected: %v\n"+"actual  : %v", expect[i], patterns[i])
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	h := NewHandler(WithAdminEndpoints())
+	h.AddReadinessCheck("database", func() error { return nil })
+
+	var ready *Route
+	for _, route := range h.Routes() {
+		if route.Path == "/health/admin/checks/{name}/disable" && route.Method != http.MethodPost {
+			t.Errorf("Wrong method\n"+"expected: %v\n"+"actual  : %v", http.MethodPost, route.Method)
+		}
+		if route.Path == ReadinessHandlerPath {
+			route := route
+			ready = &route
+		}
+	}
+	if ready == nil {
+		t.Fatalf("Missing route %s", ReadinessHandlerPath)
+	}
+
+	rr := httptest.NewRecorder()
+	ready.Handler(rr, httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusOK, rr.Code)
+	}
+}
+
+func TestWithServeMuxMethods(t *testing.T) {
+	type muxHandler interface {
+		Handle(pattern string, handler http.Handler)
+	}
+
+	if _, ok := NewHandler().(muxHandler); ok {
+		t.Errorf("Unexpected ServeMux methods without WithServeMuxMethods")
+	}
+
+	h := NewHandler(WithServeMuxMethods())
+	mux, ok := h.(muxHandler)
+	if !ok {
+		t.Fatalf("Missing ServeMux methods with WithServeMuxMethods")
+	}
+	mux.Handle("/version", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for target, expect := range map[string]int{"/version": http.StatusTeapot, "/live": http.StatusOK} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != expect {
+			t.Errorf("Wrong code for %s\n"+"expected: %v\n"+"actual  : %v", target, expect, rr.Code)
+		}
+	}
+}
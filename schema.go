@@ -0,0 +1,167 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaHandlerPath path to the JSON Schemas of the response formats.
+const SchemaHandlerPath = "/health/schema"
+
+// Response formats described by JSONSchema.
+const (
+	// SchemaCompact is the format of the probes without ?full=1: an empty object.
+	SchemaCompact = "compact"
+	// SchemaFull is the format of the probes with ?full=1: the results by check name.
+	SchemaFull = "full"
+	// SchemaHealthJSON is the application/health+json format
+	// (draft-inadarei-api-health-check).
+	SchemaHealthJSON = "health+json"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the schemas.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ErrUnknownFormat is the error of JSONSchema for an unknown format.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// HealthJSON is a response in the application/health+json format.
+type HealthJSON struct {
+	// Status is "pass", "warn" or "fail".
+	Status Status `json:"status"`
+	// Output is the error of the probe, if it failed.
+	Output string `json:"output,omitempty"`
+	// Checks are the results of the checks by name.
+	Checks map[string][]HealthJSONCheck `json:"checks,omitempty"`
+}
+
+// HealthJSONCheck is the result of a check in the application/health+json format.
+type HealthJSONCheck struct {
+	// Status is "pass", "warn" or "fail".
+	Status Status `json:"status"`
+	// Output is the error of the check, if it failed.
+	Output string `json:"output,omitempty"`
+	// Time is the time of the result.
+	Time time.Time `json:"time"`
+	// ObservedValue is the value observed by the check (the only numeric detail), if any.
+	ObservedValue any `json:"observedValue,omitempty"`
+	// ObservedUnit is the unit of ObservedValue.
+	ObservedUnit string `json:"observedUnit,omitempty"`
+}
+
+// JSONSchema returns the JSON Schema of a response format (SchemaCompact, SchemaFull
+// or SchemaHealthJSON), generated from the Go types, e.g. for contract tests of clients.
+func JSONSchema(format string) ([]byte, error) {
+	schema, ok := jsonSchemas()[format]
+	if !ok {
+		return nil, ErrUnknownFormat
+	}
+	return json.MarshalIndent(schema, "", "    ")
+}
+
+// jsonSchemas returns the schemas of the response formats by format.
+func jsonSchemas() map[string]openAPISchema {
+	full := openAPISchema{
+		"$schema":     jsonSchemaDraft,
+		"title":       "Full output of the probes",
+		"description": "Results of the checks by name: OK, the error of the check or an object.",
+		"type":        "object",
+		"additionalProperties": openAPISchema{
+			"oneOf": []openAPISchema{
+				{"type": "string"},
+				jsonSchemaOf(reflect.TypeOf(detailedOutput{})),
+			},
+		},
+	}
+
+	health := jsonSchemaOf(reflect.TypeOf(HealthJSON{}))
+	health["$schema"] = jsonSchemaDraft
+	health["title"] = "application/health+json output of the probes"
+
+	return map[string]openAPISchema{
+		SchemaCompact: {
+			"$schema":              jsonSchemaDraft,
+			"title":                "Output of the probes without ?full=1",
+			"type":                 "object",
+			"additionalProperties": false,
+		},
+		SchemaFull:       full,
+		SchemaHealthJSON: health,
+	}
+}
+
+// jsonSchemaOf generates the schema of the JSON encoding of the type.
+func jsonSchemaOf(t reflect.Type) openAPISchema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return openAPISchema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchemaOf(t.Elem())
+	case reflect.Bool:
+		return openAPISchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openAPISchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return openAPISchema{"type": "number"}
+	case reflect.String:
+		return openAPISchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return openAPISchema{"type": "array", "items": jsonSchemaOf(t.Elem())}
+	case reflect.Map:
+		return openAPISchema{"type": "object", "additionalProperties": jsonSchemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]openAPISchema)
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = jsonSchemaOf(field.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+
+		schema := openAPISchema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		// interfaces hold any value
+		return openAPISchema{}
+	}
+}
+
+// SchemaEndpoint is an HTTP handler exposing the JSON Schemas of the response
+// formats by format, or only the schema of the format set by ?format=.
+func (s *basicHandler) SchemaEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) {
+		return
+	}
+
+	schemas := jsonSchemas()
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		writeJSON(w, http.StatusOK, schemas)
+		return
+	}
+	schema, ok := schemas[format]
+	if !ok {
+		http.Error(w, "unknown format", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
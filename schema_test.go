@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		format   string
+		required []string
+	}{
+		{format: SchemaFull},
+		{format: SchemaHealthJSON, required: []string{"status"}},
+		{format: SchemaCompact},
+	} {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			raw, err := JSONSchema(tt.format)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			var schema struct {
+				Schema   string   `json:"$schema"`
+				Type     string   `json:"type"`
+				Required []string `json:"required"`
+			}
+			if err := json.Unmarshal(raw, &schema); err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if schema.Schema != jsonSchemaDraft || schema.Type != "object" {
+				t.Errorf("Wrong schema\n"+"expected: %v object\n"+"actual  : %v %v", jsonSchemaDraft, schema.Schema, schema.Type)
+			}
+			if !reflect.DeepEqual(tt.required, schema.Required) {
+				t.Errorf("Wrong required properties\n"+"expected: %v\n"+"actual  : %v", tt.required, schema.Required)
+			}
+		})
+	}
+
+	if _, err := JSONSchema("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrUnknownFormat, err)
+	}
+}
+
+func TestJSONSchemaOf(t *testing.T) {
+	t.Parallel()
+
+	schema := jsonSchemaOf(reflect.TypeOf(detailedOutput{}))
+	properties := schema["properties"].(map[string]openAPISchema)
+
+	expected := openAPISchema{"type": "number"}
+	if actual := properties["duration_seconds"]; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Wrong duration_seconds schema\n"+"expected: %v\n"+"actual  : %v", expected, actual)
+	}
+	if expected, actual := []string{"status", "kind"}, schema["required"]; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Wrong required properties\n"+"expected: %v\n"+"actual  : %v", expected, actual)
+	}
+}
+
+func TestSchemaEndpoint(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler()
+
+	for _, tt := range []struct {
+		query  string
+		status int
+	}{
+		{query: "", status: http.StatusOK},
+		{query: "?format=health%2Bjson", status: http.StatusOK},
+		{query: "?format=xml", status: http.StatusNotFound},
+	} {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, SchemaHandlerPath+tt.query, nil))
+		if rr.Code != tt.status {
+			t.Errorf("Wrong status of %q\n"+"expected: %v\n"+"actual  : %v", tt.query, tt.status, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package healthcheck
+
+import (
+	"errors"
+	"math"
+	"net/http"
+)
+
+// ScoreHandlerPath path to the weighted health score.
+const ScoreHandlerPath = "/health/score"
+
+// defaultWeight is the weight of a check without WithWeight.
+const defaultWeight = 1
+
+// WithWeight sets the weight of the check in the health score, 1 by default.
+// A check with zero weight doesn't affect the score.
+func WithWeight(weight float64) CheckOption {
+	return func(c *checkConfig) {
+		c.weight = &weight
+	}
+}
+
+// WithScoreThreshold sets the health score (0-100) below which
+// the score endpoint responds with 503 Service Unavailable.
+func WithScoreThreshold(threshold float64) Option {
+	return func(h *basicHandler) {
+		h.scoreThreshold = threshold
+	}
+}
+
+// Score is the weighted health score of the readiness checks.
+type Score struct {
+	// Score is the weighted share of passing checks from 0 to 100.
+	Score float64 `json:"score"`
+	// Threshold is the score below which the instance is considered unhealthy.
+	Threshold float64 `json:"threshold"`
+}
+
+// weightOrDefault returns the weight of the check in the health score.
+func (c *checkConfig) weightOrDefault() float64 {
+	if c.reportOnly {
+		return 0
+	}
+	if c.weight == nil {
+		return defaultWeight
+	}
+	return *c.weight
+}
+
+// score computes the weighted share of passing checks,
+// 100 if there are no weighted checks.
+func score(checks []*checkEntry, results map[string]Result) float64 {
+	var total, passed float64
+	for _, entry := range checks {
+		if err := results[entry.name].Err; errors.Is(err, ErrCheckDisabled) || errors.Is(err, ErrSuppressed) {
+			continue
+		}
+
+		weight := entry.config.weightOrDefault()
+		total += weight
+		if results[entry.name].Err == nil {
+			passed += weight
+		}
+	}
+
+	if total <= 0 {
+		return 100
+	}
+	// round to 2 decimals to keep the output readable
+	return math.Round(passed/total*100*100) / 100
+}
+
+// ScoreEndpoint is an HTTP handler exposing the weighted health score of the
+// readiness checks (liveness checks included), useful for load balancers
+// supporting weighted backends. It responds with 503 Service Unavailable
+// if the score is below the threshold set by WithScoreThreshold.
+func (s *basicHandler) ScoreEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) || !s.refresh(w, r, s.readinessProbe...) {
+		return
+	}
+
+	entries := s.entries(s.readinessProbe...)
+	result := Score{
+		Score:     score(entries, s.collectChecks(entries)),
+		Threshold: s.scoreThreshold,
+	}
+
+	status := http.StatusOK
+	if result.Score < result.Threshold {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, result)
+}
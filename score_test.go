@@ -0,0 +1,62 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScoreEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		threshold  float64
+		expect     int
+		expectBody string
+	}{
+		{
+			name:       "score above the threshold",
+			threshold:  70,
+			expect:     http.StatusOK,
+			expectBody: "{\n    \"score\": 75,\n    \"threshold\": 70\n}\n",
+		},
+		{
+			name:       "score below the threshold",
+			threshold:  80,
+			expect:     http.StatusServiceUnavailable,
+			expectBody: "{\n    \"score\": 75,\n    \"threshold\": 80\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(WithScoreThreshold(tt.threshold))
+			h.AddLivenessCheck("live", func() error { return nil }, WithWeight(2))
+			h.AddReadinessCheck("database", func() error { return nil })
+			h.AddReadinessCheck("cache", func() error { return errors.New("failed") })
+			h.AddReadinessCheck("optional", func() error { return errors.New("failed") }, WithWeight(0))
+
+			req, err := http.NewRequest(http.MethodGet, ScoreHandlerPath, nil)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != tt.expect {
+				t.Errorf("Wrong code\n"+
+					"expected: %v\n"+
+					"actual  : %v", tt.expect, rr.Code)
+			}
+			if rr.Body.String() != tt.expectBody {
+				t.Errorf("Wrong body\n"+
+					"expected: %v"+
+					"actual  : %v", tt.expectBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package healthcheck
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StatsHandlerPath path to the availability of the checks tracked by WithSLO
+// and the history of the checks set up with WithHistory.
+const StatsHandlerPath = "/health/stats"
+
+// sloBuckets is the number of buckets the SLO window is split into.
+const sloBuckets = 60
+
+// WithSLO tracks the rolling availability of the check (share of passed
+// executions) over the window against the target (e.g. 0.999), and exposes
+// the burn of its error budget on the stats endpoint and in the metrics:
+//
+//	handler.AddReadinessCheck("payments-api", check, healthcheck.WithSLO(0.999, 24*time.Hour))
+//
+// The window is tracked in buckets of a 60th of its length, so the oldest
+// executions expire by batches.
+func WithSLO(target float64, window time.Duration) CheckOption {
+	return func(c *checkConfig) {
+		c.slo = &sloConfig{target: target, window: window}
+	}
+}
+
+// sloConfig is the SLO of a check set by WithSLO.
+type sloConfig struct {
+	target float64
+	window time.Duration
+}
+
+// SLOStats is the availability of a check over its SLO window.
+type SLOStats struct {
+	// Executions is the number of executions of the check in the window.
+	Executions int `json:"executions"`
+	// Failures is the number of failed executions of the check in the window.
+	Failures int `json:"failures"`
+	// Availability is the share of passed executions, 1 without executions.
+	Availability float64 `json:"availability"`
+	// Target is the availability objective set by WithSLO.
+	Target float64 `json:"target"`
+	// Window is the length of the rolling window in seconds.
+	Window float64 `json:"window_seconds"`
+	// BurnRate is the rate the error budget is consumed at: 1 consumes
+	// exactly the budget over the window, more exhausts it before.
+	BurnRate float64 `json:"burn_rate"`
+	// BudgetRemaining is the share of the error budget left in the window,
+	// negative once the SLO is breached.
+	BudgetRemaining float64 `json:"budget_remaining"`
+}
+
+// sloBucket counts the executions of a slice of the window.
+type sloBucket struct {
+	start      time.Time
+	executions int
+	failures   int
+}
+
+// sloTracker tracks the executions of a check over its SLO window.
+type sloTracker struct {
+	config sloConfig
+
+	mu      sync.Mutex
+	buckets [sloBuckets]sloBucket
+}
+
+// sloBucketWidth returns the length of the buckets of the window.
+func sloBucketWidth(window time.Duration) time.Duration {
+	if width := window / sloBuckets; width > 0 {
+		return width
+	}
+	return 1
+}
+
+// record counts an execution of the check at now.
+func (t *sloTracker) record(now time.Time, failed bool) {
+	width := sloBucketWidth(t.config.window)
+	start := now.Truncate(width)
+	i := int(start.UnixNano()/int64(width)) % sloBuckets
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := &t.buckets[i]
+	if !b.start.Equal(start) {
+		*b = sloBucket{start: start}
+	}
+	b.executions++
+	if failed {
+		b.failures++
+	}
+}
+
+// stats returns the availability of the check over the window ending at now.
+func (t *sloTracker) stats(now time.Time) SLOStats {
+	var executions, failures int
+
+	t.mu.Lock()
+	for _, b := range t.buckets {
+		if b.executions > 0 && now.Sub(b.start) < t.config.window {
+			executions += b.executions
+			failures += b.failures
+		}
+	}
+	t.mu.Unlock()
+
+	return sloStatsOf(t.config, executions, failures)
+}
+
+// sloStatsOf returns the availability of a check from its executions in the window.
+func sloStatsOf(config sloConfig, executions, failures int) SLOStats {
+	stats := SLOStats{
+		Executions:   executions,
+		Failures:     failures,
+		Availability: 1,
+		Target:       config.target,
+		Window:       config.window.Seconds(),
+	}
+	if stats.Executions > 0 {
+		stats.Availability = 1 - float64(stats.Failures)/float64(stats.Executions)
+	}
+	if budget := 1 - stats.Target; budget > 0 {
+		stats.BurnRate = (1 - stats.Availability) / budget
+	}
+	stats.BudgetRemaining = 1 - stats.BurnRate
+	return stats
+}
+
+// recordSLO counts the execution of the check if it's tracked by WithSLO.
+func (s *basicHandler) recordSLO(entry *checkEntry, res Result) {
+	if entry.slo != nil {
+		entry.slo.record(s.clock.Now(), res.Err != nil)
+	}
+}
+
+// sloStats returns the availability of the checks tracked by WithSLO by name.
+func (s *basicHandler) sloStats() map[string]SLOStats {
+	stats := make(map[string]SLOStats)
+	for name, st := range s.checkStats() {
+		if st.SLOStats != nil {
+			stats[name] = *st.SLOStats
+		}
+	}
+	return stats
+}
+
+// StatsEndpoint is an HTTP handler exposing the availability of the checks
+// tracked by WithSLO and the history of the checks set up with WithHistory as JSON.
+func (s *basicHandler) StatsEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.allowMethod(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.checkStats())
+}
@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSLO(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock), WithOpenMetrics(""))
+
+	failing := false
+	h.AddReadinessCheck("payments", func() error {
+		if failing {
+			return errors.New("failed")
+		}
+		return nil
+	}, WithSLO(0.9, time.Hour))
+	h.AddReadinessCheck("cache", func() error { return nil })
+
+	ready := func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+	}
+	stats := func() map[string]SLOStats {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatsHandlerPath, nil))
+		var stats map[string]SLOStats
+		if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+			t.Fatalf("Received unexpected error:\n%+v", err)
+		}
+		return stats
+	}
+
+	for i := 0; i < 8; i++ {
+		ready()
+	}
+	failing = true
+	for i := 0; i < 2; i++ {
+		ready()
+	}
+
+	st, ok := stats()["payments"]
+	if !ok {
+		t.Fatalf("Missing stats of the check")
+	}
+	if st.Executions != 10 || st.Failures != 2 {
+		t.Errorf("Wrong executions\n"+"expected: %v/%v\n"+"actual  : %v/%v", 10, 2, st.Executions, st.Failures)
+	}
+	if math.Abs(st.BurnRate-2) > 1e-9 || math.Abs(st.BudgetRemaining+1) > 1e-9 {
+		t.Errorf("Wrong budget\n"+"expected: %v %v\n"+"actual  : %v %v", 2, -1, st.BurnRate, st.BudgetRemaining)
+	}
+	if _, ok := stats()["cache"]; ok {
+		t.Errorf("Unexpected stats of a check without SLO")
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, OpenMetricsHandlerPath, nil))
+	if !strings.Contains(rr.Body.String(), `healthcheck_check_availability{check="payments",probe="readiness"} `) {
+		t.Errorf("Missing availability in the metrics:\n%s", rr.Body.String())
+	}
+
+	// the executions expire with the window
+	clock.Advance(2 * time.Hour)
+	if st := stats()["payments"]; st.Executions != 0 || st.Availability != 1 {
+		t.Errorf("Wrong stats after the window: %+v", st)
+	}
+}
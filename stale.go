@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStaleResult is the error of a background check whose last
+// execution is older than the TTL set by WithStaleAfter.
+var ErrStaleResult = errors.New("stale result")
+
+// WithStaleAfter fails the background check, marking its result stale, when
+// its last execution completed more than ttl ago (or, before the first one,
+// when it started more than ttl ago): a wedged check or a dead scheduler
+// can't keep serving a cached passing result forever. The TTL should exceed
+// the schedule interval plus the duration of the check.
+// It only has an effect along with WithSchedule.
+func WithStaleAfter(ttl time.Duration) CheckOption {
+	return func(c *checkConfig) {
+		c.staleAfter = ttl
+	}
+}
+
+// backgroundResult returns the last result of the background check,
+// failed with ErrStaleResult if it's older than its staleness TTL.
+func (s *basicHandler) backgroundResult(entry *checkEntry) Result {
+	res, updated := entry.background.result()
+	ttl := entry.config.staleAfter
+	if ttl <= 0 {
+		return res
+	}
+
+	if age := s.clock.Now().Sub(updated); age > ttl {
+		res.Err = fmt.Errorf("%w: last executed %v ago (ttl %v)", ErrStaleResult, age.Round(time.Millisecond), ttl)
+		res.Code = ""
+		res.Stale = true
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithStaleAfter(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := NewHandler(WithClock(clock))
+
+	executed := make(chan struct{}, 1)
+	// the scheduler dies after the first execution
+	once := ScheduleFunc(func(time.Time) time.Time { return time.Time{} })
+	h.AddReadinessCheck("worker", func() error {
+		executed <- struct{}{}
+		return nil
+	}, WithSchedule(once), WithStaleAfter(time.Minute))
+	<-executed
+
+	// let the background goroutine store the result
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, results, _ := h.Check(context.Background())
+		if results["worker"].Err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Received unexpected error:\n%+v", results["worker"].Err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	status, results, err := h.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if status != StatusFail {
+		t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", StatusFail, status)
+	}
+	if res := results["worker"]; !errors.Is(res.Err, ErrStaleResult) || !res.Stale {
+		t.Errorf("Wrong result\n"+"expected: %v\n"+"actual  : %v", ErrStaleResult, res.Err)
+	}
+}
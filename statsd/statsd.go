@@ -0,0 +1,162 @@
+package statsd
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// DefaultPrefix is the default prefix of all emitted metric names.
+const DefaultPrefix = "healthcheck"
+
+// Emitter sends check outcome and latency metrics to a statsd
+// or DogStatsD server over UDP.
+type Emitter struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	prefix string
+	tagged bool
+	tags   []string
+}
+
+// Option configures an Emitter.
+type Option func(e *Emitter)
+
+// WithPrefix sets the prefix of all emitted metric names.
+func WithPrefix(prefix string) Option {
+	return func(e *Emitter) {
+		e.prefix = prefix
+	}
+}
+
+// WithDogStatsD enables DogStatsD tags: the check name is sent as a "check" tag
+// instead of being a part of the metric name.
+func WithDogStatsD() Option {
+	return func(e *Emitter) {
+		e.tagged = true
+	}
+}
+
+// WithTags adds constant DogStatsD tags (in "key:value" form) to every metric.
+// It implies WithDogStatsD.
+func WithTags(tags ...string) Option {
+	return func(e *Emitter) {
+		e.tagged = true
+		e.tags = append(e.tags, tags...)
+	}
+}
+
+// New creates an Emitter sending metrics to the statsd server at addr.
+func New(addr string, opts ...Option) (*Emitter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Emitter{
+		conn:   conn,
+		prefix: DefaultPrefix,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e, nil
+}
+
+// Handle emits the metrics of a single check execution. It matches
+// healthcheck.ResultHandler, so it can be passed to AddCheckResultHandler:
+//
+//	handler.AddCheckResultHandler(emitter.Handle)
+//
+// The following metrics are sent:
+//   - <prefix>.check.duration: check latency in milliseconds (timing);
+//   - <prefix>.check.up: 1 if the check passed, 0 otherwise (gauge);
+//   - <prefix>.check.failed: incremented on every failure (counter);
+//   - <prefix>.check.detail.<key>: numeric details reported by a Checker (gauge).
+//
+// Without DogStatsD tags the check name is inserted after "check", e.g.
+// "healthcheck.check.database.duration". With DogStatsD tags, the labels
+// of the check (see healthcheck.WithLabels) are sent as tags too.
+func (e *Emitter) Handle(name string, result healthcheck.Result) {
+	up := 1
+	if result.Err != nil {
+		up = 0
+	}
+
+	var b strings.Builder
+	e.write(&b, name, "duration", strconv.FormatFloat(float64(result.Duration.Microseconds())/1000, 'f', -1, 64), "ms", result.Labels)
+	e.write(&b, name, "up", strconv.Itoa(up), "g", result.Labels)
+	if result.Err != nil {
+		e.write(&b, name, "failed", "1", "c", result.Labels)
+	}
+	for key, value := range result.Metrics() {
+		e.write(&b, name, "detail."+sanitize(key), strconv.FormatFloat(value, 'f', -1, 64), "g", result.Labels)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// statsd is fire and forget, there is nothing to do with write errors
+	_, _ = e.conn.Write([]byte(b.String()))
+}
+
+// Close closes the underlying connection.
+func (e *Emitter) Close() error {
+	return e.conn.Close()
+}
+
+func (e *Emitter) write(b *strings.Builder, check, metric, value, kind string, labels map[string]string) {
+	if b.Len() > 0 {
+		b.WriteByte('\n')
+	}
+
+	b.WriteString(e.prefix)
+	b.WriteString(".check.")
+	if !e.tagged {
+		b.WriteString(sanitize(check))
+		b.WriteByte('.')
+	}
+	b.WriteString(metric)
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(kind)
+
+	if e.tagged {
+		b.WriteString("|#check:")
+		b.WriteString(sanitize(check))
+		for _, tag := range e.tags {
+			b.WriteByte(',')
+			b.WriteString(tag)
+		}
+
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteByte(',')
+			b.WriteString(sanitize(key))
+			b.WriteByte(':')
+			b.WriteString(sanitize(labels[key]))
+		}
+	}
+}
+
+// sanitize replaces the characters having a special meaning
+// in the statsd protocol.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
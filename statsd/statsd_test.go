@@ -0,0 +1,87 @@
+package statsd
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		result healthcheck.Result
+		expect string
+	}{
+		{
+			name:   "passed",
+			result: healthcheck.Result{Duration: 1500 * time.Microsecond},
+			expect: "healthcheck.check.main_db.duration:1.5|ms\n" +
+				"healthcheck.check.main_db.up:1|g",
+		},
+		{
+			name:   "failed with a prefix",
+			opts:   []Option{WithPrefix("api")},
+			result: healthcheck.Result{Err: errors.New("connection refused"), Duration: 2 * time.Millisecond},
+			expect: "api.check.main_db.duration:2|ms\n" +
+				"api.check.main_db.up:0|g\n" +
+				"api.check.main_db.failed:1|c",
+		},
+		{
+			name:   "details",
+			result: healthcheck.Result{Duration: time.Millisecond, Details: map[string]any{"pool size": 8, "version": "16.1"}},
+			expect: "healthcheck.check.main_db.duration:1|ms\n" +
+				"healthcheck.check.main_db.up:1|g\n" +
+				"healthcheck.check.main_db.detail.pool_size:8|g",
+		},
+		{
+			name: "DogStatsD tags and labels",
+			opts: []Option{WithTags("env:prod")},
+			result: healthcheck.Result{
+				Err:      errors.New("timeout"),
+				Duration: time.Millisecond,
+				Labels:   map[string]string{"tier": "critical", "owner": "payments team"},
+			},
+			expect: "healthcheck.check.duration:1|ms|#check:main_db,env:prod,owner:payments_team,tier:critical\n" +
+				"healthcheck.check.up:0|g|#check:main_db,env:prod,owner:payments_team,tier:critical\n" +
+				"healthcheck.check.failed:1|c|#check:main_db,env:prod,owner:payments_team,tier:critical",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			defer conn.Close()
+
+			e, err := New(conn.LocalAddr().String(), tt.opts...)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			defer e.Close()
+
+			e.Handle("main db", tt.result)
+
+			if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			buf := make([]byte, 1024)
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if packet := string(buf[:n]); packet != tt.expect {
+				t.Errorf("Wrong packet\n"+"expected: %v\n"+"actual  : %v", tt.expect, packet)
+			}
+		})
+	}
+}
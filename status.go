@@ -0,0 +1,90 @@
+package healthcheck
+
+import "context"
+
+// Status is the overall status of a probe.
+type Status string
+
+const (
+	// StatusPass the probe passes.
+	StatusPass Status = "pass"
+	// StatusWarn the readiness probe is degraded by failed Important checks.
+	StatusWarn Status = "warn"
+	// StatusFail the probe fails.
+	StatusFail Status = "fail"
+)
+
+// statusOf returns the Status matching the outcome of a probe.
+func statusOf(state probeState) Status {
+	switch state {
+	case probePass:
+		return StatusPass
+	case probeDegraded:
+		return StatusWarn
+	default:
+		return StatusFail
+	}
+}
+
+func (s *basicHandler) Check(ctx context.Context) (Status, map[string]Result, error) {
+	return s.check(ctx, true)
+}
+
+func (s *basicHandler) CheckLiveness(ctx context.Context) (Status, map[string]Result, error) {
+	return s.check(ctx, false)
+}
+
+// check evaluates the readiness or liveness probe, giving up when ctx is done.
+// The checks don't take a context, so the abandoned evaluation completes in background.
+func (s *basicHandler) check(ctx context.Context, readiness bool) (Status, map[string]Result, error) {
+	type evaluation struct {
+		state   probeState
+		results map[string]Result
+	}
+
+	done := make(chan evaluation, 1)
+	go func() {
+		e := evaluation{results: make(map[string]Result)}
+		if readiness {
+			e.state = s.readinessOverride.applyState(s.evaluateState(e.results, true, s.readinessProbe...))
+		} else {
+			e.state = s.evaluateState(e.results, false, s.livenessChecks)
+		}
+		done <- e
+	}()
+
+	select {
+	case <-ctx.Done():
+		return StatusFail, nil, ctx.Err()
+	case e := <-done:
+		return statusOf(e.state), e.results, nil
+	}
+}
+
+func (s *basicHandler) RunCheck(ctx context.Context, name string) (Result, error) {
+	s.checksMutex.RLock()
+	entry, ok := s.readinessChecks[name]
+	if !ok {
+		entry, ok = s.livenessChecks[name]
+	}
+	s.checksMutex.RUnlock()
+	if !ok {
+		return Result{}, ErrCheckNotFound
+	}
+
+	done := make(chan Result, 1)
+	go func() {
+		res := s.runCheck(entry)
+		if entry.background != nil {
+			entry.background.setResult(res, s.clock.Now())
+		}
+		done <- res
+	}()
+
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	case res := <-done:
+		return res, nil
+	}
+}
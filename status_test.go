@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHandlerCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		live         error
+		ready        error
+		expectLive   Status
+		expectStatus Status
+	}{
+		{
+			name:         "all checks pass",
+			expectLive:   StatusPass,
+			expectStatus: StatusPass,
+		},
+		{
+			name:         "readiness check fails",
+			ready:        errors.New("failed"),
+			expectLive:   StatusPass,
+			expectStatus: StatusFail,
+		},
+		{
+			name:         "liveness check fails",
+			live:         errors.New("failed"),
+			expectLive:   StatusFail,
+			expectStatus: StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler()
+			h.AddLivenessCheck("live", func() error { return tt.live })
+			h.AddReadinessCheck("ready", func() error { return tt.ready })
+
+			status, results, err := h.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if status != tt.expectStatus {
+				t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", tt.expectStatus, status)
+			}
+			if len(results) != 2 || results["ready"].Err != tt.ready {
+				t.Errorf("Wrong results\n"+"actual  : %v", results)
+			}
+
+			status, _, err = h.CheckLiveness(context.Background())
+			if err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			if status != tt.expectLive {
+				t.Errorf("Wrong liveness status\n"+"expected: %v\n"+"actual  : %v", tt.expectLive, status)
+			}
+		})
+	}
+}
+
+func TestHandlerCheckCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	h := NewHandler()
+	h.AddReadinessCheck("slow", func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	status, _, err := h.Check(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", context.Canceled, err)
+	}
+	if status != StatusFail {
+		t.Errorf("Wrong status\n"+"expected: %v\n"+"actual  : %v", StatusFail, status)
+	}
+}
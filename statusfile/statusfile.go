@@ -0,0 +1,152 @@
+package statusfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/probe"
+)
+
+const (
+	// LiveFileName is the name of the liveness status file.
+	LiveFileName = "live"
+	// ReadyFileName is the name of the readiness status file.
+	ReadyFileName = "ready"
+
+	// DefaultInterval is the default interval between writes.
+	DefaultInterval = 10 * time.Second
+)
+
+// Writer periodically writes the liveness and readiness status of the handler to
+// files, so exec probes or local jobs can consume it without any network call
+// (e.g. in scratch containers without curl). Each file starts with a line
+// "pass <RFC 3339 timestamp>" or "fail <RFC 3339 timestamp>", followed by
+// the failed checks if any. A Kubernetes exec probe can be as simple as:
+//
+//	grep -q '^pass' /tmp/health/ready
+//
+// The files are replaced atomically, so their modification time tells
+// when the status was written last (to detect a stuck writer). A file which
+// can't be replaced is removed, so a stale "pass" is never read.
+type Writer struct {
+	handler  healthcheck.Prober
+	dir      string
+	interval time.Duration
+	onError  func(error)
+}
+
+// Option configures a Writer.
+type Option func(w *Writer)
+
+// WithInterval sets the interval between writes, DefaultInterval by default.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Writer) {
+		w.interval = interval
+	}
+}
+
+// WithErrorHandler sets the func receiving the errors of the writes made by Run.
+func WithErrorHandler(onError func(error)) Option {
+	return func(w *Writer) {
+		w.onError = onError
+	}
+}
+
+// NewWriter creates a Writer writing the state of the handler to the files in dir.
+func NewWriter(h healthcheck.Prober, dir string, opts ...Option) *Writer {
+	w := &Writer{
+		handler:  h,
+		dir:      dir,
+		interval: DefaultInterval,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run writes the status every interval until the context is done,
+// then marks both probes as failed so the stopped process isn't considered healthy.
+// Failed writes are reported to the error handler and retried on the next interval,
+// only the error of creating dir or of marking the probes as failed is returned.
+func (w *Writer) Run(ctx context.Context) error {
+	if err := os.MkdirAll(w.dir, 0o755); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Write(); err != nil && w.onError != nil {
+			w.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			now := time.Now()
+			return errors.Join(
+				w.writeFile(LiveFileName, false, "stopped", now),
+				w.writeFile(ReadyFileName, false, "stopped", now),
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Write evaluates the probes and writes their status once.
+func (w *Writer) Write() error {
+	now := time.Now()
+
+	live := probe.Live(w.handler)
+	liveErr := w.writeFile(LiveFileName, live.Healthy(), live.Summary(), now)
+
+	ready := probe.Ready(w.handler)
+	readyErr := w.writeFile(ReadyFileName, ready.Healthy(), ready.Summary(), now)
+
+	return errors.Join(liveErr, readyErr)
+}
+
+// writeFile atomically replaces the status file, removing it if it can't be replaced.
+func (w *Writer) writeFile(name string, healthy bool, details string, now time.Time) error {
+	err := w.replaceFile(name, healthy, details, now)
+	if err != nil {
+		if removeErr := os.Remove(filepath.Join(w.dir, name)); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			err = errors.Join(err, removeErr)
+		}
+	}
+	return err
+}
+
+// replaceFile atomically replaces the status file.
+func (w *Writer) replaceFile(name string, healthy bool, details string, now time.Time) error {
+	status := "fail"
+	if healthy {
+		status = "pass"
+	}
+	content := fmt.Sprintf("%s %s\n%s\n", status, now.UTC().Format(time.RFC3339), details)
+
+	tmp, err := os.CreateTemp(w.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	if _, err = tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(w.dir, name))
+}
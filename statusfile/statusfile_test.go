@@ -0,0 +1,116 @@
+package statusfile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       healthcheck.Check
+		expectReady string
+	}{
+		{
+			name:        "ready",
+			check:       func() error { return nil },
+			expectReady: "pass ",
+		},
+		{
+			name:        "unready",
+			check:       func() error { return errors.New("connection refused") },
+			expectReady: "fail ",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := healthcheck.NewHandler()
+			h.AddReadinessCheck("database", tt.check)
+
+			dir := t.TempDir()
+			if err := NewWriter(h, dir).Write(); err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+
+			if live := readFile(t, dir, LiveFileName); !strings.HasPrefix(live, "pass ") {
+				t.Errorf("Wrong liveness file\n"+"expected: %v\n"+"actual  : %v", "pass <timestamp>", live)
+			}
+			if ready := readFile(t, dir, ReadyFileName); !strings.HasPrefix(ready, tt.expectReady) {
+				t.Errorf("Wrong readiness file\n"+"expected: %v\n"+"actual  : %v", tt.expectReady+"<timestamp>", ready)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(healthcheck.NewHandler(), dir)
+	if err := w.Write(); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	// a directory in place of the file makes the replacement fail
+	ready := filepath.Join(dir, ReadyFileName)
+	if err := os.Remove(ready); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	if err := os.Mkdir(ready, 0o755); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	if err := w.Write(); err == nil {
+		t.Errorf("Expected an error replacing the readiness file")
+	}
+	if _, err := os.Stat(ready); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Wrong readiness file state\n"+"expected: %v\n"+"actual  : %v", os.ErrNotExist, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ReadyFileName), 0o755); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	var errs []error
+	w := NewWriter(healthcheck.NewHandler(), dir, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	// the first write fails, Run keeps going until the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Run(ctx); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	if len(errs) != 1 {
+		t.Errorf("Wrong number of reported errors\n"+"expected: %v\n"+"actual  : %v", 1, len(errs))
+	}
+	for _, name := range []string{LiveFileName, ReadyFileName} {
+		if content := readFile(t, dir, name); !strings.HasPrefix(content, "fail ") || !strings.HasSuffix(content, "\nstopped\n") {
+			t.Errorf("Wrong %s file\n"+"expected: %v\n"+"actual  : %v", name, "fail <timestamp>\nstopped", content)
+		}
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	return string(content)
+}
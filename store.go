@@ -0,0 +1,343 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsStoreErrorName is the name passed to the error handler
+// when the statistics can't be recorded or loaded.
+const statsStoreErrorName = "stats_store"
+
+// statsStoreTimeout bounds the calls to the StatsStore,
+// so a slow store never blocks the checks or the stats endpoint for long.
+const statsStoreTimeout = 2 * time.Second
+
+// DefaultFlushInterval is the default interval between the writes of a FileStatsStore.
+const DefaultFlushInterval = 10 * time.Second
+
+// StatsBucket counts the executions of a check in a slice of its SLO window:
+// a window is tracked in 60 buckets, whatever the frequency of the executions.
+type StatsBucket struct {
+	// Start is the start of the slice of the window.
+	Start time.Time `json:"start"`
+	// Executions is the number of executions of the check in the slice.
+	Executions int `json:"executions"`
+	// Failures is the number of failed executions of the check in the slice.
+	Failures int `json:"failures"`
+}
+
+// Sample is the numeric details observed by an execution of a check (see Result.Metrics).
+type Sample struct {
+	Time    time.Time          `json:"time"`
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+}
+
+// StatsStore stores the statistics the availability (WithSLO) and the history
+// (WithHistory) of the checks are computed from. A store shared by the replicas
+// of a service (see the redisstats package) aggregates their executions.
+type StatsStore interface {
+	// AddExecution counts an execution of the check in the bucket starting at start,
+	// and drops the buckets of the check which started a window or more before.
+	AddExecution(ctx context.Context, check string, start time.Time, window time.Duration, failed bool) error
+	// Buckets returns the buckets of the check started since the time.
+	Buckets(ctx context.Context, check string, since time.Time) ([]StatsBucket, error)
+	// AddSample stores the sample of the check, keeping only the last size ones.
+	AddSample(ctx context.Context, check string, sample Sample, size int) error
+	// Samples returns the kept samples of the check, oldest first.
+	Samples(ctx context.Context, check string) ([]Sample, error)
+}
+
+// WithStatsStore keeps the statistics of the checks with an SLO or a history
+// in the store instead of the memory of the handler, so they survive restarts
+// (FileStatsStore) or are shared across replicas. Record and load errors are
+// passed to the error handler as "stats_store".
+func WithStatsStore(store StatsStore) Option {
+	return func(h *basicHandler) {
+		h.statsStore = store
+	}
+}
+
+// MemoryStatsStore is a StatsStore keeping the statistics in memory.
+type MemoryStatsStore struct {
+	mu     sync.RWMutex
+	checks map[string]*storedCheck
+}
+
+// storedCheck is the statistics of a check kept by a MemoryStatsStore.
+type storedCheck struct {
+	Buckets []StatsBucket `json:"buckets,omitempty"`
+	Samples []Sample      `json:"samples,omitempty"`
+}
+
+// NewMemoryStatsStore returns an empty MemoryStatsStore.
+func NewMemoryStatsStore() *MemoryStatsStore {
+	return &MemoryStatsStore{checks: make(map[string]*storedCheck)}
+}
+
+func (m *MemoryStatsStore) check(name string) *storedCheck {
+	c, ok := m.checks[name]
+	if !ok {
+		c = &storedCheck{}
+		m.checks[name] = c
+	}
+	return c
+}
+
+// AddExecution implements StatsStore.
+func (m *MemoryStatsStore) AddExecution(_ context.Context, check string, start time.Time, window time.Duration, failed bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.check(check)
+	buckets := c.Buckets[:0]
+	var current *StatsBucket
+	for _, b := range c.Buckets {
+		if start.Sub(b.Start) >= window {
+			continue
+		}
+		buckets = append(buckets, b)
+		if b.Start.Equal(start) {
+			current = &buckets[len(buckets)-1]
+		}
+	}
+	if current == nil {
+		buckets = append(buckets, StatsBucket{Start: start})
+		current = &buckets[len(buckets)-1]
+	}
+	current.Executions++
+	if failed {
+		current.Failures++
+	}
+	c.Buckets = buckets
+	return nil
+}
+
+// Buckets implements StatsStore.
+func (m *MemoryStatsStore) Buckets(_ context.Context, check string, since time.Time) ([]StatsBucket, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var buckets []StatsBucket
+	if c, ok := m.checks[check]; ok {
+		for _, b := range c.Buckets {
+			if !b.Start.Before(since) {
+				buckets = append(buckets, b)
+			}
+		}
+	}
+	return buckets, nil
+}
+
+// AddSample implements StatsStore.
+func (m *MemoryStatsStore) AddSample(_ context.Context, check string, sample Sample, size int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.check(check)
+	samples := append(c.Samples, sample)
+	if len(samples) > size {
+		// copy rather than reslice, so the evicted samples are released
+		samples = append([]Sample(nil), samples[len(samples)-size:]...)
+	}
+	c.Samples = samples
+	return nil
+}
+
+// Samples implements StatsStore.
+func (m *MemoryStatsStore) Samples(_ context.Context, check string) ([]Sample, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if c, ok := m.checks[check]; ok {
+		return append([]Sample(nil), c.Samples...), nil
+	}
+	return nil, nil
+}
+
+// FileStatsStore is a StatsStore keeping the statistics in memory and saving
+// them to a file in background, so they survive restarts: the file is written
+// at most once per flush interval, never on the path of the checks.
+type FileStatsStore struct {
+	*MemoryStatsStore
+
+	path     string
+	interval time.Duration
+	onError  func(error)
+
+	mu      sync.Mutex
+	pending *time.Timer
+	closed  bool
+}
+
+// NewFileStatsStore returns a store restoring the statistics from the file at path,
+// a missing file isn't an error, and saving them at most once per interval
+// (DefaultFlushInterval if not positive). Close saves the last statistics.
+func NewFileStatsStore(path string, interval time.Duration) (*FileStatsStore, error) {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	s := &FileStatsStore{MemoryStatsStore: NewMemoryStatsStore(), path: path, interval: interval}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &s.checks); err != nil {
+			return nil, err
+		}
+		if s.checks == nil {
+			s.checks = make(map[string]*storedCheck)
+		}
+	}
+	return s, nil
+}
+
+// OnError sets the func receiving the errors of the background writes.
+func (s *FileStatsStore) OnError(onError func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = onError
+}
+
+// AddExecution implements StatsStore.
+func (s *FileStatsStore) AddExecution(ctx context.Context, check string, start time.Time, window time.Duration, failed bool) error {
+	err := s.MemoryStatsStore.AddExecution(ctx, check, start, window, failed)
+	s.scheduleFlush()
+	return err
+}
+
+// AddSample implements StatsStore.
+func (s *FileStatsStore) AddSample(ctx context.Context, check string, sample Sample, size int) error {
+	err := s.MemoryStatsStore.AddSample(ctx, check, sample, size)
+	s.scheduleFlush()
+	return err
+}
+
+// scheduleFlush saves the statistics after the interval, unless already scheduled.
+func (s *FileStatsStore) scheduleFlush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil && !s.closed {
+		s.pending = time.AfterFunc(s.interval, func() {
+			s.mu.Lock()
+			s.pending = nil
+			onError := s.onError
+			s.mu.Unlock()
+
+			if err := s.Flush(); err != nil && onError != nil {
+				onError(err)
+			}
+		})
+	}
+}
+
+// Flush saves the statistics to the file.
+func (s *FileStatsStore) Flush() error {
+	s.MemoryStatsStore.mu.RLock()
+	data, err := json.Marshal(s.checks)
+	s.MemoryStatsStore.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// Close stops the background writes and saves the statistics.
+func (s *FileStatsStore) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	if s.pending != nil {
+		s.pending.Stop()
+		s.pending = nil
+	}
+	s.mu.Unlock()
+
+	return s.Flush()
+}
+
+// recordStats records the execution of the check if it has an SLO or a history.
+func (s *basicHandler) recordStats(entry *checkEntry, res Result) {
+	if s.statsStore == nil {
+		s.recordSLO(entry, res)
+		s.recordHistory(entry, res)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, statsStoreTimeout)
+	defer cancel()
+
+	now := s.clock.Now()
+	if slo := entry.config.slo; slo != nil {
+		start := now.Truncate(sloBucketWidth(slo.window))
+		if err := s.statsStore.AddExecution(ctx, entry.name, start, slo.window, res.Err != nil); err != nil {
+			s.statsStoreError(err)
+		}
+	}
+	if size := entry.config.historySize; size > 0 {
+		sample := Sample{Time: now, Metrics: res.Metrics()}
+		if len(sample.Metrics) == 0 {
+			sample.Metrics = nil
+		}
+		if err := s.statsStore.AddSample(ctx, entry.name, sample, size); err != nil {
+			s.statsStoreError(err)
+		}
+	}
+}
+
+// storedStats returns the statistics of the check computed from the store,
+// ok is false if they can't be loaded.
+func (s *basicHandler) storedStats(entry *checkEntry, now time.Time) (st CheckStats, ok bool) {
+	ctx, cancel := context.WithTimeout(s.ctx, statsStoreTimeout)
+	defer cancel()
+
+	if slo := entry.config.slo; slo != nil {
+		// the buckets are counted as by the memory of the handler
+		buckets, err := s.statsStore.Buckets(ctx, entry.name, now.Add(-slo.window))
+		if err != nil {
+			s.statsStoreError(err)
+			return st, false
+		}
+
+		var executions, failures int
+		for _, b := range buckets {
+			if now.Sub(b.Start) < slo.window {
+				executions += b.Executions
+				failures += b.Failures
+			}
+		}
+		stats := sloStatsOf(*slo, executions, failures)
+		st.SLOStats = &stats
+	}
+
+	if entry.config.historySize > 0 {
+		samples, err := s.statsStore.Samples(ctx, entry.name)
+		if err != nil {
+			s.statsStoreError(err)
+			return st, false
+		}
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+
+		st.History = make(map[string][]Observation)
+		for _, sample := range samples {
+			for key, value := range sample.Metrics {
+				st.History[key] = append(st.History[key], Observation{Time: sample.Time, Value: value})
+			}
+		}
+	}
+	return st, true
+}
+
+func (s *basicHandler) statsStoreError(err error) {
+	if s.errorHandler != nil {
+		s.errorHandler(statsStoreErrorName, err)
+	}
+}
@@ -0,0 +1,127 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemoryStatsStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStatsStore()
+
+	for i := 0; i < 5; i++ {
+		_ = store.AddExecution(ctx, "db", start.Add(time.Duration(i)*time.Minute), 3*time.Minute, i == 4)
+		_ = store.AddExecution(ctx, "db", start.Add(time.Duration(i)*time.Minute), 3*time.Minute, false)
+		_ = store.AddSample(ctx, "db", Sample{Time: start.Add(time.Duration(i) * time.Minute)}, 2)
+	}
+
+	buckets, _ := store.Buckets(ctx, "db", start.Add(3*time.Minute))
+	expect := []StatsBucket{
+		{Start: start.Add(3 * time.Minute), Executions: 2},
+		{Start: start.Add(4 * time.Minute), Executions: 2, Failures: 1},
+	}
+	if !reflect.DeepEqual(expect, buckets) {
+		t.Errorf("Wrong buckets\n"+"expected: %+v\n"+"actual  : %+v", expect, buckets)
+	}
+
+	// the buckets out of the window are dropped
+	if all, _ := store.Buckets(ctx, "db", time.Time{}); len(all) != 3 {
+		t.Errorf("Wrong number of kept buckets\n"+"expected: %v\n"+"actual  : %v", 3, len(all))
+	}
+	if samples, _ := store.Samples(ctx, "db"); len(samples) != 2 || !samples[1].Time.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("Wrong samples: %+v", samples)
+	}
+}
+
+func TestFileStatsStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "stats.json")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := NewFileStatsStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	for i := 0; i < 10; i++ {
+		_ = store.AddExecution(ctx, "db", start, time.Hour, i%2 == 0)
+		_ = store.AddSample(ctx, "db", Sample{Time: start, Metrics: map[string]float64{"lag": float64(i)}}, 1)
+	}
+
+	// the writes are deferred to the flush interval
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Unexpected write before the flush interval: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+
+	restored, err := NewFileStatsStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	buckets, _ := restored.Buckets(ctx, "db", time.Time{})
+	if expect := []StatsBucket{{Start: start, Executions: 10, Failures: 5}}; !reflect.DeepEqual(expect, buckets) {
+		t.Errorf("Wrong restored buckets\n"+"expected: %+v\n"+"actual  : %+v", expect, buckets)
+	}
+	samples, _ := restored.Samples(ctx, "db")
+	if expect := []Sample{{Time: start, Metrics: map[string]float64{"lag": 9}}}; !reflect.DeepEqual(expect, samples) {
+		t.Errorf("Wrong restored samples\n"+"expected: %+v\n"+"actual  : %+v", expect, samples)
+	}
+}
+
+func TestWithStatsStore(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	store := NewMemoryStatsStore()
+
+	// two replicas sharing the store
+	replicas := []Handler{
+		NewHandler(WithClock(clock), WithStatsStore(store)),
+		NewHandler(WithClock(clock), WithStatsStore(store)),
+	}
+	replicas[0].AddReadinessCheck("db", func() error { return nil }, WithSLO(0.9, 24*time.Hour), WithHistory(10))
+	replicas[1].AddReadinessCheck("db", func() error { return errors.New("refused") }, WithSLO(0.9, 24*time.Hour), WithHistory(10))
+
+	// a day of executions every second is kept in 60 buckets
+	for i := 0; i < 24*60*60; i += 30 {
+		for _, h := range replicas {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ReadinessHandlerPath, nil))
+		}
+		clock.Advance(30 * time.Second)
+	}
+	clock.Advance(-time.Second)
+
+	rr := httptest.NewRecorder()
+	replicas[0].ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatsHandlerPath, nil))
+
+	var stats map[string]CheckStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	st, ok := stats["db"]
+	if !ok || st.SLOStats == nil {
+		t.Fatalf("Missing stats of the check: %s", rr.Body.String())
+	}
+	if st.Executions != 2*24*60*2 || st.Failures != 24*60*2 || st.Availability != 0.5 {
+		t.Errorf("Wrong stats\n"+"expected: %v/%v %v\n"+"actual  : %v/%v %v",
+			2*24*60*2, 24*60*2, 0.5, st.Executions, st.Failures, st.Availability)
+	}
+	if all, _ := store.Buckets(context.Background(), "db", time.Time{}); len(all) > sloBuckets {
+		t.Errorf("Too many buckets kept: %v", len(all))
+	}
+}
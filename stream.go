@@ -0,0 +1,108 @@
+package healthcheck
+
+import (
+	"bufio"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+)
+
+// writeResults streams the full output of the results as a JSON object sorted
+// by check name, one check at a time instead of building the whole document
+// in memory. The output is indented by 4 spaces unless compact is set.
+func (s *basicHandler) writeResults(w *bufio.Writer, results map[string]Result, compact, timing bool) error {
+	if len(results) == 0 {
+		_, err := w.Write(emptyBody)
+		return err
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	open, separator, colon, closing := "{\n    ", ",\n    ", ": ", "\n}\n"
+	if compact {
+		open, separator, colon, closing = "{", ",", ":", "}\n"
+	}
+
+	_, _ = w.WriteString(open)
+	for i, name := range names {
+		if i > 0 {
+			_, _ = w.WriteString(separator)
+		}
+		writeJSONString(w, name)
+		_, _ = w.WriteString(colon)
+
+		output := s.resultOutput(name, results[name], timing)
+		if status, ok := output.(string); ok {
+			writeJSONString(w, status)
+			continue
+		}
+
+		var (
+			value []byte
+			err   error
+		)
+		if compact {
+			value, err = json.Marshal(output)
+		} else {
+			value, err = json.MarshalIndent(output, "    ", "    ")
+		}
+		if err != nil {
+			return err
+		}
+		_, _ = w.Write(value)
+	}
+	_, err := w.WriteString(closing)
+	return err
+}
+
+const hex = "0123456789abcdef"
+
+// writeJSONString writes s as a JSON string, escaped the same way as encoding/json
+// (including the HTML characters), without allocating.
+func writeJSONString(w *bufio.Writer, s string) {
+	_ = w.WriteByte('"')
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				_ = w.WriteByte('\\')
+				_ = w.WriteByte(c)
+			case c == '\n':
+				_, _ = w.WriteString(`\n`)
+			case c == '\r':
+				_, _ = w.WriteString(`\r`)
+			case c == '\t':
+				_, _ = w.WriteString(`\t`)
+			case c == '\b':
+				_, _ = w.WriteString(`\b`)
+			case c == '\f':
+				_, _ = w.WriteString(`\f`)
+			case c < 0x20 || c == '<' || c == '>' || c == '&':
+				_, _ = w.WriteString(`\u00`)
+				_ = w.WriteByte(hex[c>>4])
+				_ = w.WriteByte(hex[c&0xF])
+			default:
+				_ = w.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			_, _ = w.WriteRune(utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			_, _ = w.WriteString(`\u202`)
+			_ = w.WriteByte(hex[r&0xF])
+		default:
+			_, _ = w.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	_ = w.WriteByte('"')
+}
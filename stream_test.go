@@ -0,0 +1,30 @@
+package healthcheck
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteJSONString(t *testing.T) {
+	tests := []string{
+		"OK",
+		"dial tcp: \"db\" <timeout> & retry",
+		"line\nbreak\ttab\r\b\f\x01\\",
+		"unicode: é 日本   ",
+		"invalid: \xff\xfe",
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		writeJSONString(w, tt)
+		_ = w.Flush()
+
+		expect, _ := json.Marshal(tt)
+		if buf.String() != string(expect) {
+			t.Errorf("Wrong encoding\n"+"expected: %s\n"+"actual  : %s", expect, buf.String())
+		}
+	}
+}
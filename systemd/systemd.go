@@ -0,0 +1,157 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+	"github.com/catalystgo/healthcheck/internal/probe"
+)
+
+// DefaultReadyInterval is the default interval of readiness evaluations
+// until the service is reported ready.
+const DefaultReadyInterval = time.Second
+
+// ErrNotifySocketUnset is returned when the process isn't run by systemd
+// with notification support (NOTIFY_SOCKET is unset).
+var ErrNotifySocketUnset = errors.New("NOTIFY_SOCKET is not set")
+
+// Notifier reports the handler state to systemd: it sends READY=1 once readiness
+// passes for the first time and, if the unit has WatchdogSec set, WATCHDOG=1 pings
+// while liveness is healthy, so systemd restarts a hung service.
+type Notifier struct {
+	handler       healthcheck.Prober
+	socket        string
+	readyInterval time.Duration
+	watchdog      time.Duration
+}
+
+// Option configures a Notifier.
+type Option func(n *Notifier)
+
+// WithReadyInterval sets the interval of readiness evaluations until
+// the service is ready, DefaultReadyInterval by default.
+func WithReadyInterval(interval time.Duration) Option {
+	return func(n *Notifier) {
+		n.readyInterval = interval
+	}
+}
+
+// WithWatchdogInterval sets the interval of the watchdog pings, half of
+// WATCHDOG_USEC by default. Zero disables the pings.
+func WithWatchdogInterval(interval time.Duration) Option {
+	return func(n *Notifier) {
+		n.watchdog = interval
+	}
+}
+
+// NewNotifier creates a Notifier reporting the state of the handler to the socket
+// from NOTIFY_SOCKET with the watchdog interval derived from WATCHDOG_USEC.
+func NewNotifier(h healthcheck.Prober, opts ...Option) *Notifier {
+	n := &Notifier{
+		handler:       h,
+		socket:        os.Getenv("NOTIFY_SOCKET"),
+		readyInterval: DefaultReadyInterval,
+		watchdog:      watchdogInterval(),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Run waits for readiness and sends the watchdog pings until the context is done,
+// then reports STOPPING=1. It returns ErrNotifySocketUnset if NOTIFY_SOCKET is unset.
+func (n *Notifier) Run(ctx context.Context) error {
+	if n.socket == "" {
+		return ErrNotifySocketUnset
+	}
+	defer func() { _ = n.Notify("STOPPING=1") }()
+
+	if err := n.waitReady(ctx); err != nil {
+		return err
+	}
+
+	if n.watchdog <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(n.watchdog)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// a missed ping makes systemd restart the service
+		live := probe.Live(n.handler)
+		if live.Healthy() {
+			if err := n.Notify("WATCHDOG=1"); err != nil {
+				return err
+			}
+		} else {
+			_ = n.Notify("STATUS=liveness failed: " + live.Summary())
+		}
+	}
+}
+
+func (n *Notifier) waitReady(ctx context.Context) error {
+	ticker := time.NewTicker(n.readyInterval)
+	defer ticker.Stop()
+
+	for {
+		if probe.Ready(n.handler).Healthy() {
+			return n.Notify("READY=1\nSTATUS=ready")
+		}
+
+		// keep the watchdog happy while starting up, as long as the service is alive
+		if n.watchdog > 0 && probe.Live(n.handler).Healthy() {
+			_ = n.Notify("WATCHDOG=1")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Notify sends the state (e.g. "READY=1") to the systemd notification socket.
+func (n *Notifier) Notify(state string) error {
+	if n.socket == "" {
+		return ErrNotifySocketUnset
+	}
+
+	// net handles the "@" prefix of abstract sockets
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval returns half of the watchdog timeout requested by systemd
+// for this process, zero if the watchdog isn't enabled.
+func watchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
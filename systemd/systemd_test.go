@@ -0,0 +1,145 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/catalystgo/healthcheck"
+)
+
+// listenNotify listens on a notification socket in a temporary directory
+// and points NOTIFY_SOCKET to it.
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+// readNotify reads the next state sent to the notification socket.
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		usec   string
+		pid    string
+		expect time.Duration
+	}{
+		{name: "unset"},
+		{name: "invalid", usec: "soon"},
+		{name: "set", usec: "30000000", expect: 15 * time.Second},
+		{name: "set for this process", usec: "30000000", pid: strconv.Itoa(os.Getpid()), expect: 15 * time.Second},
+		{name: "set for another process", usec: "30000000", pid: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+
+			if interval := NewNotifier(healthcheck.NewHandler()).watchdog; interval != tt.expect {
+				t.Errorf("Wrong watchdog interval\n"+"expected: %v\n"+"actual  : %v", tt.expect, interval)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	conn := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+	t.Setenv("WATCHDOG_PID", "")
+
+	ready := make(chan struct{})
+	h := healthcheck.NewHandler()
+	h.AddReadinessCheck("database", func() error {
+		select {
+		case <-ready:
+			return nil
+		default:
+			return errors.New("connection refused")
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewNotifier(h, WithReadyInterval(time.Millisecond)).Run(ctx) }()
+
+	// the watchdog is pinged while starting up
+	if state := readNotify(t, conn); state != "WATCHDOG=1" {
+		t.Errorf("Wrong state\n"+"expected: %v\n"+"actual  : %v", "WATCHDOG=1", state)
+	}
+
+	close(ready)
+	for state := readNotify(t, conn); state != "READY=1\nSTATUS=ready"; state = readNotify(t, conn) {
+		if state != "WATCHDOG=1" {
+			t.Fatalf("Wrong state\n"+"expected: %v\n"+"actual  : %v", "READY=1\nSTATUS=ready", state)
+		}
+	}
+	if state := readNotify(t, conn); state != "WATCHDOG=1" {
+		t.Errorf("Wrong state\n"+"expected: %v\n"+"actual  : %v", "WATCHDOG=1", state)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+	for state := readNotify(t, conn); state != "STOPPING=1"; state = readNotify(t, conn) {
+		if state != "WATCHDOG=1" {
+			t.Fatalf("Wrong state\n"+"expected: %v\n"+"actual  : %v", "STOPPING=1", state)
+		}
+	}
+}
+
+func TestRunLivenessFailed(t *testing.T) {
+	conn := listenNotify(t)
+
+	h := healthcheck.NewHandler()
+	h.AddLivenessCheck("deadlock", func() error { return errors.New("stuck") }, healthcheck.WithCriticality(healthcheck.Informational))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = NewNotifier(h, WithWatchdogInterval(time.Millisecond)).Run(ctx) }()
+
+	if state := readNotify(t, conn); state != "READY=1\nSTATUS=ready" {
+		t.Fatalf("Wrong state\n"+"expected: %v\n"+"actual  : %v", "READY=1\nSTATUS=ready", state)
+	}
+	// no ping while liveness fails, so systemd restarts the service
+	if state, expect := readNotify(t, conn), "STATUS=liveness failed: deadlock: stuck"; state != expect {
+		t.Errorf("Wrong state\n"+"expected: %v\n"+"actual  : %v", expect, state)
+	}
+}
+
+func TestRunWithoutSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	err := NewNotifier(healthcheck.NewHandler()).Run(context.Background())
+	if !errors.Is(err, ErrNotifySocketUnset) {
+		t.Errorf("Wrong error\n"+"expected: %v\n"+"actual  : %v", ErrNotifySocketUnset, err)
+	}
+}
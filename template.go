@@ -0,0 +1,139 @@
+package healthcheck
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	texttemplate "text/template"
+	"time"
+)
+
+// TemplateData is the model the HTML and text templates are executed with.
+type TemplateData struct {
+	// Probe is "liveness" or "readiness".
+	Probe string
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Passed is true if the probe passes (possibly degraded).
+	Passed bool
+	// Time is the time of the evaluation.
+	Time time.Time
+	// Duration is the time it took to evaluate the probe.
+	Duration time.Duration
+	// Checks are the results of the checks sorted by name.
+	Checks []TemplateCheck
+}
+
+// TemplateCheck is the result of a check in TemplateData.
+type TemplateCheck struct {
+	// Name is the name of the check.
+	Name string
+	// Status is the status rendered in the full output: "OK" or the error message.
+	Status string
+	// Kind is the kind of outcome of the check.
+	Kind ResultKind
+	// Result is the raw result of the check.
+	Result Result
+}
+
+// defaultHTMLTemplate renders a minimal dashboard of the probe.
+var defaultHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Probe}}</title></head>
+<body>
+<h1>{{.Probe}}: {{if .Passed}}pass{{else}}fail{{end}}</h1>
+<table>
+<tr><th>Check</th><th>Kind</th><th>Status</th><th>Duration</th></tr>
+{{range .Checks}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{.Status}}</td><td>{{.Result.Duration}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// defaultTextTemplate renders the probe as plain text, one check per line.
+var defaultTextTemplate = texttemplate.Must(texttemplate.New("text").Parse(
+	`{{.Probe}}: {{if .Passed}}pass{{else}}fail{{end}}
+{{range .Checks}}{{.Name}}	{{.Kind}}	{{.Status}}
+{{end}}`))
+
+// WithHTMLTemplate sets the template of the HTML output of the probes, served
+// with ?format=html or Accept: text/html and executed with a TemplateData, e.g. to match an internal
+// branding. A minimal dashboard is rendered by default.
+func WithHTMLTemplate(tmpl *htmltemplate.Template) Option {
+	return func(h *basicHandler) {
+		h.htmlTemplate = tmpl
+	}
+}
+
+// WithTextTemplate sets the template of the plain text output of the probes,
+// served with ?format=text or Accept: text/plain and executed with a TemplateData, e.g. for the
+// tooling parsing it. A line per check is rendered by default.
+func WithTextTemplate(tmpl *texttemplate.Template) Option {
+	return func(h *basicHandler) {
+		h.textTemplate = tmpl
+	}
+}
+
+// outputTemplate is the part of the html and text templates used to render the output.
+type outputTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+// writeTemplate renders the results with the template of the format (formatHTML
+// or formatText) and returns true, or returns false if the format has no template.
+func (s *basicHandler) writeTemplate(
+	w http.ResponseWriter, format, probe string, status int, results map[string]Result, elapsed time.Duration,
+) bool {
+	var (
+		tmpl        outputTemplate
+		contentType string
+	)
+	switch format {
+	case formatHTML:
+		tmpl, contentType = defaultHTMLTemplate, "text/html; charset=utf-8"
+		if s.htmlTemplate != nil {
+			tmpl = s.htmlTemplate
+		}
+	case formatText:
+		tmpl, contentType = defaultTextTemplate, "text/plain; charset=utf-8"
+		if s.textTemplate != nil {
+			tmpl = s.textTemplate
+		}
+	default:
+		return false
+	}
+
+	data := TemplateData{
+		Probe:      probe,
+		StatusCode: status,
+		Passed:     status < http.StatusBadRequest,
+		Time:       s.clock.Now(),
+		Duration:   elapsed,
+		Checks:     make([]TemplateCheck, 0, len(results)),
+	}
+	for name, res := range results {
+		output := s.resultOutput(name, res, false)
+		if detailed, ok := output.(detailedOutput); ok {
+			output = detailed.Status
+		}
+		data.Checks = append(data.Checks, TemplateCheck{Name: name, Status: output.(string), Kind: res.Kind(), Result: res})
+	}
+	sort.Slice(data.Checks, func(i, j int) bool { return data.Checks[i].Name < data.Checks[j].Name })
+
+	// render first, a failing template is a 500 rather than a truncated body
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		http.Error(w, "template: "+err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set(DurationHeader, strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+	return true
+}
@@ -0,0 +1,82 @@
+package healthcheck
+
+import (
+	"errors"
+	htmltemplate "html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+)
+
+func TestTemplates(t *testing.T) {
+	custom := texttemplate.Must(texttemplate.New("nagios").Parse(
+		`{{if .Passed}}OK{{else}}CRITICAL{{end}} -{{range .Checks}} {{.Name}}={{.Kind}}{{end}}`))
+	brandedHTML := htmltemplate.Must(htmltemplate.New("branded").Parse(
+		`<h1 class="acme">{{.Probe}}</h1>{{range .Checks}}<p>{{.Status}}</p>{{end}}`))
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		target      string
+		contentType string
+		expect      string
+	}{
+		{
+			name:        "default text",
+			target:      ReadinessHandlerPath + "?format=text",
+			contentType: "text/plain; charset=utf-8",
+			expect:      "readiness: fail\ncache\tok\tOK\ndatabase\tfailed\t<refused>\n",
+		},
+		{
+			name:        "custom text",
+			opts:        []Option{WithTextTemplate(custom)},
+			target:      ReadinessHandlerPath + "?format=text",
+			contentType: "text/plain; charset=utf-8",
+			expect:      "CRITICAL - cache=ok database=failed",
+		},
+		{
+			name:        "custom html",
+			opts:        []Option{WithHTMLTemplate(brandedHTML)},
+			target:      ReadinessHandlerPath + "?format=html",
+			contentType: "text/html; charset=utf-8",
+			expect:      `<h1 class="acme">readiness</h1><p>OK</p><p>&lt;refused&gt;</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.opts...)
+			h.AddReadinessCheck("cache", func() error { return nil })
+			h.AddReadinessCheck("database", func() error { return errors.New("<refused>") })
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != http.StatusServiceUnavailable {
+				t.Errorf("Wrong code\n"+"expected: %v\n"+"actual  : %v", http.StatusServiceUnavailable, rr.Code)
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != tt.contentType {
+				t.Errorf("Wrong content type\n"+"expected: %v\n"+"actual  : %v", tt.contentType, contentType)
+			}
+			if rr.Body.String() != tt.expect {
+				t.Errorf("Wrong body\n"+"expected: %q\n"+"actual  : %q", tt.expect, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestDefaultHTMLTemplate(t *testing.T) {
+	h := NewHandler()
+	h.AddLivenessCheck("ping", func() error { return nil })
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, LivenessHandlerPath+"?format=html", nil))
+	if body := rr.Body.String(); !strings.Contains(body, "<h1>liveness: pass</h1>") || !strings.Contains(body, "<td>ping</td>") {
+		t.Errorf("Wrong dashboard:\n%s", body)
+	}
+}